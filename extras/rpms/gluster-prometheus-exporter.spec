@@ -47,6 +47,8 @@ BuildRequires: systemd
 BuildRequires: golang(github.com/BurntSushi/toml)
 BuildRequires: golang(github.com/sirupsen/logrus)
 BuildRequires: golang(github-prometheus-client_golang)
+BuildRequires: golang(golang.org/x/crypto/bcrypt)
+BuildRequires: golang(gopkg.in/yaml.v2)
 %endif
 
 Requires: glusterfs-server >= 3.12.0
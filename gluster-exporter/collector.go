@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gluster/gluster-prometheus/pkg/glusterutils"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	defaultScrapeTimeout = 10 * time.Second
+
+	errCollectorRunning = errors.New("previous collection is still running")
+	errCollectorTimeout = errors.New("collection timed out")
+)
+
+// run runs the metric function once and records the
+// duration and the result of the run
+func (m glusterMetric) run(ctx context.Context, gi glusterutils.GInterface) error {
+	start := time.Now()
+	err := m.fn(ctx, gi)
+	observeCollectorRun(m.name, time.Since(start), err)
+	return err
+}
+
+// scrapeCollector runs a glusterMetric when Prometheus scrapes the
+// exporter, and exports the collected values as const metrics
+type scrapeCollector struct {
+	metric  glusterMetric
+	gluster glusterutils.GInterface
+	timeout time.Duration
+	// running acts as a semaphore, only one collection
+	// of the metric can be in progress at a time
+	running chan struct{}
+}
+
+func newScrapeCollector(m glusterMetric, gi glusterutils.GInterface, timeout time.Duration) *scrapeCollector {
+	if timeout <= 0 {
+		timeout = defaultScrapeTimeout
+	}
+	for _, gaugeVec := range m.gaugeVecs {
+		gaugeVec.enableScrapeMode()
+	}
+	return &scrapeCollector{
+		metric:  m,
+		gluster: gi,
+		timeout: timeout,
+		running: make(chan struct{}, 1),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *scrapeCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, gaugeVec := range c.metric.gaugeVecs {
+		ch <- gaugeVec.Desc
+	}
+}
+
+// Collect implements prometheus.Collector. The metric function is
+// bounded by the scrape timeout, on timeout its gluster calls are
+// cancelled and it is left to finish in the background, the scrapes
+// arriving meanwhile are skipped
+func (c *scrapeCollector) Collect(ch chan<- prometheus.Metric) {
+	select {
+	case c.running <- struct{}{}:
+	default:
+		c.logError(errCollectorRunning)
+		return
+	}
+
+	for _, gaugeVec := range c.metric.gaugeVecs {
+		gaugeVec.resetSamples()
+	}
+
+	// the run is recorded here rather than by 'run', so that a run left
+	// to finish after the timeout is not recorded a second time
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- c.metric.fn(ctx, c.gluster)
+	}()
+
+	select {
+	case err := <-done:
+		observeCollectorRun(c.metric.name, time.Since(start), err)
+		if err != nil {
+			c.logError(err)
+		} else {
+			for _, gaugeVec := range c.metric.gaugeVecs {
+				gaugeVec.collectSamples(ch)
+			}
+		}
+		cancel()
+		<-c.running
+	case <-ctx.Done():
+		cancel()
+		c.logError(errCollectorTimeout)
+		observeCollectorRun(c.metric.name, c.timeout, errCollectorTimeout)
+		go func() {
+			<-done
+			<-c.running
+		}()
+	}
+}
+
+// wait waits for the run in progress, like one left to finish after a
+// timeout, and keeps the collector from running again. It is called
+// once the collector is unregistered, so that no run of the stopped
+// collector sets values after its GaugeVecs are reset or switched mode
+func (c *scrapeCollector) wait() {
+	c.running <- struct{}{}
+}
+
+func (c *scrapeCollector) logError(err error) {
+	log.WithError(err).WithFields(log.Fields{
+		"name": c.metric.name,
+	}).Debug("failed to export metric")
+}
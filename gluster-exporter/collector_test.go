@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gluster/gluster-prometheus/pkg/glusterutils"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// collectorValue returns the value of a collector metric of the collector
+func collectorValue(t *testing.T, c prometheus.Collector, name string) float64 {
+	var metric prometheus.Metric
+	switch vec := c.(type) {
+	case *prometheus.CounterVec:
+		metric = vec.WithLabelValues(name)
+	case *prometheus.GaugeVec:
+		metric = vec.WithLabelValues(name)
+	}
+	var m dto.Metric
+	if err := metric.Write(&m); err != nil {
+		t.Fatal(err)
+	}
+	if m.Counter != nil {
+		return m.Counter.GetValue()
+	}
+	return m.Gauge.GetValue()
+}
+
+func TestScrapeCollectorTimeout(t *testing.T) {
+	name := "test_slow_collector"
+	release := make(chan struct{})
+	finished := make(chan struct{})
+	m := glusterMetric{
+		name: name,
+		fn: func(ctx context.Context, gi glusterutils.GInterface) error {
+			defer close(finished)
+			// finishes successfully, but only after the scrape timed out
+			<-release
+			return nil
+		},
+		gaugeVecs: map[string]*ExportedGaugeVec{},
+	}
+	initCollectorMetrics(name)
+	c := newScrapeCollector(m, nil, 10*time.Millisecond)
+
+	ch := make(chan prometheus.Metric, 10)
+	c.Collect(ch)
+
+	// the collector is only stopped once the abandoned run finished
+	waited := make(chan struct{})
+	go func() {
+		c.wait()
+		close(waited)
+	}()
+	select {
+	case <-waited:
+		t.Fatal("expected the stop to wait for the abandoned run")
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(release)
+	<-finished
+	<-waited
+
+	if value := collectorValue(t, glusterExporterCollectorErrors, name); value != 1 {
+		t.Errorf("expected 1 error, got %v", value)
+	}
+	if value := collectorValue(t, glusterExporterCollectorTimeouts, name); value != 1 {
+		t.Errorf("expected 1 timeout, got %v", value)
+	}
+	if value := collectorValue(t, glusterExporterCollectorSuccess, name); value != 0 {
+		t.Errorf("expected the timed out run to be failed, got %v", value)
+	}
+	if value := collectorValue(t, glusterExporterCollectorLastSuccess, name); value != 0 {
+		t.Errorf("expected no successful run, got %v", value)
+	}
+}
@@ -2,14 +2,67 @@ package conf
 
 import (
 	"errors"
+	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
 	"strings"
 
 	"github.com/BurntSushi/toml"
 	"github.com/gluster/gluster-prometheus/pkg/glusterutils/glusterconsts"
+	log "github.com/sirupsen/logrus"
 )
 
+const (
+	// CollectorModeBackground runs the collector periodically in the
+	// background, and exports the last collected values
+	CollectorModeBackground = "background"
+	// CollectorModeScrape runs the collector when the metrics are scraped
+	CollectorModeScrape = "scrape"
+
+	// HealInfoModeFull gets the heal counts of the gluster_volume_heal
+	// collector from the full heal info, a crawl for each count
+	HealInfoModeFull = "full"
+	// HealInfoModeSummary gets all the heal counts of the
+	// gluster_volume_heal collector from a single heal info summary,
+	// it is the default
+	HealInfoModeSummary = "summary"
+
+	// DefaultPort is the port of the metrics, when not configured
+	DefaultPort = 9713
+	// DefaultMetricsPath is the path of the metrics, when not configured
+	DefaultMetricsPath = "/metrics"
+	// DefaultLogLevel is the log level, when not configured
+	DefaultLogLevel = "info"
+	// DefaultLogFile logs to stderr, when no log file is configured
+	DefaultLogFile = "stderr"
+
+	// maxClientLabelLimit and maxLogTopMsgIDs bound the no of series
+	// exported by the gluster_volume_clients and gluster_log collectors
+	maxClientLabelLimit = 10000
+	maxLogTopMsgIDs     = 1000
+)
+
+// collectorOptions are the collectors reading the collector specific
+// options, the other options of the collectors apply to all of them
+var collectorOptions = map[string]string{
+	"heal-info-mode":        "gluster_volume_heal",
+	"heal-index-scan-limit": "gluster_brick_heal",
+	"heal-index-scan-rate":  "gluster_brick_heal",
+	"client-label-limit":    "gluster_volume_clients",
+	"status-details":        "gluster_brick_stats",
+	"mount-timeout-in-sec":  "gluster_fuse",
+	"statedump-trigger":     "gluster_brick_statedump",
+	"gluster-log-dir":       "gluster_log",
+	"log-files":             "gluster_log",
+	"log-top-msgids":        "gluster_log",
+}
+
+// statusDetails are the volume status details of 'status-details'
+var statusDetails = []string{"mem", "inode", "fd", "callpool"}
+
 // GConfig represents Glusterd1/Glusterd2 configurations
 type GConfig struct {
 	GlusterMgmt         string `toml:"gluster-mgmt"`
@@ -26,15 +79,28 @@ type GConfig struct {
 	Timeout             int64
 }
 
+// Glusterd2Endpoints returns the glusterd2 endpoints, 'gd2-rest-endpoint'
+// and GD2_ENDPOINTS are lists separated by commas or spaces
+func (gConfig *GConfig) Glusterd2Endpoints() []string {
+	return strings.Fields(strings.Replace(gConfig.Glusterd2Endpoint, ",", " ", -1))
+}
+
 // Globals maintains the global system configurations
 type Globals struct {
+	ListenAddress     string   `toml:"listen-address"`
 	Port              int      `toml:"port"`
+	WebConfigFile     string   `toml:"web-config-file"`
 	MetricsPath       string   `toml:"metrics-path"`
 	LogDir            string   `toml:"log-dir"`
 	LogFile           string   `toml:"log-file"`
 	LogLevel          string   `toml:"log-level"`
 	CacheTTL          uint64   `toml:"cache-ttl-in-sec"`
 	CacheEnabledFuncs []string `toml:"cache-enabled-funcs"`
+	CollectorMode     string   `toml:"collector-mode"`
+	ScrapeTimeout     uint64   `toml:"scrape-timeout-in-sec"`
+	EventsPath        string   `toml:"events-path"`
+	EventsToken       string   `toml:"events-bearer-token"`
+	EventsSecret      string   `toml:"events-secret"`
 	*GConfig
 }
 
@@ -43,6 +109,26 @@ type Collectors struct {
 	Name         string `toml:"name"`
 	SyncInterval uint64 `toml:"sync-interval"`
 	Disabled     bool   `toml:"disabled"`
+	Mode         string `toml:"mode"`
+	// HealInfoMode is only used by the gluster_volume_heal collector
+	HealInfoMode string `toml:"heal-info-mode"`
+	// HealIndexScanLimit and HealIndexScanRate are only
+	// used by the gluster_brick_heal collector
+	HealIndexScanLimit uint64 `toml:"heal-index-scan-limit"`
+	HealIndexScanRate  uint64 `toml:"heal-index-scan-rate"`
+	// ClientLabelLimit is only used by the gluster_volume_clients collector
+	ClientLabelLimit int `toml:"client-label-limit"`
+	// StatusDetails is only used by the gluster_brick_stats collector
+	StatusDetails []string `toml:"status-details"`
+	// MountTimeout is only used by the gluster_fuse collector
+	MountTimeout uint64 `toml:"mount-timeout-in-sec"`
+	// StatedumpTrigger is only used by the gluster_brick_statedump collector
+	StatedumpTrigger bool `toml:"statedump-trigger"`
+	// GlusterLogDir, LogFiles and LogTopMsgIDs are
+	// only used by the gluster_log collector
+	GlusterLogDir string   `toml:"gluster-log-dir"`
+	LogFiles      []string `toml:"log-files"`
+	LogTopMsgIDs  int      `toml:"log-top-msgids"`
 }
 
 // Config struct defines overall configurations
@@ -50,6 +136,14 @@ type Collectors struct {
 type Config struct {
 	*Globals       `toml:"globals"`
 	CollectorsConf map[string]Collectors `toml:"collectors"`
+	// problems are the unknown keys of the configuration file and
+	// the invalid environment variables, reported by Validate
+	problems []string
+	// definedBy are the sources of the options set in the
+	// configuration file or by the environment variables
+	definedBy map[string]string
+	// envVars are the environment variables applied
+	envVars []string
 }
 
 // GConfig method helps 'Config' objects to implement 'GConfigInterface'
@@ -57,37 +151,202 @@ func (conf *Config) GConfig() *GConfig {
 	return conf.Globals.GConfig
 }
 
-// LoadConfig loads the configuration file
+// IsDefined returns true if the option is set in the configuration
+// file or by an environment variable, like
+// IsDefined("collectors", "gluster_ps", "disabled")
+func (conf *Config) IsDefined(key ...string) bool {
+	return conf.DefinedBy(key...) != ""
+}
+
+// DefinedBy returns the source of the option, 'the config file' or
+// the environment variable, empty if the option is not set
+func (conf *Config) DefinedBy(key ...string) string {
+	return conf.definedBy[strings.Join(key, ".")]
+}
+
+// EnvVars returns the environment variables applied to the configuration
+func (conf *Config) EnvVars() []string {
+	return conf.envVars
+}
+
+// LoadConfig loads the configuration file, and the options set by the
+// environment variables, which take precedence. Without a configuration
+// file, when the path is empty, only the environment variables are used
 func LoadConfig(confFilePath string) (conf *Config, err error) {
-	conf = &Config{}
-	if _, err = toml.DecodeFile(filepath.Clean(confFilePath), conf); err != nil {
-		conf = nil
-		return
+	conf = &Config{definedBy: make(map[string]string)}
+	if confFilePath != "" {
+		md, err := toml.DecodeFile(filepath.Clean(confFilePath), conf)
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range md.Undecoded() {
+			conf.problems = append(conf.problems, fmt.Sprintf("unknown option %q", key.String()))
+		}
+		for _, key := range md.Keys() {
+			conf.definedBy[key.String()] = "the config file"
+		}
 	}
-	// by default, use glusterd (that is; GD1)
-	if conf.GlusterMgmt == "" {
-		conf.GlusterMgmt = glusterconsts.MgmtGlusterd
+	// the client nodes need no gluster configurations
+	if conf.Globals == nil {
+		conf.Globals = &Globals{}
+	}
+	if conf.Globals.GConfig == nil {
+		conf.Globals.GConfig = &GConfig{}
 	}
 	// If GD2_ENDPOINTS env variable is set, use that info
 	// for making REST API calls
 	if endpoint := os.Getenv(glusterconsts.EnvGD2Endpoints); endpoint != "" {
 		conf.Glusterd2Endpoint = endpoint
 	}
-	// if there are multiple endpoints, get the first one
-	if endpoint := conf.Glusterd2Endpoint; endpoint != "" {
-		endpoint = strings.Replace(endpoint, ",", " ", -1)
-		endpoint = strings.Fields(endpoint)[0]
-		conf.Glusterd2Endpoint = endpoint
-	}
 	// if GLUSTER_CLUSTER_ID env variable is set, it gets the precedence
 	if gClusterID := os.Getenv(glusterconsts.EnvGlusterClusterID); gClusterID != "" {
 		conf.GlusterClusterID = gClusterID
 	}
+	// the GLUSTER_EXPORTER_* variables override all of the above
+	conf.applyEnv(os.Environ())
+
+	// by default, use glusterd (that is; GD1)
+	if conf.GlusterMgmt == "" {
+		conf.GlusterMgmt = glusterconsts.MgmtGlusterd
+	}
+	// by default, collectors run in the background
+	if conf.CollectorMode == "" {
+		conf.CollectorMode = CollectorModeBackground
+	}
+	if conf.Port == 0 {
+		conf.Port = DefaultPort
+	}
+	if conf.MetricsPath == "" {
+		conf.MetricsPath = DefaultMetricsPath
+	}
+	if conf.LogLevel == "" {
+		conf.LogLevel = DefaultLogLevel
+	}
+	if conf.LogFile == "" {
+		conf.LogFile = DefaultLogFile
+	}
 	// gluster cluster ID is still empty, put the default
 	if conf.GlusterClusterID == "" {
 		conf.GlusterClusterID = glusterconsts.DefaultGlusterClusterID
 	}
-	return
+	return conf, nil
+}
+
+// ValidationError lists all the problems found in the configuration
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration: " + strings.Join(e.Problems, "; ")
+}
+
+// Validate checks the configuration, the collectors are checked against
+// the registered collectors, and 'cache-enabled-funcs' against the
+// functions which can be cached. All the problems found are returned
+// at once, as a *ValidationError
+func (conf *Config) Validate(collectors []string, cacheFuncs []string) error {
+	var problems []string
+	addProblem := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	problems = append(problems, conf.problems...)
+	if conf.Port < 1 || conf.Port > 65535 {
+		addProblem("invalid port %d, must be between 1 and 65535", conf.Port)
+	}
+	if !strings.HasPrefix(conf.MetricsPath, "/") {
+		addProblem("invalid metrics-path %q, must start with '/'", conf.MetricsPath)
+	}
+	if conf.EventsPath != "" && !strings.HasPrefix(conf.EventsPath, "/") {
+		addProblem("invalid events-path %q, must start with '/'", conf.EventsPath)
+	}
+	if conf.EventsPath != "" && conf.EventsPath == conf.MetricsPath {
+		addProblem("events-path %q is the same as metrics-path", conf.EventsPath)
+	}
+	if _, err := log.ParseLevel(strings.ToLower(conf.LogLevel)); err != nil {
+		addProblem("invalid log-level %q", conf.LogLevel)
+	}
+	if conf.GlusterMgmt != glusterconsts.MgmtGlusterd && conf.GlusterMgmt != glusterconsts.MgmtGlusterd2 {
+		addProblem("invalid gluster-mgmt %q, must be %q or %q", conf.GlusterMgmt,
+			glusterconsts.MgmtGlusterd, glusterconsts.MgmtGlusterd2)
+	}
+	for _, endpoint := range conf.Glusterd2Endpoints() {
+		if u, err := url.Parse(endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			addProblem("invalid gd2-rest-endpoint %q, must be a http or https URL", endpoint)
+		}
+	}
+	for _, fName := range conf.CacheEnabledFuncs {
+		if !contains(cacheFuncs, fName) {
+			addProblem("invalid cache-enabled-funcs entry %q, supported functions are %s",
+				fName, strings.Join(cacheFuncs, ", "))
+		}
+	}
+	if !validCollectorMode(conf.CollectorMode) {
+		addProblem("invalid collector-mode %q", conf.CollectorMode)
+	}
+
+	names := make([]string, 0, len(conf.CollectorsConf))
+	for name := range conf.CollectorsConf {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		collector := conf.CollectorsConf[name]
+		if !contains(collectors, name) {
+			addProblem("unknown collector %q", name)
+			continue
+		}
+		if collector.Name != "" && collector.Name != name {
+			addProblem("name %q of the collector %s does not match its section", collector.Name, name)
+		}
+		if collector.Mode != "" && !validCollectorMode(collector.Mode) {
+			addProblem("invalid mode %q of the collector %s", collector.Mode, name)
+		}
+		if collector.HealInfoMode != "" && collector.HealInfoMode != HealInfoModeFull &&
+			collector.HealInfoMode != HealInfoModeSummary {
+			addProblem("invalid heal-info-mode %q of the collector %s", collector.HealInfoMode, name)
+		}
+		for _, opt := range options(reflect.ValueOf(&collector).Elem()) {
+			reader, ok := collectorOptions[opt.key]
+			isZero := reflect.DeepEqual(opt.value.Interface(), reflect.Zero(opt.value.Type()).Interface())
+			if ok && reader != name && (!isZero || conf.IsDefined("collectors", name, opt.key)) {
+				addProblem("option %s of the collector %s is only used by the collector %s", opt.key, name, reader)
+			}
+		}
+		if collector.ClientLabelLimit > maxClientLabelLimit {
+			addProblem("invalid client-label-limit %d of the collector %s, must be at most %d",
+				collector.ClientLabelLimit, name, maxClientLabelLimit)
+		}
+		if collector.LogTopMsgIDs > maxLogTopMsgIDs {
+			addProblem("invalid log-top-msgids %d of the collector %s, must be at most %d",
+				collector.LogTopMsgIDs, name, maxLogTopMsgIDs)
+		}
+		for _, detail := range collector.StatusDetails {
+			if !contains(statusDetails, detail) {
+				addProblem("invalid status-details entry %q of the collector %s, supported details are %s",
+					detail, name, strings.Join(statusDetails, ", "))
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+func validCollectorMode(mode string) bool {
+	return mode == CollectorModeBackground || mode == CollectorModeScrape
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
 }
 
 // GConfigInterface enables to get configuration,
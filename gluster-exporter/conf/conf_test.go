@@ -0,0 +1,143 @@
+package conf
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testCollectors are the collectors known to the validation in the tests
+var testCollectors = []string{
+	"gluster_ps", "gluster_brick", "gluster_brick_heal", "gluster_brick_stats",
+	"gluster_brick_statedump", "gluster_volume", "gluster_volume_heal",
+	"gluster_volume_clients", "gluster_log", "gluster_fuse",
+}
+
+func loadTestConfig(t *testing.T, content string) *Config {
+	dir, err := ioutil.TempDir("", "conf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "gluster-exporter.toml")
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	conf, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %s", err)
+	}
+	return conf
+}
+
+func TestValidateSample(t *testing.T) {
+	conf, err := LoadConfig(filepath.Join("..", "..", "extras", "conf", "gluster-exporter.toml.sample"))
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %s", err)
+	}
+	var collectors []string
+	for name := range conf.CollectorsConf {
+		collectors = append(collectors, name)
+	}
+	// the cacheable functions are checked by TestCacheableFuncs
+	if err := conf.Validate(collectors, conf.CacheEnabledFuncs); err != nil {
+		t.Errorf("expected the sample config to be valid, got %s", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		// problem is the start of the single problem expected
+		problem string
+	}{
+		{name: "valid", content: `
+[globals]
+port = 9713
+metrics-path = "/metrics"
+events-path = "/events"
+
+[collectors.gluster_ps]
+sync-interval = 10
+
+[collectors.gluster_volume_heal]
+heal-info-mode = "summary"
+
+[collectors.gluster_volume_clients]
+client-label-limit = -1
+`},
+		{name: "unknown key", content: `
+[globals]
+metric-path = "/metrics"
+`, problem: `unknown option "globals.metric-path"`},
+		{name: "unknown collector key", content: `
+[collectors.gluster_ps]
+sync-intervall = 10
+`, problem: `unknown option "collectors.gluster_ps.sync-intervall"`},
+		{name: "port", content: `
+[globals]
+port = 70000
+`, problem: "invalid port 70000"},
+		{name: "metrics path", content: `
+[globals]
+metrics-path = "metrics"
+`, problem: `invalid metrics-path "metrics"`},
+		{name: "events path", content: `
+[globals]
+events-path = "/metrics"
+`, problem: `events-path "/metrics" is the same as metrics-path`},
+		{name: "collector mode", content: `
+[globals]
+collector-mode = "pull"
+`, problem: `invalid collector-mode "pull"`},
+		{name: "mode of the collector", content: `
+[collectors.gluster_ps]
+mode = "pull"
+`, problem: `invalid mode "pull" of the collector gluster_ps`},
+		{name: "unknown collector", content: `
+[collectors.gluster_foo]
+sync-interval = 10
+`, problem: `unknown collector "gluster_foo"`},
+		{name: "collector name", content: `
+[collectors.gluster_ps]
+name = "gluster_brick"
+`, problem: `name "gluster_brick" of the collector gluster_ps does not match its section`},
+		{name: "option of another collector", content: `
+[collectors.gluster_ps]
+heal-index-scan-limit = 1000
+`, problem: "option heal-index-scan-limit of the collector gluster_ps is only used by the collector gluster_brick_heal"},
+		{name: "zero option of another collector", content: `
+[collectors.gluster_volume]
+client-label-limit = 0
+`, problem: "option client-label-limit of the collector gluster_volume is only used by the collector gluster_volume_clients"},
+		{name: "client label limit", content: `
+[collectors.gluster_volume_clients]
+client-label-limit = 100000
+`, problem: "invalid client-label-limit 100000 of the collector gluster_volume_clients"},
+		{name: "log top msgids", content: `
+[collectors.gluster_log]
+log-top-msgids = 5000
+`, problem: "invalid log-top-msgids 5000 of the collector gluster_log"},
+		{name: "status details", content: `
+[collectors.gluster_brick_stats]
+status-details = ["mem", "clients"]
+`, problem: `invalid status-details entry "clients" of the collector gluster_brick_stats`},
+	}
+	for _, tt := range tests {
+		conf := loadTestConfig(t, tt.content)
+		err := conf.Validate(testCollectors, nil)
+		if tt.problem == "" {
+			if err != nil {
+				t.Errorf("%s: expected no problems, got %s", tt.name, err)
+			}
+			continue
+		}
+		vErr, ok := err.(*ValidationError)
+		if !ok || len(vErr.Problems) != 1 || !strings.HasPrefix(vErr.Problems[0], tt.problem) {
+			t.Errorf("%s: expected the problem %q, got %v", tt.name, tt.problem, err)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package conf
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	// EnvPrefix is the prefix of the environment variables setting the
+	// options, like GLUSTER_EXPORTER_LOG_LEVEL for 'log-level'
+	EnvPrefix = "GLUSTER_EXPORTER_"
+	// EnvCollectorPrefix is the prefix of the environment variables
+	// setting the collector options, followed by the collector name,
+	// like GLUSTER_EXPORTER_COLLECTOR_GLUSTER_PS_SYNC_INTERVAL
+	EnvCollectorPrefix = EnvPrefix + "COLLECTOR_"
+	// EnvFileSuffix reads the value of a variable from a file, for the
+	// secrets, like GLUSTER_EXPORTER_GLUSTERD2_SECRET_FILE
+	EnvFileSuffix = "_FILE"
+)
+
+// option is a field of the configuration, with its key
+// in the configuration file and its environment variable
+type option struct {
+	key   string
+	env   string
+	value reflect.Value
+}
+
+// options returns the options of the configuration struct, the
+// fields of an embedded struct pointer, like GConfig, are included
+func options(v reflect.Value) []option {
+	var opts []option
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		if field.Anonymous && field.Type.Kind() == reflect.Ptr {
+			if !v.Field(i).IsNil() {
+				opts = append(opts, options(v.Field(i).Elem())...)
+			}
+			continue
+		}
+		opt := option{key: field.Tag.Get("toml"), value: v.Field(i)}
+		if opt.key == "" {
+			opt.key = field.Name
+			opt.env = envName(field.Name)
+		} else {
+			opt.env = strings.ToUpper(strings.Replace(opt.key, "-", "_", -1))
+		}
+		opts = append(opts, opt)
+	}
+	return opts
+}
+
+// envName returns the environment variable name of a field without a
+// toml key, like GLUSTERD2_USER for Glusterd2User
+func envName(fieldName string) string {
+	var name strings.Builder
+	prevUpper := true
+	for _, r := range fieldName {
+		upper := unicode.IsUpper(r)
+		if upper && !prevUpper {
+			name.WriteByte('_')
+		}
+		prevUpper = upper
+		name.WriteRune(unicode.ToUpper(r))
+	}
+	return name.String()
+}
+
+// setOption sets the option from the value of its environment
+// variable, the lists are comma separated
+func setOption(v reflect.Value, value string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Slice:
+		var values []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				values = append(values, item)
+			}
+		}
+		v.Set(reflect.ValueOf(values))
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+// envLookup looks up the environment variables of the options, and
+// tracks the ones used, to report the unknown ones
+type envLookup struct {
+	vars map[string]string
+	used map[string]bool
+}
+
+func newEnvLookup(environ []string) *envLookup {
+	l := &envLookup{vars: make(map[string]string), used: make(map[string]bool)}
+	for _, kv := range environ {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) == 2 && strings.HasPrefix(parts[0], EnvPrefix) {
+			l.vars[parts[0]] = parts[1]
+		}
+	}
+	return l
+}
+
+// lookup returns the value of the variable, or the content of the file
+// set by its _FILE variant, without the trailing newline. The source is
+// the name of the variable used
+func (l *envLookup) lookup(name string) (value, source string, ok bool, err error) {
+	value, isSet := l.vars[name]
+	path, fileSet := l.vars[name+EnvFileSuffix]
+	l.used[name] = true
+	l.used[name+EnvFileSuffix] = true
+	switch {
+	case isSet && fileSet:
+		return "", "", false, fmt.Errorf("both %s and %s are set", name, name+EnvFileSuffix)
+	case isSet:
+		return value, name, true, nil
+	case fileSet:
+		data, err := ioutil.ReadFile(filepath.Clean(path))
+		if err != nil {
+			return "", "", false, fmt.Errorf("%s: %s", name+EnvFileSuffix, err)
+		}
+		return strings.TrimRight(string(data), "\r\n"), name + EnvFileSuffix, true, nil
+	}
+	return "", "", false, nil
+}
+
+// unused returns the variables with the prefix which matched no option
+func (l *envLookup) unused() []string {
+	var names []string
+	for name := range l.vars {
+		if !l.used[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// setFromEnv sets the option from its environment variable, if set
+func (conf *Config) setFromEnv(l *envLookup, opt option, name string, key string) {
+	value, source, ok, err := l.lookup(name)
+	if err != nil {
+		conf.problems = append(conf.problems, err.Error())
+		return
+	}
+	if !ok {
+		return
+	}
+	if err := setOption(opt.value, value); err != nil {
+		conf.problems = append(conf.problems, fmt.Sprintf("invalid %s: %s", source, err))
+		return
+	}
+	conf.definedBy[key] = source
+	conf.envVars = append(conf.envVars, source)
+}
+
+// applyEnv sets the options from the GLUSTER_EXPORTER_* environment
+// variables, the unknown variables and invalid values are problems
+// reported by Validate, like the unknown keys of the configuration file
+func (conf *Config) applyEnv(environ []string) {
+	l := newEnvLookup(environ)
+	for _, opt := range options(reflect.ValueOf(conf.Globals).Elem()) {
+		conf.setFromEnv(l, opt, EnvPrefix+opt.env, "globals."+opt.key)
+	}
+
+	// the collector of a variable is known once its option is
+	// matched, the longest option wins, like HEAL_INFO_MODE over MODE
+	collectorOpts := options(reflect.ValueOf(&Collectors{}).Elem())
+	var names []string
+	for name := range l.vars {
+		if strings.HasPrefix(name, EnvCollectorPrefix) && !l.used[name] {
+			names = append(names, strings.TrimSuffix(name, EnvFileSuffix))
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if l.used[name] {
+			continue
+		}
+		rest := strings.TrimPrefix(name, EnvCollectorPrefix)
+		matched := -1
+		for idx, opt := range collectorOpts {
+			if strings.HasSuffix(rest, "_"+opt.env) && len(rest) > len(opt.env)+1 &&
+				(matched < 0 || len(opt.env) > len(collectorOpts[matched].env)) {
+				matched = idx
+			}
+		}
+		if matched < 0 {
+			continue
+		}
+		collector := strings.ToLower(strings.TrimSuffix(rest, "_"+collectorOpts[matched].env))
+		if conf.CollectorsConf == nil {
+			conf.CollectorsConf = make(map[string]Collectors)
+		}
+		collectorConf := conf.CollectorsConf[collector]
+		opt := options(reflect.ValueOf(&collectorConf).Elem())[matched]
+		conf.setFromEnv(l, opt, name, "collectors."+collector+"."+opt.key)
+		conf.CollectorsConf[collector] = collectorConf
+	}
+
+	for _, name := range l.unused() {
+		conf.problems = append(conf.problems, fmt.Sprintf("unknown environment variable %s", name))
+	}
+	sort.Strings(conf.envVars)
+}
@@ -0,0 +1,168 @@
+package conf
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func newTestConfig() *Config {
+	return &Config{
+		Globals:   &Globals{GConfig: &GConfig{}},
+		definedBy: make(map[string]string),
+	}
+}
+
+func TestEnvName(t *testing.T) {
+	for fieldName, expected := range map[string]string{
+		"Glusterd2User":     "GLUSTERD2_USER",
+		"Glusterd2Insecure": "GLUSTERD2_INSECURE",
+		"Timeout":           "TIMEOUT",
+	} {
+		if name := envName(fieldName); name != expected {
+			t.Errorf("%s: expected %s, got %s", fieldName, expected, name)
+		}
+	}
+}
+
+func TestApplyEnv(t *testing.T) {
+	dir, err := ioutil.TempDir("", "conf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	secretFile := filepath.Join(dir, "secret")
+	if err := ioutil.WriteFile(secretFile, []byte("s3cr3t\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		environ  []string
+		check    func(conf *Config) interface{}
+		expected interface{}
+		// definedBy is the key of the option and its expected source
+		definedBy []string
+		problem   string
+	}{
+		{
+			name:      "string",
+			environ:   []string{"GLUSTER_EXPORTER_LOG_LEVEL=debug"},
+			check:     func(conf *Config) interface{} { return conf.LogLevel },
+			expected:  "debug",
+			definedBy: []string{"globals.log-level", "GLUSTER_EXPORTER_LOG_LEVEL"},
+		},
+		{
+			name:     "field without key",
+			environ:  []string{"GLUSTER_EXPORTER_GLUSTERD2_USER=admin"},
+			check:    func(conf *Config) interface{} { return conf.Glusterd2User },
+			expected: "admin",
+		},
+		{
+			name:     "bool",
+			environ:  []string{"GLUSTER_EXPORTER_GLUSTERD2_INSECURE=true"},
+			check:    func(conf *Config) interface{} { return conf.Glusterd2Insecure },
+			expected: true,
+		},
+		{
+			name:     "invalid bool",
+			environ:  []string{"GLUSTER_EXPORTER_GLUSTERD2_INSECURE=maybe"},
+			check:    func(conf *Config) interface{} { return conf.Glusterd2Insecure },
+			expected: false,
+			problem:  "invalid GLUSTER_EXPORTER_GLUSTERD2_INSECURE",
+		},
+		{
+			name:     "invalid int",
+			environ:  []string{"GLUSTER_EXPORTER_PORT=http"},
+			check:    func(conf *Config) interface{} { return conf.Port },
+			expected: 0,
+			problem:  "invalid GLUSTER_EXPORTER_PORT",
+		},
+		{
+			name:     "list",
+			environ:  []string{"GLUSTER_EXPORTER_CACHE_ENABLED_FUNCS=IsLeader, Peers,,"},
+			check:    func(conf *Config) interface{} { return conf.CacheEnabledFuncs },
+			expected: []string{"IsLeader", "Peers"},
+		},
+		{
+			name:      "file",
+			environ:   []string{"GLUSTER_EXPORTER_GLUSTERD2_SECRET_FILE=" + secretFile},
+			check:     func(conf *Config) interface{} { return conf.Glusterd2Secret },
+			expected:  "s3cr3t",
+			definedBy: []string{"globals.Glusterd2Secret", "GLUSTER_EXPORTER_GLUSTERD2_SECRET_FILE"},
+		},
+		{
+			name:     "missing file",
+			environ:  []string{"GLUSTER_EXPORTER_GLUSTERD2_SECRET_FILE=" + secretFile + ".missing"},
+			check:    func(conf *Config) interface{} { return conf.Glusterd2Secret },
+			expected: "",
+			problem:  "GLUSTER_EXPORTER_GLUSTERD2_SECRET_FILE: ",
+		},
+		{
+			name: "both the variable and the file",
+			environ: []string{
+				"GLUSTER_EXPORTER_GLUSTERD2_SECRET=other",
+				"GLUSTER_EXPORTER_GLUSTERD2_SECRET_FILE=" + secretFile,
+			},
+			check:    func(conf *Config) interface{} { return conf.Glusterd2Secret },
+			expected: "",
+			problem:  "both GLUSTER_EXPORTER_GLUSTERD2_SECRET and GLUSTER_EXPORTER_GLUSTERD2_SECRET_FILE are set",
+		},
+		{
+			name:      "collector name with underscores",
+			environ:   []string{"GLUSTER_EXPORTER_COLLECTOR_GLUSTER_PS_SYNC_INTERVAL=30"},
+			check:     func(conf *Config) interface{} { return conf.CollectorsConf["gluster_ps"].SyncInterval },
+			expected:  uint64(30),
+			definedBy: []string{"collectors.gluster_ps.sync-interval", "GLUSTER_EXPORTER_COLLECTOR_GLUSTER_PS_SYNC_INTERVAL"},
+		},
+		{
+			name:    "longest collector option",
+			environ: []string{"GLUSTER_EXPORTER_COLLECTOR_GLUSTER_VOLUME_HEAL_HEAL_INFO_MODE=summary"},
+			check: func(conf *Config) interface{} {
+				return []string{conf.CollectorsConf["gluster_volume_heal"].HealInfoMode,
+					conf.CollectorsConf["gluster_volume_heal"].Mode}
+			},
+			expected: []string{"summary", ""},
+		},
+		{
+			name:     "collector mode",
+			environ:  []string{"GLUSTER_EXPORTER_COLLECTOR_GLUSTER_VOLUME_HEAL_MODE=scrape"},
+			check:    func(conf *Config) interface{} { return conf.CollectorsConf["gluster_volume_heal"].Mode },
+			expected: "scrape",
+		},
+		{
+			name:     "unknown variable",
+			environ:  []string{"GLUSTER_EXPORTER_LOG_LEVL=debug", "OTHER_LOG_LEVEL=debug"},
+			check:    func(conf *Config) interface{} { return conf.LogLevel },
+			expected: "",
+			problem:  "unknown environment variable GLUSTER_EXPORTER_LOG_LEVL",
+		},
+		{
+			name:     "unknown collector option",
+			environ:  []string{"GLUSTER_EXPORTER_COLLECTOR_GLUSTER_PS_INTERVAL=30"},
+			check:    func(conf *Config) interface{} { return len(conf.CollectorsConf) },
+			expected: 0,
+			problem:  "unknown environment variable GLUSTER_EXPORTER_COLLECTOR_GLUSTER_PS_INTERVAL",
+		},
+	}
+	for _, tt := range tests {
+		conf := newTestConfig()
+		conf.applyEnv(tt.environ)
+		if value := tt.check(conf); !reflect.DeepEqual(value, tt.expected) {
+			t.Errorf("%s: expected %#v, got %#v", tt.name, tt.expected, value)
+		}
+		if len(tt.definedBy) == 2 && conf.definedBy[tt.definedBy[0]] != tt.definedBy[1] {
+			t.Errorf("%s: expected %s to be defined by %s, got %q", tt.name,
+				tt.definedBy[0], tt.definedBy[1], conf.definedBy[tt.definedBy[0]])
+		}
+		switch {
+		case tt.problem == "" && len(conf.problems) > 0:
+			t.Errorf("%s: expected no problems, got %v", tt.name, conf.problems)
+		case tt.problem != "" && (len(conf.problems) != 1 || !strings.HasPrefix(conf.problems[0], tt.problem)):
+			t.Errorf("%s: expected the problem %q, got %v", tt.name, tt.problem, conf.problems)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/gluster/gluster-prometheus/gluster-exporter/conf"
+	"github.com/gluster/gluster-prometheus/pkg/glusterutils"
+	"github.com/gluster/gluster-prometheus/pkg/glusterutils/glusterconsts"
+
+	"github.com/BurntSushi/toml"
+)
+
+// secretMask replaces the secrets in the printed configuration
+const secretMask = "<secret>"
+
+// collectorNames returns the names of the registered collectors
+func collectorNames() []string {
+	names := make([]string, 0, len(glusterMetrics))
+	for _, m := range glusterMetrics {
+		names = append(names, m.name)
+	}
+	return names
+}
+
+// collectorFlag is a --collector.<name> or --no-collector.<name>
+// flag set on the command line
+type collectorFlag struct {
+	name    string
+	enabled bool
+}
+
+// collectorFlags are the collector flags set on the command line,
+// by the name of the collector
+var collectorFlags = make(map[string]collectorFlag)
+
+// registerCollectorFlags registers the --collector.<name> and
+// --no-collector.<name> flags of the collectors, like node_exporter
+func registerCollectorFlags() {
+	for _, m := range glusterMetrics {
+		flag.Bool("collector."+m.name, false, "Enable the "+m.name+" collector, overrides the config file")
+		flag.Bool("no-collector."+m.name, false, "Disable the "+m.name+" collector, overrides the config file")
+	}
+}
+
+// parseCollectorFlags collects the collector flags set on the
+// command line, once the flags are parsed
+func parseCollectorFlags() error {
+	var err error
+	flag.Visit(func(f *flag.Flag) {
+		name, enabled := "", true
+		switch {
+		case strings.HasPrefix(f.Name, "collector."):
+			name = strings.TrimPrefix(f.Name, "collector.")
+		case strings.HasPrefix(f.Name, "no-collector."):
+			name, enabled = strings.TrimPrefix(f.Name, "no-collector."), false
+		default:
+			return
+		}
+		// like --collector.<name>=false
+		if f.Value.String() != "true" {
+			enabled = !enabled
+		}
+		if prev, ok := collectorFlags[name]; ok && prev.enabled != enabled {
+			err = fmt.Errorf("conflicting flags --%s and --%s", prev.name, f.Name)
+		}
+		collectorFlags[name] = collectorFlag{name: f.Name, enabled: enabled}
+	})
+	return err
+}
+
+// mergeCollectorsConf sets the configuration of every registered
+// collector, from its defaults, overridden by its section in the
+// config file, overridden by the command line flags
+func mergeCollectorsConf(exporterConf *conf.Config) {
+	confs := make(map[string]conf.Collectors, len(glusterMetrics))
+	for _, m := range glusterMetrics {
+		collectorConf := exporterConf.CollectorsConf[m.name]
+		collectorConf.Name = m.name
+		if collectorConf.SyncInterval == 0 {
+			collectorConf.SyncInterval = m.defaults.SyncInterval
+		}
+		if !exporterConf.IsDefined("collectors", m.name, "disabled") {
+			collectorConf.Disabled = m.defaults.Disabled
+		}
+		if f, ok := collectorFlags[m.name]; ok {
+			collectorConf.Disabled = !f.enabled
+		}
+		confs[m.name] = collectorConf
+	}
+	exporterConf.CollectorsConf = confs
+}
+
+// collectorReason tells why the collector is enabled or disabled
+func collectorReason(exporterConf *conf.Config, name string) string {
+	state := "enabled"
+	if exporterConf.CollectorsConf[name].Disabled {
+		state = "disabled"
+	}
+	if f, ok := collectorFlags[name]; ok {
+		return state + " by --" + f.name
+	}
+	switch source := exporterConf.DefinedBy("collectors", name, "disabled"); source {
+	case "":
+	case "the config file":
+		return state + " in the config file"
+	default:
+		return state + " by " + source
+	}
+	return state + " by default"
+}
+
+// loadConfig loads and validates the config file, and sets the
+// defaults which depend on the gluster management and the collectors
+func loadConfig(path string) (*conf.Config, error) {
+	exporterConf, err := conf.LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := exporterConf.Validate(collectorNames(), glusterutils.CacheableFuncs); err != nil {
+		return nil, err
+	}
+	if exporterConf.GlusterdWorkdir == "" {
+		exporterConf.GlusterdWorkdir =
+			getDefaultGlusterdDir(exporterConf.GlusterMgmt)
+	}
+	mergeCollectorsConf(exporterConf)
+	return exporterConf, nil
+}
+
+// configPath returns the path of the config file, empty if the
+// default config file is missing, then the exporter is configured
+// by the environment variables only
+func configPath() string {
+	explicit := false
+	flag.Visit(func(f *flag.Flag) {
+		explicit = explicit || f.Name == "config"
+	})
+	if explicit || *config == "" {
+		return *config
+	}
+	if _, err := os.Stat(*config); os.IsNotExist(err) {
+		return ""
+	}
+	return *config
+}
+
+// configSource names the config file, or the environment
+// if there is no config file
+func configSource(path string) string {
+	if path == "" {
+		return "environment"
+	}
+	return path
+}
+
+// checkConfig writes the problems found in the config file and the
+// environment variables, and returns false if there are any
+func checkConfig(w io.Writer, path string) bool {
+	_, err := loadConfig(path)
+	if err == nil {
+		_, _ = fmt.Fprintf(w, "%s: OK\n", configSource(path))
+		return true
+	}
+	if verr, ok := err.(*conf.ValidationError); ok {
+		for _, problem := range verr.Problems {
+			_, _ = fmt.Fprintf(w, "%s: %s\n", configSource(path), problem)
+		}
+		return false
+	}
+	_, _ = fmt.Fprintf(w, "%s: %s\n", configSource(path), err)
+	return false
+}
+
+// printConfig writes the effective configuration in the TOML format,
+// with the defaults set and the secrets masked, after the order of
+// precedence of its sources
+func printConfig(w io.Writer, exporterConf *conf.Config, path string) error {
+	if path == "" {
+		path = "none"
+	}
+	envVars := "none"
+	if len(exporterConf.EnvVars()) > 0 {
+		envVars = strings.Join(exporterConf.EnvVars(), ", ")
+	}
+	_, err := fmt.Fprintf(w, `# Effective configuration of gluster-exporter, from the sources
+# below, each overriding the previous ones:
+#  1. the defaults, of the exporter and of each collector
+#  2. the config file: %s
+#  3. the %s and %s environment variables
+#  4. the %s* environment variables, the ones with the %s
+#     suffix are read from the file at their value, like the secrets
+#  5. the --collector.<name> and --no-collector.<name> flags
+# Environment variables applied: %s
+
+`, path, glusterconsts.EnvGD2Endpoints, glusterconsts.EnvGlusterClusterID,
+		conf.EnvPrefix, conf.EnvFileSuffix, envVars)
+	if err != nil {
+		return err
+	}
+
+	globals := *exporterConf.Globals
+	gConfig := *exporterConf.GConfig()
+	glusterutils.SetDefaultConfig(&gConfig)
+	globals.GConfig = &gConfig
+	for _, secret := range []*string{&globals.EventsToken, &globals.EventsSecret, &gConfig.Glusterd2Secret} {
+		if *secret != "" {
+			*secret = secretMask
+		}
+	}
+	return toml.NewEncoder(w).Encode(conf.Config{
+		Globals:        &globals,
+		CollectorsConf: exporterConf.CollectorsConf,
+	})
+}
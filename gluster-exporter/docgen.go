@@ -49,9 +49,10 @@ func generateMetricsDoc() {
 	fmt.Println(writer.h1("Metrics Exported by Gluster Prometheus exporter"))
 	for _, m := range metrics {
 		fmt.Println(writer.h2(m.Namespace + "_" + m.Name))
-		desc := m.LongHelp
-		if desc == "" {
-			desc = m.Help
+		// the long help adds to the help, which it doesn't repeat
+		desc := m.Help
+		if m.LongHelp != "" {
+			desc += ". " + m.LongHelp
 		}
 		fmt.Println(writer.para(desc))
 		if len(m.Labels) > 0 {
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gluster/gluster-prometheus/pkg/events"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+// maxEventSize is the max size of an event pushed by glustereventsd
+const maxEventSize = 64 * 1024
+
+var (
+	eventLabels = []MetricLabel{
+		clusterIDLabel,
+		{
+			Name: "event",
+			Help: "Name of the gluster event, like `PEER_DETACH` or `QUORUM_LOST`, `OTHER` for the events unknown to glustereventsd",
+		},
+	}
+
+	eventRejectedLabels = []MetricLabel{
+		clusterIDLabel,
+		{
+			Name: "reason",
+			Help: "Reason the event was rejected, `unauthorized` or `invalid`",
+		},
+	}
+
+	eventsTotalMetric = Metric{
+		Namespace: "gluster",
+		Name:      "events_total",
+		Help:      "Total no of gluster events received from glustereventsd",
+		LongHelp: "The events are pushed to the events webhook " +
+			"of the exporter. Exported once the first event of the kind is received",
+		Labels: eventLabels,
+	}
+
+	eventLastTimestampMetric = Metric{
+		Namespace: "gluster",
+		Name:      "event_last_timestamp_seconds",
+		Help:      "Unix timestamp of the last gluster event of the kind, as reported by glustereventsd",
+		Labels:    eventLabels,
+	}
+
+	eventsRejectedMetric = Metric{
+		Namespace: "gluster",
+		Name:      "events_rejected_total",
+		Help:      "Total no of requests to the events webhook which were rejected",
+		Labels:    eventRejectedLabels,
+	}
+
+	glusterEventsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: eventsTotalMetric.Namespace,
+			Name:      eventsTotalMetric.Name,
+			Help:      eventsTotalMetric.Help,
+		},
+		eventsTotalMetric.LabelNames(),
+	)
+
+	glusterEventLastTimestamp = newSelfGaugeVec(eventLastTimestampMetric)
+
+	glusterEventsRejected = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: eventsRejectedMetric.Namespace,
+			Name:      eventsRejectedMetric.Name,
+			Help:      eventsRejectedMetric.Help,
+		},
+		eventsRejectedMetric.LabelNames(),
+	)
+)
+
+// eventsHandler receives the gluster events pushed by glustereventsd to
+// the webhook registered with 'gluster-eventsapi webhook-add'
+type eventsHandler struct {
+	verifier events.Verifier
+}
+
+func newEventsHandler(verifier events.Verifier) *eventsHandler {
+	for _, reason := range []string{"unauthorized", "invalid"} {
+		glusterEventsRejected.With(prometheus.Labels{"cluster_id": clusterID, "reason": reason}).Add(0)
+	}
+	return &eventsHandler{verifier: verifier}
+}
+
+func (h *eventsHandler) reject(w http.ResponseWriter, r *http.Request, reason string, status int, err error) {
+	log.WithError(err).WithFields(log.Fields{
+		"remote": r.RemoteAddr,
+		"reason": reason,
+	}).Debug("[Gluster Events] Rejected event")
+	glusterEventsRejected.With(prometheus.Labels{"cluster_id": clusterID, "reason": reason}).Inc()
+	http.Error(w, http.StatusText(status), status)
+}
+
+// ServeHTTP implements http.Handler
+func (h *eventsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	event, err := events.Parse(http.MaxBytesReader(w, r.Body, maxEventSize))
+	if err != nil {
+		h.reject(w, r, "invalid", http.StatusBadRequest, err)
+		return
+	}
+	if err := h.verifier.Verify(r.Header.Get("Authorization"), event, time.Now()); err != nil {
+		h.reject(w, r, "unauthorized", http.StatusUnauthorized, err)
+		return
+	}
+	if event.Name == events.TestEvent {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	lbls := prometheus.Labels{"cluster_id": clusterID, "event": events.MetricName(event.Name)}
+	glusterEventsTotal.With(lbls).Inc()
+	ts := event.Time()
+	if event.Timestamp == 0 {
+		ts = time.Now()
+	}
+	glusterEventLastTimestamp.With(lbls).Set(float64(ts.Unix()))
+	w.WriteHeader(http.StatusOK)
+}
+
+func init() {
+	prometheus.MustRegister(
+		glusterEventsTotal,
+		glusterEventLastTimestamp,
+		glusterEventsRejected,
+	)
+	// Add to the global queue for documentation
+	metrics = append(metrics, eventsTotalMetric, eventLastTimestampMetric, eventsRejectedMetric)
+}
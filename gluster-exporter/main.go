@@ -1,19 +1,28 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
 	"runtime"
-	"strings"
+	"strconv"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/gluster/gluster-prometheus/gluster-exporter/conf"
+	"github.com/gluster/gluster-prometheus/pkg/events"
 	"github.com/gluster/gluster-prometheus/pkg/glusterutils"
+	"github.com/gluster/gluster-prometheus/pkg/glusterutils/capture"
 	"github.com/gluster/gluster-prometheus/pkg/glusterutils/glusterconsts"
 	"github.com/gluster/gluster-prometheus/pkg/logging"
+	"github.com/gluster/gluster-prometheus/pkg/webconfig"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	log "github.com/sirupsen/logrus"
 )
@@ -30,7 +39,10 @@ var (
 var (
 	showVersion                   = flag.Bool("version", false, "Show the version information")
 	docgen                        = flag.Bool("docgen", false, "Generate exported metrics documentation in Asciidoc format")
-	config                        = flag.String("config", defaultConfFile, "Config file path")
+	config                        = flag.String("config", defaultConfFile, "Config file path, without the default config file the exporter is configured by the GLUSTER_EXPORTER_* environment variables")
+	checkConfigFlag               = flag.Bool("check-config", false, "Check the config file, and exit with the problems found")
+	printConfigFlag               = flag.Bool("print-config", false, "Print the effective config, with the defaults set and the secrets masked")
+	recordDir                     = flag.String("record-dir", "", "Record the outputs of the gluster and lvm commands, the /proc files of the gluster processes and the glusterd2 responses into a timestamped tar.gz archive under the given directory, written when the exporter is stopped")
 	defaultInterval time.Duration = 5
 	clusterIDLabel                = MetricLabel{
 		Name: "cluster_id",
@@ -39,15 +51,45 @@ var (
 	clusterID string
 )
 
+// collectorDefaults are the defaults of a collector, overridden by
+// its section in the config file and by the command line flags
+type collectorDefaults struct {
+	SyncInterval uint64
+	Disabled     bool
+}
+
 type glusterMetric struct {
-	name string
-	fn   func(glusterutils.GInterface) error
+	name      string
+	defaults  collectorDefaults
+	fn        func(context.Context, glusterutils.GInterface) error
+	gaugeVecs map[string]*ExportedGaugeVec
 }
 
 var glusterMetrics []glusterMetric
 
-func registerMetric(name string, fn func(glusterutils.GInterface) error) {
-	glusterMetrics = append(glusterMetrics, glusterMetric{name: name, fn: fn})
+// collectorsConf is the configuration of the collectors, the
+// collectors with their own options look it up by their name.
+// It is replaced on the reload of the configuration
+var collectorsConf = struct {
+	sync.RWMutex
+	confs map[string]conf.Collectors
+}{}
+
+// getCollectorConf returns the configuration of the collector
+func getCollectorConf(name string) conf.Collectors {
+	collectorsConf.RLock()
+	defer collectorsConf.RUnlock()
+	return collectorsConf.confs[name]
+}
+
+func setCollectorsConf(confs map[string]conf.Collectors) {
+	collectorsConf.Lock()
+	defer collectorsConf.Unlock()
+	collectorsConf.confs = confs
+}
+
+func registerMetric(name string, defaults collectorDefaults, fn func(context.Context, glusterutils.GInterface) error, gaugeVecs map[string]*ExportedGaugeVec) {
+	glusterMetrics = append(glusterMetrics, glusterMetric{name: name, defaults: defaults, fn: fn, gaugeVecs: gaugeVecs})
 }
 
 func dumpVersionInfo() {
@@ -63,13 +105,33 @@ func getDefaultGlusterdDir(mgmt string) string {
 	return defaultGlusterd1Workdir
 }
 
+// archiveOnExit makes the exporter write the capture archive of the
+// recorder and exit, when it is stopped by SIGINT or SIGTERM
+func archiveOnExit(recorder *capture.Recorder) {
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-stop
+		if err := recorder.Close(); err != nil {
+			log.WithError(err).WithField("dir", recorder.Dir()).
+				Fatal("Failed to archive the recording")
+		}
+		log.WithField("archive", recorder.Archive()).Info("Recorded the gluster outputs")
+		os.Exit(0)
+	}()
+}
+
 func main() {
 	// Init logger with stderr, will be reinitialized later
 	if err := logging.Init("", "-", "info"); err != nil {
 		log.Fatal("Init logging failed for stderr")
 	}
 
+	registerCollectorFlags()
 	flag.Parse()
+	if err := parseCollectorFlags(); err != nil {
+		log.WithError(err).Fatal("Invalid collector flags")
+	}
 
 	if *docgen {
 		generateMetricsDoc()
@@ -81,67 +143,101 @@ func main() {
 		return
 	}
 
+	if *checkConfigFlag {
+		if !checkConfig(os.Stdout, configPath()) {
+			os.Exit(1)
+		}
+		return
+	}
+
 	var gluster glusterutils.GInterface
-	exporterConf, err := conf.LoadConfig(*config)
+	confPath := configPath()
+	exporterConf, err := loadConfig(confPath)
 	if err != nil {
 		log.WithError(err).Fatal("Loading global config failed")
 	}
 
-	if strings.ToLower(exporterConf.LogFile) != "stderr" && exporterConf.LogFile != "-" && strings.ToLower(exporterConf.LogFile) != "stdout" {
-		// Create Log dir
-		err = os.MkdirAll(exporterConf.LogDir, 0750)
-		if err != nil {
-			log.WithError(err).WithField("logdir", exporterConf.LogDir).
-				Fatal("Failed to create log directory")
+	if *printConfigFlag {
+		if err := printConfig(os.Stdout, exporterConf, confPath); err != nil {
+			log.WithError(err).Fatal("Failed to print the config")
 		}
+		return
 	}
 
-	if err := logging.Init(exporterConf.LogDir, exporterConf.LogFile, exporterConf.LogLevel); err != nil {
-		log.WithError(err).Fatal("Failed to initialize logging")
+	if err := initLogging(exporterConf); err != nil {
+		log.WithError(err).WithField("logdir", exporterConf.LogDir).
+			Fatal("Failed to initialize logging")
 	}
 
 	// Set the Gluster Configurations used in glusterutils
-	if exporterConf.GlusterdWorkdir == "" {
-		exporterConf.GlusterdWorkdir =
-			getDefaultGlusterdDir(exporterConf.GlusterMgmt)
-	}
 	gluster = glusterutils.MakeGluster(exporterConf)
+	if exporterConf.GlusterMgmt == glusterconsts.MgmtGlusterd2 {
+		prometheus.MustRegister(newGD2EndpointCollector(gluster))
+	}
 
-	// start := time.Now()
-
-	for _, m := range glusterMetrics {
-		if collectorConf, ok := exporterConf.CollectorsConf[m.name]; ok {
-			if !collectorConf.Disabled {
-				go func(m glusterMetric, gi glusterutils.GInterface) {
-					for {
-						// exporter's config will have proper Cluster ID set
-						clusterID = exporterConf.GlusterClusterID
-						err := m.fn(gi)
-						interval := defaultInterval
-						if collectorConf.SyncInterval > 0 {
-							interval = time.Duration(collectorConf.SyncInterval)
-						}
-						if err != nil {
-							log.WithError(err).WithFields(log.Fields{
-								"name": m.name,
-							}).Debug("failed to export metric")
-						}
-						time.Sleep(time.Second * interval)
-					}
-				}(m, gluster)
-			}
+	if *recordDir != "" {
+		recorder, err := capture.NewRecorder(*recordDir)
+		if err != nil {
+			log.WithError(err).WithField("record-dir", *recordDir).
+				Fatal("Failed to create the record directory")
 		}
+		if err := glusterutils.EnableRecording(recorder, exporterConf.GConfig()); err != nil {
+			log.WithError(err).Fatal("Failed to enable recording")
+		}
+		archiveOnExit(recorder)
+		log.WithField("archive", recorder.Archive()).Info("Recording the gluster outputs")
 	}
 
+	// exporter's config will have proper Cluster ID set
+	clusterID = exporterConf.GlusterClusterID
+	scheduler := newCollectorScheduler(gluster)
+	scheduler.apply(exporterConf)
+	go newConfigReloader(confPath, exporterConf, gluster, scheduler).watch()
+
 	if len(glusterMetrics) == 0 {
 		_, _ = fmt.Fprintf(os.Stderr, "No Metrics registered, Exiting..\n")
 		os.Exit(1)
 	}
 
+	webConfig := &webconfig.Config{}
+	if exporterConf.WebConfigFile != "" {
+		webConfig, err = webconfig.Load(exporterConf.WebConfigFile)
+		if err != nil {
+			log.WithError(err).WithField("web-config-file", exporterConf.WebConfigFile).
+				Fatal("Failed to load the web config")
+		}
+	}
+
 	metricsPath := exporterConf.MetricsPath
 	port := exporterConf.Port
-	http.Handle(metricsPath, promhttp.Handler())
-	if err := http.ListenAndServe(fmt.Sprintf(":%d", port), nil); err != nil {
+	http.Handle(metricsPath, webConfig.Handler(promhttp.Handler()))
+	http.Handle(collectorsPath, webConfig.Handler(scheduler))
+	if exporterConf.EventsPath != "" {
+		// glustereventsd authorizes the events with its own token
+		verifier := events.Verifier{
+			Token:  exporterConf.EventsToken,
+			Secret: exporterConf.EventsSecret,
+		}
+		var eventsHandler http.Handler = newEventsHandler(verifier)
+		if !verifier.Enabled() {
+			// without a token, the events are protected like the metrics,
+			// the basic authentication is set in the webhook URL
+			log.WithField("events-path", exporterConf.EventsPath).
+				Warn("The events are not authorized, set events-bearer-token or events-secret")
+			eventsHandler = webConfig.Handler(eventsHandler)
+		}
+		http.Handle(exporterConf.EventsPath, eventsHandler)
+	}
+	addr := net.JoinHostPort(exporterConf.ListenAddress, strconv.Itoa(port))
+	server, err := webConfig.NewServer(addr, nil)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to configure TLS")
+	}
+	log.WithFields(log.Fields{
+		"address": addr,
+		"tls":     webConfig.TLSEnabled(),
+	}).Info("Serving the metrics")
+	if err := webconfig.ListenAndServe(server); err != nil {
 		_, _ = fmt.Fprintf(os.Stderr, "Failed to run exporter\nError: %s", err)
 		log.WithError(err).Fatal("Failed to run exporter")
 	}
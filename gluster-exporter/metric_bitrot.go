@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+
+	"github.com/gluster/gluster-prometheus/gluster-exporter/conf"
+	"github.com/gluster/gluster-prometheus/pkg/glusterutils"
+	"github.com/gluster/gluster-prometheus/pkg/glusterutils/glusterconsts"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	bitrotScrubInfoLabels = []MetricLabel{
+		clusterIDLabel,
+		{
+			Name: "volume",
+			Help: "Volume Name",
+		},
+		{
+			Name: "throttle",
+			Help: "Scrub throttle, lazy, normal or aggressive",
+		},
+		{
+			Name: "frequency",
+			Help: "Scrub frequency, like daily, weekly or biweekly",
+		},
+	}
+
+	bitrotNodeLabels = []MetricLabel{
+		clusterIDLabel,
+		{
+			Name: "volume",
+			Help: "Volume Name",
+		},
+		{
+			Name: "host",
+			Help: "Host name or IP, as reported by glusterd",
+		},
+	}
+
+	bitrotGaugeVecs = make(map[string]*ExportedGaugeVec)
+
+	glusterBitrotScrubInfo = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "bitrot_scrub_info",
+		Help:      "A metric with a constant '1' value labeled by the scrub throttle and frequency of the volume",
+		Labels:    bitrotScrubInfoLabels,
+	}, &bitrotGaugeVecs)
+
+	glusterBitrotScrubbedFiles = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "bitrot_scrubbed_files",
+		Help:      "No of files scrubbed by the node in the last scrub",
+		Labels:    bitrotNodeLabels,
+	}, &bitrotGaugeVecs)
+
+	glusterBitrotSkippedFiles = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "bitrot_skipped_files",
+		Help:      "No of files skipped by the node in the last scrub",
+		Labels:    bitrotNodeLabels,
+	}, &bitrotGaugeVecs)
+
+	glusterBitrotLastScrub = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "bitrot_last_scrub_timestamp_seconds",
+		Help:      "Unix timestamp of the last scrub completed by the node",
+		LongHelp:  "Not exported till the first scrub completes",
+		Labels:    bitrotNodeLabels,
+	}, &bitrotGaugeVecs)
+
+	glusterBitrotLastScrubDuration = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "bitrot_last_scrub_duration_seconds",
+		Help:      "Duration of the last scrub by the node in seconds",
+		Labels:    bitrotNodeLabels,
+	}, &bitrotGaugeVecs)
+
+	glusterBitrotScrubErrors = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "bitrot_scrub_errors",
+		Help:      "No of errors reported by the scrubber of the node",
+		Labels:    bitrotNodeLabels,
+	}, &bitrotGaugeVecs)
+
+	glusterBitrotCorruptedObjects = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "bitrot_corrupted_objects",
+		Help:      "No of objects found corrupted by the scrubber of the node",
+		Labels:    bitrotNodeLabels,
+	}, &bitrotGaugeVecs)
+)
+
+func getBitrotScrubInfoLabels(status glusterutils.BitrotScrubStatus, volume string) prometheus.Labels {
+	return prometheus.Labels{
+		"cluster_id": clusterID,
+		"volume":     volume,
+		"throttle":   status.Throttle,
+		"frequency":  status.Frequency,
+	}
+}
+
+func getBitrotNodeLabels(volume string, node glusterutils.BitrotScrubNodeStatus) prometheus.Labels {
+	return prometheus.Labels{
+		"cluster_id": clusterID,
+		"volume":     volume,
+		"host":       node.Hostname,
+	}
+}
+
+func bitrot(ctx context.Context, gluster glusterutils.GInterface) error {
+	isLeader, err := gluster.IsLeader(ctx)
+
+	// Reset all vecs to not export stale information
+	for _, gaugeVec := range bitrotGaugeVecs {
+		gaugeVec.RemoveStaleMetrics()
+	}
+
+	if err != nil {
+		// Unable to find out if the current node is leader
+		// Cannot register bitrot metrics at this node
+		log.WithError(err).Debug("Unable to find if the current node is leader")
+		return err
+	}
+	// the scrub status of all the nodes is
+	// available from any node, export only from the leader
+	if !isLeader {
+		return nil
+	}
+
+	volumes, err := gluster.VolumeInfo(ctx)
+	if err != nil {
+		return err
+	}
+	volOption := glusterconsts.BitrotGD1
+	var glusterConfig *conf.GConfig
+	if glusterConfig, err = conf.GConfigFromInterface(gluster); err != nil {
+		return err
+	}
+	if glusterConfig.GlusterMgmt == glusterconsts.MgmtGlusterd2 {
+		volOption = glusterconsts.BitrotGD2
+	}
+
+	for _, volume := range volumes {
+		if volume.State != glusterconsts.VolumeStateStarted {
+			continue
+		}
+		if value := volume.Options[volOption]; value != "on" {
+			// bitrot detection is not enabled for the volume
+			continue
+		}
+		status, err := gluster.BitrotScrubStatus(ctx, volume.Name)
+		if err != nil {
+			log.WithError(err).WithFields(log.Fields{
+				"volume": volume.Name,
+			}).Debug("[Gluster Bitrot] Error getting scrub status")
+			return err
+		}
+		bitrotGaugeVecs[glusterBitrotScrubInfo].Set(getBitrotScrubInfoLabels(status, volume.Name), 1)
+		for _, node := range status.Nodes {
+			labels := getBitrotNodeLabels(volume.Name, node)
+			bitrotGaugeVecs[glusterBitrotScrubbedFiles].Set(labels, float64(node.ScrubbedFiles))
+			bitrotGaugeVecs[glusterBitrotSkippedFiles].Set(labels, float64(node.SkippedFiles))
+			if node.LastScrubTime > 0 {
+				bitrotGaugeVecs[glusterBitrotLastScrub].Set(labels, float64(node.LastScrubTime))
+			}
+			bitrotGaugeVecs[glusterBitrotLastScrubDuration].Set(labels, float64(node.LastScrubDuration))
+			bitrotGaugeVecs[glusterBitrotScrubErrors].Set(labels, float64(node.ErrorCount))
+			bitrotGaugeVecs[glusterBitrotCorruptedObjects].Set(labels, float64(len(node.CorruptedObjects)))
+		}
+	}
+	return nil
+}
+
+func init() {
+	registerMetric("gluster_bitrot", collectorDefaults{SyncInterval: 60}, bitrot, bitrotGaugeVecs)
+}
@@ -1,13 +1,14 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
-	"os/exec"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 
 	"github.com/gluster/gluster-prometheus/pkg/glusterutils"
@@ -396,10 +397,10 @@ type VGDetails struct {
 	VGExtentFree    string `json:"vg_free_count"`
 }
 
-func getLVS() ([]LVMStat, []ThinPoolStat, error) {
+func getLVS(ctx context.Context) ([]LVMStat, []ThinPoolStat, error) {
 	cmd := "lvm vgs --unquoted --reportformat=json --noheading --nosuffix --units m -o lv_uuid,lv_name,data_percent,pool_lv,lv_attr,lv_size,lv_path,lv_metadata_size,metadata_percent,vg_name,vg_extent_count,vg_free_count"
 
-	out, err := exec.Command("sh", "-c", cmd).Output() // #nosec
+	out, err := glusterutils.ExecuteCmdContext(ctx, cmd)
 	lvmDet := []LVMStat{}
 	thinPool := []ThinPoolStat{}
 	var vgExtentFreeTemp float64
@@ -505,20 +506,54 @@ type ProcMounts struct {
 	MountOptions string
 }
 
-func parseProcMounts() ([]ProcMounts, error) {
+// unescapeMountField reverts the octal escapes of the space, tab,
+// newline and backslash in the fields of '/proc/mounts', like '\040'
+func unescapeMountField(field string) string {
+	if !strings.Contains(field, "\\") {
+		return field
+	}
+	var unescaped strings.Builder
+	for idx := 0; idx < len(field); idx++ {
+		if field[idx] == '\\' && idx+3 < len(field) {
+			if c, err := strconv.ParseUint(field[idx+1:idx+4], 8, 8); err == nil {
+				unescaped.WriteByte(byte(c))
+				idx += 3
+				continue
+			}
+		}
+		unescaped.WriteByte(field[idx])
+	}
+	return unescaped.String()
+}
+
+// parseMounts parses the content of '/proc/mounts', the mount
+// points and the devices are unescaped
+func parseMounts(content string) []ProcMounts {
 	procMounts := []ProcMounts{}
+	for _, line := range strings.Split(content, "\n") {
+		tokens := strings.Fields(line)
+		if len(tokens) < 4 {
+			continue
+		}
+		procMounts = append(procMounts, ProcMounts{
+			Name:         unescapeMountField(tokens[1]),
+			Device:       unescapeMountField(tokens[0]),
+			FSType:       tokens[2],
+			MountOptions: tokens[3],
+		})
+	}
+	return procMounts
+}
+
+// parseProcMounts returns all the mounts, the local file systems with a
+// device path and the network file systems like the gluster mounts,
+// whose device is '<server>:/<volume>'
+func parseProcMounts() ([]ProcMounts, error) {
 	b, err := ioutil.ReadFile("/proc/mounts")
 	if err != nil {
-		return procMounts, err
+		return []ProcMounts{}, err
 	}
-	for _, line := range strings.Split(string(b), "\n") {
-		if strings.HasPrefix(line, "/") {
-			tokens := strings.Fields(line)
-			procMounts = append(procMounts,
-				ProcMounts{Name: tokens[1], Device: tokens[0], FSType: tokens[2], MountOptions: tokens[3]})
-		}
-	}
-	return procMounts, nil
+	return parseMounts(string(b)), nil
 }
 
 func getGlusterLVMLabels(brick glusterutils.Brick, subvol string, stat LVMStat) prometheus.Labels {
@@ -547,18 +582,22 @@ func getGlusterThinPoolLabels(brick glusterutils.Brick, vol string, subvol strin
 	}
 }
 
-func lvmUsage(path string) (stats []LVMStat, thinPoolStats []ThinPoolStat, err error) {
+func lvmUsage(ctx context.Context, path string) (stats []LVMStat, thinPoolStats []ThinPoolStat, err error) {
 	mountPoints, err := parseProcMounts()
 	if err != nil {
 		return stats, thinPoolStats, err
 	}
 	var thinPoolNames []string
-	lvs, tpStats, err := getLVS()
+	lvs, tpStats, err := getLVS(ctx)
 	if err != nil {
 		return stats, thinPoolStats, err
 	}
 	for _, lv := range lvs {
 		for _, mount := range mountPoints {
+			if !strings.HasPrefix(mount.Device, "/") {
+				// not a block device
+				continue
+			}
 			dev, err := filepath.EvalSymlinks(mount.Device)
 			if err != nil {
 				log.WithError(err).WithFields(log.Fields{
@@ -589,20 +628,75 @@ func lvmUsage(path string) (stats []LVMStat, thinPoolStats []ThinPoolStat, err e
 	return stats, thinPoolStats, nil
 }
 
-func brickUtilization(gluster glusterutils.GInterface) error {
+var (
+	// lastVolumes are the volumes found by the last successful call
+	// of 'getLastKnownVolumes', used while glusterd is not reachable
+	lastVolumes     []glusterutils.Volume
+	lastVolumesLock sync.Mutex
+)
+
+// getLastKnownVolumes returns the volume info. The volumes do not change
+// often, so if glusterd is not reachable the last known volumes are
+// returned, for the collectors reading the local bricks and processes
+func getLastKnownVolumes(ctx context.Context, gluster glusterutils.GInterface) ([]glusterutils.Volume, error) {
+	lastVolumesLock.Lock()
+	defer lastVolumesLock.Unlock()
+
+	volumes, err := gluster.VolumeInfo(ctx)
+	if err != nil {
+		if lastVolumes == nil {
+			return nil, err
+		}
+		log.WithError(err).Debug("Unable to get the volume info, using the last known volumes")
+		return lastVolumes, nil
+	}
+	lastVolumes = volumes
+	return volumes, nil
+}
+
+// getLocalBricks returns the bricks of the started volumes hosted on the
+// current node, the same bricks 'brickUtilization' exports. If glusterd
+// is not reachable the bricks of the last known volumes are returned
+func getLocalBricks(ctx context.Context, gluster glusterutils.GInterface) ([]glusterutils.Brick, error) {
+	localPeerID, err := gluster.LocalPeerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	volumes, err := getLastKnownVolumes(ctx, gluster)
+	if err != nil {
+		return nil, err
+	}
+
+	bricks := []glusterutils.Brick{}
+	for _, volume := range volumes {
+		if volume.State != glusterconsts.VolumeStateStarted {
+			continue
+		}
+		for _, subvol := range volume.SubVolumes {
+			for _, brick := range subvol.Bricks {
+				if brick.PeerID == localPeerID {
+					bricks = append(bricks, brick)
+				}
+			}
+		}
+	}
+	return bricks, nil
+}
+
+func brickUtilization(ctx context.Context, gluster glusterutils.GInterface) error {
 	// Reset all vecs to not export stale information
 	for _, gaugeVec := range brickGaugeVecs {
 		gaugeVec.RemoveStaleMetrics()
 	}
 
-	volumes, err := gluster.VolumeInfo()
+	volumes, err := gluster.VolumeInfo(ctx)
 
 	if err != nil {
 		// Return without exporting metric in this cycle
 		return err
 	}
 
-	localPeerID, err := gluster.LocalPeerID()
+	localPeerID, err := gluster.LocalPeerID(ctx)
 	if err != nil {
 		// Return without exporting metric in this cycle
 		return err
@@ -649,7 +743,7 @@ func brickUtilization(gluster glusterutils.GInterface) error {
 						}
 					}
 					// Get lvm usage details
-					stats, thinStats, err := lvmUsage(brick.Path)
+					stats, thinStats, err := lvmUsage(ctx, brick.Path)
 					if err != nil {
 						log.WithError(err).WithFields(log.Fields{
 							"volume":     volume.Name,
@@ -713,13 +807,13 @@ func getBrickStatusLabels(vol string, host string, brickPath string, peerID stri
 	}
 }
 
-func brickStatus(gluster glusterutils.GInterface) error {
+func brickStatus(ctx context.Context, gluster glusterutils.GInterface) error {
 	// Reset all vecs to not export stale information
 	for _, gaugeVec := range brickStatusGaugeVecs {
 		gaugeVec.RemoveStaleMetrics()
 	}
 
-	isLeader, err := gluster.IsLeader()
+	isLeader, err := gluster.IsLeader(ctx)
 
 	if err != nil {
 		log.WithError(err).Debug("Unable to find if the current node is leader")
@@ -730,7 +824,7 @@ func brickStatus(gluster glusterutils.GInterface) error {
 		return nil
 	}
 
-	volumes, err := gluster.VolumeInfo()
+	volumes, err := gluster.VolumeInfo(ctx)
 	if err != nil {
 		return err
 	}
@@ -753,7 +847,7 @@ func brickStatus(gluster glusterutils.GInterface) error {
 				}
 			}
 		} else {
-			brickStatus, err = gluster.VolumeBrickStatus(volume.Name)
+			brickStatus, err = gluster.VolumeBrickStatus(ctx, volume.Name)
 			if err != nil {
 				log.WithError(err).WithFields(log.Fields{
 					"volume": volume.Name,
@@ -771,6 +865,6 @@ func brickStatus(gluster glusterutils.GInterface) error {
 }
 
 func init() {
-	registerMetric("gluster_brick", brickUtilization)
-	registerMetric("gluster_brick_status", brickStatus)
+	registerMetric("gluster_brick", collectorDefaults{SyncInterval: 5}, brickUtilization, brickGaugeVecs)
+	registerMetric("gluster_brick_status", collectorDefaults{SyncInterval: 15}, brickStatus, brickStatusGaugeVecs)
 }
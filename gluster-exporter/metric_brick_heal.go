@@ -0,0 +1,275 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gluster/gluster-prometheus/pkg/glusterutils"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultHealIndexScanLimit is the max no of entries
+	// read from each index directory of a brick
+	defaultHealIndexScanLimit = 10000
+	// defaultHealIndexScanRate is the max no of entries
+	// read per second, across all the local bricks
+	defaultHealIndexScanRate = 10000
+	// healIndexScanBatch is the no of entries read at once
+	healIndexScanBatch = 1000
+)
+
+// errIndexScanLimit stops the scan of the nested index directories
+var errIndexScanLimit = errors.New("index scan limit reached")
+
+var (
+	// healIndexes are the index directories under '<brick>/.glusterfs/indices'
+	// holding the entries pending heal, with the prefix of their base
+	// entry, which is not an entry pending heal
+	healIndexes = []struct {
+		name       string
+		basePrefix string
+	}{
+		{name: "xattrop", basePrefix: "xattrop-"},
+		{name: "dirty", basePrefix: "dirty-"},
+		{name: "entry-changes"},
+	}
+
+	brickHealLabels = []MetricLabel{
+		clusterIDLabel,
+		{
+			Name: "host",
+			Help: "Host name or IP",
+		},
+		{
+			Name: "volume",
+			Help: "Volume Name",
+		},
+		{
+			Name: "brick_path",
+			Help: "Brick Path",
+		},
+		{
+			Name: "index",
+			Help: "Index directory, xattrop, dirty or entry-changes",
+		},
+	}
+
+	brickHealGaugeVecs = make(map[string]*ExportedGaugeVec)
+
+	glusterBrickHealPendingEntries = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "brick_heal_pending_entries",
+		Help:      "No of entries pending heal in the index directory of the local brick",
+		LongHelp: "Counted from the brick without glusterd. The count stops at the " +
+			"'heal-index-scan-limit' of the collector, lowered so that the scan of all the " +
+			"index directories of the local bricks at the 'heal-index-scan-rate' ends within " +
+			"half the sync interval, or the scrape timeout, see gluster_brick_heal_index_scan_truncated",
+		Labels: brickHealLabels,
+	}, &brickHealGaugeVecs)
+
+	glusterBrickHealIndexScanTruncated = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "brick_heal_index_scan_truncated",
+		Help:      "Whether the scan of the index directory stopped at the scan limit (1-truncated, 0-complete)",
+		Labels:    brickHealLabels,
+	}, &brickHealGaugeVecs)
+)
+
+func getBrickHealLabels(brick glusterutils.Brick, index string) prometheus.Labels {
+	return prometheus.Labels{
+		"cluster_id": clusterID,
+		"host":       brick.Host,
+		"volume":     brick.VolumeName,
+		"brick_path": brick.Path,
+		"index":      index,
+	}
+}
+
+// indexScanner counts the entries of the index directories, reading at
+// most 'limit' entries of each directory and 'rate' entries per second
+type indexScanner struct {
+	limit   uint64
+	rate    uint64
+	scanned uint64
+	start   time.Time
+}
+
+func newIndexScanner(limit, rate uint64) *indexScanner {
+	if limit == 0 {
+		limit = defaultHealIndexScanLimit
+	}
+	if rate == 0 {
+		rate = defaultHealIndexScanRate
+	}
+	return &indexScanner{limit: limit, rate: rate, start: time.Now()}
+}
+
+// fitLimit lowers the limit, so that the scan of all the index
+// directories of the bricks at the rate ends within the given time
+func (s *indexScanner) fitLimit(bricks int, within time.Duration) {
+	if bricks == 0 || within <= 0 {
+		return
+	}
+	limit := uint64(within.Seconds()*float64(s.rate)) / uint64(bricks*len(healIndexes))
+	if limit < 1 {
+		limit = 1
+	}
+	if limit < s.limit {
+		s.limit = limit
+	}
+}
+
+// throttle waits till the entries scanned so far are within the rate
+func (s *indexScanner) throttle(ctx context.Context) error {
+	expected := time.Duration(float64(s.scanned) / float64(s.rate) * float64(time.Second))
+	wait := expected - time.Since(s.start)
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// scanDir calls fn for each entry of the directory, till 'limit' entries
+// are read. It returns true if the scan stopped at the limit
+func (s *indexScanner) scanDir(ctx context.Context, dir string, limit uint64, fn func(name string) error) (bool, error) {
+	f, err := os.Open(dir) // #nosec
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	var read uint64
+	for read < limit {
+		if err := s.throttle(ctx); err != nil {
+			return false, err
+		}
+		batch := uint64(healIndexScanBatch)
+		if limit-read < batch {
+			batch = limit - read
+		}
+		names, err := f.Readdirnames(int(batch))
+		s.scanned += uint64(len(names))
+		read += uint64(len(names))
+		for _, name := range names {
+			if err := fn(name); err != nil {
+				return false, err
+			}
+		}
+		if err == io.EOF {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+	// the directory has more entries if the next read is not at EOF
+	more, err := f.Readdirnames(1)
+	return len(more) > 0 && err == nil, nil
+}
+
+// countIndex counts the entries pending heal in the index directory, and
+// returns true if the count stopped at the limit. The 'entry-changes'
+// directory has a directory for each parent directory pending heal,
+// holding its changed entries
+func (s *indexScanner) countIndex(ctx context.Context, brickPath string, index string, basePrefix string) (uint64, bool, error) {
+	dir := filepath.Join(brickPath, ".glusterfs", "indices", index)
+	var count uint64
+	if index == "entry-changes" {
+		truncated, err := s.scanDir(ctx, dir, s.limit, func(name string) error {
+			if count >= s.limit {
+				return errIndexScanLimit
+			}
+			subTruncated, err := s.scanDir(ctx, filepath.Join(dir, name), s.limit-count, func(string) error {
+				count++
+				return nil
+			})
+			// the parent directory is removed once healed
+			if err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			if subTruncated {
+				return errIndexScanLimit
+			}
+			return nil
+		})
+		if err == errIndexScanLimit {
+			return count, true, nil
+		}
+		return count, truncated, err
+	}
+	truncated, err := s.scanDir(ctx, dir, s.limit, func(name string) error {
+		if basePrefix == "" || !strings.HasPrefix(name, basePrefix) {
+			count++
+		}
+		return nil
+	})
+	return count, truncated, err
+}
+
+func brickHealIndex(ctx context.Context, gluster glusterutils.GInterface) error {
+	// Reset all vecs to not export stale information
+	for _, gaugeVec := range brickHealGaugeVecs {
+		gaugeVec.RemoveStaleMetrics()
+	}
+
+	bricks, err := getLocalBricks(ctx, gluster)
+	if err != nil {
+		// Return without exporting metric in this cycle
+		return err
+	}
+
+	collectorConf := getCollectorConf("gluster_brick_heal")
+	scanner := newIndexScanner(collectorConf.HealIndexScanLimit, collectorConf.HealIndexScanRate)
+	// the scan leaves half the sync interval, or of the time left
+	// till the scrape timeout, to the next run
+	within := time.Duration(collectorConf.SyncInterval) * time.Second / 2
+	if deadline, ok := ctx.Deadline(); ok {
+		within = time.Until(deadline) / 2
+	}
+	scanner.fitLimit(len(bricks), within)
+	for _, brick := range bricks {
+		for _, index := range healIndexes {
+			count, truncated, err := scanner.countIndex(ctx, brick.Path, index.name, index.basePrefix)
+			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				// the index directories are created by the index
+				// translator, not all of them exist on all the bricks
+				if !os.IsNotExist(err) {
+					log.WithError(err).WithFields(log.Fields{
+						"volume":     brick.VolumeName,
+						"brick_path": brick.Path,
+						"index":      index.name,
+					}).Debug("Error scanning the heal index")
+				}
+				continue
+			}
+			labels := getBrickHealLabels(brick, index.name)
+			brickHealGaugeVecs[glusterBrickHealPendingEntries].Set(labels, float64(count))
+			brickHealGaugeVecs[glusterBrickHealIndexScanTruncated].Set(labels, boolToFloat(truncated))
+		}
+	}
+	return nil
+}
+
+func init() {
+	registerMetric("gluster_brick_heal", collectorDefaults{SyncInterval: 60}, brickHealIndex, brickHealGaugeVecs)
+}
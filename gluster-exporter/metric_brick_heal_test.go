@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// makeIndex creates the index directory of the brick with the entries,
+// the entries with a '/' are created in their parent directory
+func makeIndex(t *testing.T, brickPath, index string, entries ...string) {
+	dir := filepath.Join(brickPath, ".glusterfs", "indices", index)
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry)
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, nil, 0640); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestIndexScanner(t *testing.T) {
+	brickPath, err := ioutil.TempDir("", "brick")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(brickPath)
+	makeIndex(t, brickPath, "xattrop", "xattrop-0a1b", "gfid-1", "gfid-2", "gfid-3")
+	makeIndex(t, brickPath, "dirty", "dirty-0a1b", "gfid-4")
+	makeIndex(t, brickPath, "entry-changes", "parent-1/name-1", "parent-1/name-2", "parent-2/name-3")
+	// a parent directory removed once healed, while it is listed
+	if err := os.Symlink(filepath.Join(brickPath, "healed"),
+		filepath.Join(brickPath, ".glusterfs", "indices", "entry-changes", "parent-3")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		index     string
+		limit     uint64
+		count     uint64
+		truncated bool
+	}{
+		// the base entries are not pending heal
+		{index: "xattrop", limit: 10, count: 3},
+		{index: "dirty", limit: 10, count: 1},
+		{index: "entry-changes", limit: 10, count: 3},
+		// the scan reads 'limit' entries, the base entry included
+		{index: "xattrop", limit: 4, count: 3},
+		{index: "entry-changes", limit: 2, count: 2, truncated: true},
+	}
+	for _, tt := range tests {
+		basePrefix := ""
+		for _, index := range healIndexes {
+			if index.name == tt.index {
+				basePrefix = index.basePrefix
+			}
+		}
+		scanner := newIndexScanner(tt.limit, 1000000)
+		count, truncated, err := scanner.countIndex(context.Background(), brickPath, tt.index, basePrefix)
+		if err != nil {
+			t.Errorf("%s: countIndex failed: %s", tt.index, err)
+			continue
+		}
+		if count != tt.count || truncated != tt.truncated {
+			t.Errorf("%s (limit %d): expected %d entries (truncated %v), got %d (truncated %v)",
+				tt.index, tt.limit, tt.count, tt.truncated, count, truncated)
+		}
+	}
+
+	// the xattrop index has 4 entries, the scan stops at
+	// the limit before or after the base entry
+	scanner := newIndexScanner(2, 1000000)
+	count, truncated, err := scanner.countIndex(context.Background(), brickPath, "xattrop", "xattrop-")
+	if err != nil || !truncated || count < 1 || count > 2 {
+		t.Errorf("expected the scan to be truncated, got %d entries (truncated %v, %v)", count, truncated, err)
+	}
+
+	_, _, err = newIndexScanner(10, 1000000).countIndex(context.Background(), filepath.Join(brickPath, "missing"), "xattrop", "xattrop-")
+	if !os.IsNotExist(err) {
+		t.Errorf("expected a missing index to fail with ENOENT, got %v", err)
+	}
+}
+
+func TestIndexScannerFitLimit(t *testing.T) {
+	scanner := newIndexScanner(0, 0)
+	if scanner.limit != defaultHealIndexScanLimit || scanner.rate != defaultHealIndexScanRate {
+		t.Fatalf("expected the defaults, got %d at %d/s", scanner.limit, scanner.rate)
+	}
+	// 5 bricks with 3 indices in 15s at 10000/s
+	scanner.fitLimit(5, 15*time.Second)
+	if scanner.limit != defaultHealIndexScanLimit {
+		t.Errorf("expected the limit to fit, got %d", scanner.limit)
+	}
+	// 10 bricks with 3 indices in 15s
+	scanner.fitLimit(10, 15*time.Second)
+	if scanner.limit != 5000 {
+		t.Errorf("expected the limit to be lowered to 5000, got %d", scanner.limit)
+	}
+	scanner.fitLimit(10, time.Millisecond)
+	if scanner.limit != 1 {
+		t.Errorf("expected the limit to be at least 1, got %d", scanner.limit)
+	}
+}
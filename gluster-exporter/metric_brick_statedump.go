@@ -0,0 +1,376 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/gluster/gluster-prometheus/pkg/glusterutils"
+	"github.com/gluster/gluster-prometheus/pkg/glusterutils/glusterconsts"
+	"github.com/gluster/gluster-prometheus/pkg/proc"
+	"github.com/gluster/gluster-prometheus/pkg/statedump"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// statedumpWait is the max time to wait for the brick
+	// process to write the triggered statedump
+	statedumpWait = 10 * time.Second
+	// statedumpPollInterval is the interval to check
+	// if the triggered statedump is written
+	statedumpPollInterval = 200 * time.Millisecond
+)
+
+var (
+	brickStatedumpLabels = []MetricLabel{
+		clusterIDLabel,
+		{
+			Name: "volume",
+			Help: "Volume Name",
+		},
+		{
+			Name: "hostname",
+			Help: "Hostname of the brick",
+		},
+		{
+			Name: "brick_path",
+			Help: "Path of the brick",
+		},
+	}
+
+	brickXlatorMemLabels = []MetricLabel{
+		clusterIDLabel,
+		{
+			Name: "volume",
+			Help: "Volume Name",
+		},
+		{
+			Name: "hostname",
+			Help: "Hostname of the brick",
+		},
+		{
+			Name: "brick_path",
+			Help: "Path of the brick",
+		},
+		{
+			Name: "xlator",
+			Help: "Name of the translator, 'glusterfs' for the memory accounted outside the translators",
+		},
+		{
+			Name: "xlator_type",
+			Help: "Type of the translator, like `protocol/server`, 'global' for the memory accounted outside the translators",
+		},
+	}
+
+	brickStatedumpMempoolLabels = []MetricLabel{
+		clusterIDLabel,
+		{
+			Name: "volume",
+			Help: "Volume Name",
+		},
+		{
+			Name: "hostname",
+			Help: "Hostname of the brick",
+		},
+		{
+			Name: "brick_path",
+			Help: "Path of the brick",
+		},
+		{
+			Name: "pool",
+			Help: "Name of the memory pool, prefixed by its translator",
+		},
+	}
+
+	brickStatedumpGaugeVecs = make(map[string]*ExportedGaugeVec)
+
+	glusterBrickStatedumpTimestamp = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "brick_statedump_timestamp_seconds",
+		Help:      "Unix timestamp of the statedump of the brick process the metrics are read from",
+		Labels:    brickStatedumpLabels,
+	}, &brickStatedumpGaugeVecs)
+
+	glusterBrickXlatorMemory = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "brick_xlator_memory_bytes",
+		Help:      "Bytes of memory allocated by the translator of the brick process",
+		LongHelp: "The sum of all the memory types of the translator, " +
+			"from the memory accounting of the statedump",
+		Labels: brickXlatorMemLabels,
+	}, &brickStatedumpGaugeVecs)
+
+	glusterBrickXlatorMemoryAllocs = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "brick_xlator_memory_allocs",
+		Help:      "No of memory allocations in use by the translator of the brick process",
+		Labels:    brickXlatorMemLabels,
+	}, &brickStatedumpGaugeVecs)
+
+	glusterBrickXlatorMemoryAllocsTotal = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "brick_xlator_memory_allocs_total",
+		Help:      "No of memory allocations made by the translator of the brick process since it started",
+		Labels:    brickXlatorMemLabels,
+		Counter:   true,
+	}, &brickStatedumpGaugeVecs)
+
+	glusterBrickStatedumpMempoolHotCount = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "brick_statedump_mempool_hot_count",
+		Help:      "No of objects of the memory pool in use, from the statedump",
+		Labels:    brickStatedumpMempoolLabels,
+	}, &brickStatedumpGaugeVecs)
+
+	glusterBrickStatedumpMempoolColdCount = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "brick_statedump_mempool_cold_count",
+		Help:      "No of objects of the memory pool available for allocation, from the statedump",
+		LongHelp:  "The recent gluster versions do not report it",
+		Labels:    brickStatedumpMempoolLabels,
+	}, &brickStatedumpGaugeVecs)
+
+	glusterBrickStatedumpMempoolHotBytes = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "brick_statedump_mempool_hot_bytes",
+		Help:      "Bytes of the objects of the memory pool in use, from the statedump",
+		Labels:    brickStatedumpMempoolLabels,
+	}, &brickStatedumpGaugeVecs)
+
+	glusterBrickStatedumpMempoolAllocCount = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "brick_statedump_mempool_alloc_count",
+		Help:      "No of objects allocated from the memory pool since the brick started, from the statedump",
+		Labels:    brickStatedumpMempoolLabels,
+	}, &brickStatedumpGaugeVecs)
+
+	glusterBrickStatedumpMempoolMisses = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "brick_statedump_mempool_misses",
+		Help:      "No of allocations that fell back to the heap as the memory pool was exhausted, from the statedump",
+		Labels:    brickStatedumpMempoolLabels,
+	}, &brickStatedumpGaugeVecs)
+)
+
+func getBrickStatedumpLabels(brick glusterutils.Brick) prometheus.Labels {
+	return prometheus.Labels{
+		"cluster_id": clusterID,
+		"volume":     brick.VolumeName,
+		"hostname":   brick.Host,
+		"brick_path": brick.Path,
+	}
+}
+
+func getBrickXlatorMemLabels(brick glusterutils.Brick, xlatorType, xlator string) prometheus.Labels {
+	labels := getBrickStatedumpLabels(brick)
+	labels["xlator"] = xlator
+	labels["xlator_type"] = xlatorType
+	return labels
+}
+
+func getBrickStatedumpMempoolLabels(brick glusterutils.Brick, pool string) prometheus.Labels {
+	labels := getBrickStatedumpLabels(brick)
+	labels["pool"] = pool
+	return labels
+}
+
+// brickPIDs returns the pids of the brick processes by their volume and
+// brick path. A multiplexed brick process is only found by its first brick
+func brickPIDs() (map[string]int, error) {
+	procs, err := proc.NewFS(proc.DefaultRoot).Find(func(args []string) bool {
+		p, ok := glusterutils.ClassifyProcess(args)
+		return ok && p.Role == glusterconsts.ProcessRoleBrick
+	})
+	if err != nil {
+		return nil, err
+	}
+	pids := make(map[string]int)
+	for _, p := range procs {
+		glusterProc, _ := glusterutils.ClassifyProcess(p.Args)
+		pids[glusterProc.Volume+":"+glusterProc.BrickPath] = p.PID
+	}
+	return pids, nil
+}
+
+// triggerStatedump sends SIGUSR1 to the brick process, and waits for
+// the complete statedump newer than the last one. The statedump
+// is removed once read, to not fill the statedump directory
+func triggerStatedump(ctx context.Context, dir string, prefix string, pid int) (*statedump.Dump, string, error) {
+	last, _ := statedump.Latest(dir, prefix)
+	if err := syscall.Kill(pid, syscall.SIGUSR1); err != nil {
+		return nil, "", err
+	}
+
+	ticker := time.NewTicker(statedumpPollInterval)
+	defer ticker.Stop()
+	timeout := time.NewTimer(statedumpWait)
+	defer timeout.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, "", ctx.Err()
+		case <-timeout.C:
+			return nil, "", os.ErrNotExist
+		case <-ticker.C:
+		}
+		path, err := statedump.Latest(dir, prefix)
+		if err != nil || path == last {
+			continue
+		}
+		dump, err := statedump.ParseFile(path)
+		if err != nil {
+			return nil, "", err
+		}
+		if !dump.Complete {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			log.WithError(err).WithField("path", path).Debug("[Gluster Brick Statedump] Error removing the statedump")
+		}
+		return dump, path, nil
+	}
+}
+
+// latestStatedump returns the newest complete statedump of the brick process
+func latestStatedump(dir string, prefix string) (*statedump.Dump, string, error) {
+	path, err := statedump.Latest(dir, prefix)
+	if err != nil {
+		return nil, "", err
+	}
+	dump, err := statedump.ParseFile(path)
+	if err != nil {
+		return nil, "", err
+	}
+	if !dump.Complete {
+		return nil, "", os.ErrNotExist
+	}
+	return dump, path, nil
+}
+
+// statedumpTimestamp returns the timestamp of the statedump, the
+// suffix of its file name
+func statedumpTimestamp(path string, prefix string) float64 {
+	ts, err := strconv.ParseInt(strings.TrimPrefix(filepath.Base(path), prefix), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return float64(ts)
+}
+
+func exportBrickStatedump(brick glusterutils.Brick, dump *statedump.Dump) {
+	type xlatorKey struct {
+		xlatorType string
+		xlator     string
+	}
+	type xlatorMem struct {
+		size        uint64
+		allocs      uint64
+		totalAllocs uint64
+	}
+	// the memory accounting lists every memory type of the
+	// translators, they are summed up to limit the labels
+	xlators := make(map[xlatorKey]*xlatorMem)
+	for _, usage := range dump.MemUsage() {
+		key := xlatorKey{xlatorType: usage.XlatorType, xlator: usage.Xlator}
+		mem, ok := xlators[key]
+		if !ok {
+			mem = &xlatorMem{}
+			xlators[key] = mem
+		}
+		mem.size += usage.Size
+		mem.allocs += usage.NumAllocs
+		mem.totalAllocs += usage.TotalAllocs
+	}
+	for key, mem := range xlators {
+		labels := getBrickXlatorMemLabels(brick, key.xlatorType, key.xlator)
+		brickStatedumpGaugeVecs[glusterBrickXlatorMemory].Set(labels, float64(mem.size))
+		brickStatedumpGaugeVecs[glusterBrickXlatorMemoryAllocs].Set(labels, float64(mem.allocs))
+		brickStatedumpGaugeVecs[glusterBrickXlatorMemoryAllocsTotal].Set(labels, float64(mem.totalAllocs))
+	}
+
+	for _, pool := range dump.Mempools() {
+		labels := getBrickStatedumpMempoolLabels(brick, pool.Name)
+		brickStatedumpGaugeVecs[glusterBrickStatedumpMempoolHotCount].Set(labels, float64(pool.HotCount))
+		brickStatedumpGaugeVecs[glusterBrickStatedumpMempoolColdCount].Set(labels, float64(pool.ColdCount))
+		brickStatedumpGaugeVecs[glusterBrickStatedumpMempoolHotBytes].Set(labels, float64(pool.HotCount*pool.PaddedSize))
+		brickStatedumpGaugeVecs[glusterBrickStatedumpMempoolAllocCount].Set(labels, float64(pool.AllocCount))
+		brickStatedumpGaugeVecs[glusterBrickStatedumpMempoolMisses].Set(labels, float64(pool.Misses))
+	}
+}
+
+// brickStatedump exports the memory usage of the local brick processes
+// from their statedumps. It triggers a statedump of each brick process if
+// 'statedump-trigger' is set, else reads the newest existing statedump
+func brickStatedump(ctx context.Context, gluster glusterutils.GInterface) error {
+	// Reset all vecs to not export stale information
+	for _, gaugeVec := range brickStatedumpGaugeVecs {
+		gaugeVec.RemoveStaleMetrics()
+	}
+
+	localPeerID, err := gluster.LocalPeerID(ctx)
+	if err != nil {
+		return err
+	}
+	volumes, err := getLastKnownVolumes(ctx, gluster)
+	if err != nil {
+		return err
+	}
+	pids, err := brickPIDs()
+	if err != nil {
+		return err
+	}
+	trigger := getCollectorConf("gluster_brick_statedump").StatedumpTrigger
+
+	for _, volume := range volumes {
+		if volume.State != glusterconsts.VolumeStateStarted {
+			continue
+		}
+		dir := volume.Options[glusterconsts.StatedumpPathGD1]
+		if dir == "" {
+			dir = glusterconsts.DefaultStatedumpPath
+		}
+		for _, subvol := range volume.SubVolumes {
+			for _, brick := range subvol.Bricks {
+				if brick.PeerID != localPeerID {
+					continue
+				}
+				pid, ok := pids[volume.Name+":"+brick.Path]
+				if !ok {
+					continue
+				}
+				prefix := statedump.BrickPrefix(brick.Path, pid)
+				var dump *statedump.Dump
+				var path string
+				if trigger {
+					dump, path, err = triggerStatedump(ctx, dir, prefix, pid)
+				} else {
+					dump, path, err = latestStatedump(dir, prefix)
+				}
+				if err != nil {
+					if ctx.Err() != nil {
+						return ctx.Err()
+					}
+					log.WithError(err).WithFields(log.Fields{
+						"volume":     volume.Name,
+						"brick_path": brick.Path,
+					}).Debug("[Gluster Brick Statedump] Error getting the statedump")
+					continue
+				}
+				brickStatedumpGaugeVecs[glusterBrickStatedumpTimestamp].Set(getBrickStatedumpLabels(brick),
+					statedumpTimestamp(path, prefix))
+				exportBrickStatedump(brick, dump)
+			}
+		}
+	}
+	return nil
+}
+
+func init() {
+	registerMetric("gluster_brick_statedump", collectorDefaults{SyncInterval: 600, Disabled: true}, brickStatedump, brickStatedumpGaugeVecs)
+}
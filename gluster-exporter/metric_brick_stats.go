@@ -0,0 +1,317 @@
+package main
+
+import (
+	"context"
+
+	"github.com/gluster/gluster-prometheus/pkg/glusterutils"
+	"github.com/gluster/gluster-prometheus/pkg/glusterutils/glusterconsts"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+// defaultStatusDetails are the volume status details collected by
+// gluster_brick_stats, when not configured
+var defaultStatusDetails = []string{"mem", "inode", "fd", "callpool"}
+
+var (
+	brickStatsLabels = []MetricLabel{
+		clusterIDLabel,
+		{
+			Name: "volume",
+			Help: "Volume Name",
+		},
+		{
+			Name: "hostname",
+			Help: "Hostname of the brick",
+		},
+		{
+			Name: "brick_path",
+			Help: "Path of the brick",
+		},
+	}
+
+	brickMempoolLabels = []MetricLabel{
+		clusterIDLabel,
+		{
+			Name: "volume",
+			Help: "Volume Name",
+		},
+		{
+			Name: "hostname",
+			Help: "Hostname of the brick",
+		},
+		{
+			Name: "brick_path",
+			Help: "Path of the brick",
+		},
+		{
+			Name: "pool",
+			Help: "Name of the memory pool, prefixed by its translator",
+		},
+	}
+
+	brickStatsGaugeVecs = make(map[string]*ExportedGaugeVec)
+
+	glusterBrickMallinfoArena = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "brick_mallinfo_arena_bytes",
+		Help:      "Bytes of the heap allocated by the brick process with sbrk (mallinfo arena)",
+		Labels:    brickStatsLabels,
+	}, &brickStatsGaugeVecs)
+
+	glusterBrickMallinfoMmap = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "brick_mallinfo_mmap_bytes",
+		Help:      "Bytes allocated by the brick process with mmap (mallinfo hblkhd)",
+		Labels:    brickStatsLabels,
+	}, &brickStatsGaugeVecs)
+
+	glusterBrickMallinfoUsed = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "brick_mallinfo_used_bytes",
+		Help:      "Bytes of the heap in use by the brick process (mallinfo uordblks)",
+		Labels:    brickStatsLabels,
+	}, &brickStatsGaugeVecs)
+
+	glusterBrickMallinfoFree = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "brick_mallinfo_free_bytes",
+		Help:      "Bytes of the heap free in the brick process (mallinfo fordblks)",
+		Labels:    brickStatsLabels,
+	}, &brickStatsGaugeVecs)
+
+	glusterBrickMempoolHotCount = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "brick_mempool_hot_count",
+		Help:      "No of objects of the memory pool in use by the brick process",
+		Labels:    brickMempoolLabels,
+	}, &brickStatsGaugeVecs)
+
+	glusterBrickMempoolColdCount = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "brick_mempool_cold_count",
+		Help:      "No of free objects of the memory pool of the brick process",
+		Labels:    brickMempoolLabels,
+	}, &brickStatsGaugeVecs)
+
+	glusterBrickMempoolAllocCount = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "brick_mempool_alloc_count",
+		Help:      "No of allocations from the memory pool of the brick process",
+		Labels:    brickMempoolLabels,
+	}, &brickStatsGaugeVecs)
+
+	glusterBrickMempoolMaxAlloc = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "brick_mempool_max_alloc",
+		Help:      "Max no of objects of the memory pool in use at once by the brick process",
+		Labels:    brickMempoolLabels,
+	}, &brickStatsGaugeVecs)
+
+	glusterBrickMempoolMisses = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "brick_mempool_misses",
+		Help:      "No of allocations the memory pool of the brick process could not serve",
+		LongHelp:  "These allocations are served from the heap instead",
+		Labels:    brickMempoolLabels,
+	}, &brickStatsGaugeVecs)
+
+	glusterBrickInodeTableActive = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "brick_inode_table_active",
+		Help:      "No of active inodes in the inode tables of the brick process",
+		Labels:    brickStatsLabels,
+	}, &brickStatsGaugeVecs)
+
+	glusterBrickInodeTableLRU = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "brick_inode_table_lru",
+		Help:      "No of inodes in the LRU lists of the inode tables of the brick process",
+		Labels:    brickStatsLabels,
+	}, &brickStatsGaugeVecs)
+
+	glusterBrickInodeTablePurge = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "brick_inode_table_purge",
+		Help:      "No of inodes waiting to be purged from the inode tables of the brick process",
+		Labels:    brickStatsLabels,
+	}, &brickStatsGaugeVecs)
+
+	glusterBrickOpenFds = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "brick_open_fds",
+		Help:      "No of fds open by the clients of the brick",
+		Labels:    brickStatsLabels,
+	}, &brickStatsGaugeVecs)
+
+	glusterBrickPendingCallStacks = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "brick_pending_call_stacks",
+		Help:      "No of requests pending in the call pool of the brick process",
+		Labels:    brickStatsLabels,
+	}, &brickStatsGaugeVecs)
+
+	glusterBrickPendingCallFrames = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "brick_pending_call_frames",
+		Help:      "No of call frames of the requests pending in the call pool of the brick process",
+		Labels:    brickStatsLabels,
+	}, &brickStatsGaugeVecs)
+)
+
+func getBrickStatsLabels(volume, hostname, brickPath string) prometheus.Labels {
+	return prometheus.Labels{
+		"cluster_id": clusterID,
+		"volume":     volume,
+		"hostname":   hostname,
+		"brick_path": brickPath,
+	}
+}
+
+func getBrickMempoolLabels(volume, hostname, brickPath, pool string) prometheus.Labels {
+	return prometheus.Labels{
+		"cluster_id": clusterID,
+		"volume":     volume,
+		"hostname":   hostname,
+		"brick_path": brickPath,
+		"pool":       pool,
+	}
+}
+
+func brickMemStats(ctx context.Context, gluster glusterutils.GInterface, volume string) error {
+	bricks, err := gluster.VolumeMemStatus(ctx, volume)
+	if err != nil {
+		return err
+	}
+	for _, brick := range bricks {
+		if brick.Status != 1 {
+			continue
+		}
+		labels := getBrickStatsLabels(volume, brick.Hostname, brick.Path)
+		brickStatsGaugeVecs[glusterBrickMallinfoArena].Set(labels, float64(brick.Mallinfo.Arena))
+		brickStatsGaugeVecs[glusterBrickMallinfoMmap].Set(labels, float64(brick.Mallinfo.Hblkhd))
+		brickStatsGaugeVecs[glusterBrickMallinfoUsed].Set(labels, float64(brick.Mallinfo.Uordblks))
+		brickStatsGaugeVecs[glusterBrickMallinfoFree].Set(labels, float64(brick.Mallinfo.Fordblks))
+		for _, pool := range brick.Mempools {
+			poolLabels := getBrickMempoolLabels(volume, brick.Hostname, brick.Path, pool.Name)
+			brickStatsGaugeVecs[glusterBrickMempoolHotCount].Set(poolLabels, float64(pool.HotCount))
+			brickStatsGaugeVecs[glusterBrickMempoolColdCount].Set(poolLabels, float64(pool.ColdCount))
+			brickStatsGaugeVecs[glusterBrickMempoolAllocCount].Set(poolLabels, float64(pool.AllocCount))
+			brickStatsGaugeVecs[glusterBrickMempoolMaxAlloc].Set(poolLabels, float64(pool.MaxAlloc))
+			brickStatsGaugeVecs[glusterBrickMempoolMisses].Set(poolLabels, float64(pool.PoolMisses))
+		}
+	}
+	return nil
+}
+
+func brickInodeStats(ctx context.Context, gluster glusterutils.GInterface, volume string) error {
+	bricks, err := gluster.VolumeInodeStatus(ctx, volume)
+	if err != nil {
+		return err
+	}
+	for _, brick := range bricks {
+		if brick.Status != 1 {
+			continue
+		}
+		labels := getBrickStatsLabels(volume, brick.Hostname, brick.Path)
+		brickStatsGaugeVecs[glusterBrickInodeTableActive].Set(labels, float64(brick.ActiveSize))
+		brickStatsGaugeVecs[glusterBrickInodeTableLRU].Set(labels, float64(brick.LRUSize))
+		brickStatsGaugeVecs[glusterBrickInodeTablePurge].Set(labels, float64(brick.PurgeSize))
+	}
+	return nil
+}
+
+func brickFdStats(ctx context.Context, gluster glusterutils.GInterface, volume string) error {
+	bricks, err := gluster.VolumeFdStatus(ctx, volume)
+	if err != nil {
+		return err
+	}
+	for _, brick := range bricks {
+		if brick.Status != 1 {
+			continue
+		}
+		labels := getBrickStatsLabels(volume, brick.Hostname, brick.Path)
+		brickStatsGaugeVecs[glusterBrickOpenFds].Set(labels, float64(brick.OpenFds))
+	}
+	return nil
+}
+
+func brickCallpoolStats(ctx context.Context, gluster glusterutils.GInterface, volume string) error {
+	bricks, err := gluster.VolumeCallpoolStatus(ctx, volume)
+	if err != nil {
+		return err
+	}
+	for _, brick := range bricks {
+		if brick.Status != 1 {
+			continue
+		}
+		labels := getBrickStatsLabels(volume, brick.Hostname, brick.Path)
+		brickStatsGaugeVecs[glusterBrickPendingCallStacks].Set(labels, float64(brick.CallStacks))
+		brickStatsGaugeVecs[glusterBrickPendingCallFrames].Set(labels, float64(brick.CallFrames))
+	}
+	return nil
+}
+
+// brickStatsFuncs collect the metrics of each volume status detail
+var brickStatsFuncs = map[string]func(context.Context, glusterutils.GInterface, string) error{
+	"mem":      brickMemStats,
+	"inode":    brickInodeStats,
+	"fd":       brickFdStats,
+	"callpool": brickCallpoolStats,
+}
+
+func brickStats(ctx context.Context, gluster glusterutils.GInterface) error {
+	isLeader, err := gluster.IsLeader(ctx)
+
+	// Reset all vecs to not export stale information
+	for _, gaugeVec := range brickStatsGaugeVecs {
+		gaugeVec.RemoveStaleMetrics()
+	}
+
+	if err != nil {
+		// Unable to find out if the current node is leader
+		// Cannot register brick stats metrics at this node
+		log.WithError(err).Debug("Unable to find if the current node is leader")
+		return err
+	}
+	// the status details of all the bricks are
+	// available from any node, export only from the leader
+	if !isLeader {
+		return nil
+	}
+
+	details := getCollectorConf("gluster_brick_stats").StatusDetails
+	if len(details) == 0 {
+		details = defaultStatusDetails
+	}
+
+	volumes, err := gluster.VolumeInfo(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, volume := range volumes {
+		if volume.State != glusterconsts.VolumeStateStarted {
+			continue
+		}
+		for _, detail := range details {
+			fn, ok := brickStatsFuncs[detail]
+			if !ok {
+				log.WithField("detail", detail).Debug("[Gluster Brick Stats] Unknown volume status detail")
+				continue
+			}
+			if err := fn(ctx, gluster, volume.Name); err != nil {
+				log.WithError(err).WithFields(log.Fields{
+					"volume": volume.Name,
+					"detail": detail,
+				}).Debug("[Gluster Brick Stats] Error getting volume status detail")
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func init() {
+	registerMetric("gluster_brick_stats", collectorDefaults{SyncInterval: 60}, brickStats, brickStatsGaugeVecs)
+}
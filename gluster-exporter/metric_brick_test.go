@@ -0,0 +1,24 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseMounts(t *testing.T) {
+	content := `/dev/mapper/vg-brick1 /bricks/brick1 xfs rw,noatime 0 0
+server1:/gv0 /mnt/gv\0400 fuse.glusterfs rw,relatime 0 0
+server1:/gv1 /mnt/back\134slash\011tab fuse.glusterfs rw 0 0
+server1:/gv2 /mnt/\04 fuse.glusterfs rw 0 0
+`
+	expected := []ProcMounts{
+		{Name: "/bricks/brick1", Device: "/dev/mapper/vg-brick1", FSType: "xfs", MountOptions: "rw,noatime"},
+		{Name: "/mnt/gv 0", Device: "server1:/gv0", FSType: "fuse.glusterfs", MountOptions: "rw,relatime"},
+		{Name: "/mnt/back\\slash\ttab", Device: "server1:/gv1", FSType: "fuse.glusterfs", MountOptions: "rw"},
+		// not an escape
+		{Name: "/mnt/\\04", Device: "server1:/gv2", FSType: "fuse.glusterfs", MountOptions: "rw"},
+	}
+	if mounts := parseMounts(content); !reflect.DeepEqual(mounts, expected) {
+		t.Errorf("expected %#v, got %#v", expected, mounts)
+	}
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"runtime"
+	"time"
+
+	"github.com/gluster/gluster-prometheus/pkg/glusterutils"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	collectorLabels = []MetricLabel{
+		{
+			Name: "collector",
+			Help: "Name of the collector",
+		},
+	}
+
+	buildInfoLabels = []MetricLabel{
+		{
+			Name: "version",
+			Help: "Version of gluster-exporter",
+		},
+		{
+			Name: "goversion",
+			Help: "Go version used to build gluster-exporter",
+		},
+	}
+
+	collectorDurationMetric = Metric{
+		Namespace: "gluster_exporter",
+		Name:      "collector_duration_seconds",
+		Help:      "Duration of the last run of the collector in seconds",
+		Labels:    collectorLabels,
+	}
+
+	collectorSuccessMetric = Metric{
+		Namespace: "gluster_exporter",
+		Name:      "collector_success",
+		Help:      "Whether the last run of the collector succeeded (1-success, 0-failure)",
+		Labels:    collectorLabels,
+	}
+
+	collectorLastSuccessMetric = Metric{
+		Namespace: "gluster_exporter",
+		Name:      "collector_last_success_timestamp_seconds",
+		Help:      "Unix timestamp of the last successful run of the collector",
+		Labels:    collectorLabels,
+	}
+
+	collectorTimeoutsMetric = Metric{
+		Namespace: "gluster_exporter",
+		Name:      "collector_timeouts_total",
+		Help:      "Total no of runs of the collector which failed with a timeout",
+		Labels:    collectorLabels,
+	}
+
+	collectorErrorsMetric = Metric{
+		Namespace: "gluster_exporter",
+		Name:      "collector_errors_total",
+		Help:      "Total no of failed runs of the collector",
+		Labels:    collectorLabels,
+	}
+
+	gd2EndpointUpMetric = Metric{
+		Namespace: "gluster_exporter",
+		Name:      "gd2_endpoint_up",
+		Help:      "Whether the glusterd2 endpoint is up (1-up, 0-down), it is down from a failed call to the endpoint till a call to it succeeds",
+		Labels: []MetricLabel{
+			{
+				Name: "endpoint",
+				Help: "glusterd2 REST endpoint",
+			},
+		},
+	}
+
+	buildInfoMetric = Metric{
+		Namespace: "gluster_exporter",
+		Name:      "build_info",
+		Help:      "A metric with a constant '1' value labeled by version and goversion of gluster-exporter",
+		Labels:    buildInfoLabels,
+	}
+
+	glusterExporterCollectorDuration    = newSelfGaugeVec(collectorDurationMetric)
+	glusterExporterCollectorSuccess     = newSelfGaugeVec(collectorSuccessMetric)
+	glusterExporterCollectorLastSuccess = newSelfGaugeVec(collectorLastSuccessMetric)
+	glusterExporterBuildInfo            = newSelfGaugeVec(buildInfoMetric)
+
+	glusterExporterCollectorErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: collectorErrorsMetric.Namespace,
+			Name:      collectorErrorsMetric.Name,
+			Help:      collectorErrorsMetric.Help,
+		},
+		collectorErrorsMetric.LabelNames(),
+	)
+
+	glusterExporterCollectorTimeouts = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: collectorTimeoutsMetric.Namespace,
+			Name:      collectorTimeoutsMetric.Name,
+			Help:      collectorTimeoutsMetric.Help,
+		},
+		collectorTimeoutsMetric.LabelNames(),
+	)
+)
+
+// newSelfGaugeVec creates a GaugeVec for the metrics exported about the
+// exporter itself. These are never stale, so they do not need the TTL
+// handling of 'ExportedGaugeVec'
+func newSelfGaugeVec(m Metric) *prometheus.GaugeVec {
+	return prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: m.Namespace,
+			Name:      m.Name,
+			Help:      m.Help,
+		},
+		m.LabelNames(),
+	)
+}
+
+// observeCollectorRun updates the collector metrics after each run
+func observeCollectorRun(name string, duration time.Duration, err error) {
+	lbls := prometheus.Labels{"collector": name}
+	glusterExporterCollectorDuration.With(lbls).Set(duration.Seconds())
+	if err != nil {
+		glusterExporterCollectorSuccess.With(lbls).Set(0)
+		glusterExporterCollectorErrors.With(lbls).Inc()
+		if err == errCollectorTimeout || glusterutils.IsTimeout(err) {
+			glusterExporterCollectorTimeouts.With(lbls).Inc()
+		}
+		return
+	}
+	glusterExporterCollectorSuccess.With(lbls).Set(1)
+	glusterExporterCollectorLastSuccess.With(lbls).Set(float64(time.Now().Unix()))
+}
+
+// gd2EndpointCollector exports the state of the glusterd2 endpoints on scrape
+type gd2EndpointCollector struct {
+	gluster glusterutils.GInterface
+	desc    *prometheus.Desc
+}
+
+func newGD2EndpointCollector(gi glusterutils.GInterface) *gd2EndpointCollector {
+	return &gd2EndpointCollector{
+		gluster: gi,
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(gd2EndpointUpMetric.Namespace, "", gd2EndpointUpMetric.Name),
+			gd2EndpointUpMetric.Help,
+			gd2EndpointUpMetric.LabelNames(),
+			nil,
+		),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *gd2EndpointCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+// Collect implements prometheus.Collector
+func (c *gd2EndpointCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, status := range glusterutils.GD2EndpointStatuses(c.gluster) {
+		var up float64
+		if status.Up {
+			up = 1
+		}
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, up, status.Endpoint)
+	}
+}
+
+// initCollectorMetrics exports the collector metrics for the registered
+// collector, so that the error counter is visible before the first failure
+func initCollectorMetrics(name string) {
+	lbls := prometheus.Labels{"collector": name}
+	glusterExporterCollectorErrors.With(lbls).Add(0)
+	glusterExporterCollectorTimeouts.With(lbls).Add(0)
+}
+
+// removeCollectorMetrics removes the collector metrics of the
+// collector stopped, so that it doesn't look failing or stale
+func removeCollectorMetrics(name string) {
+	lbls := prometheus.Labels{"collector": name}
+	glusterExporterCollectorDuration.Delete(lbls)
+	glusterExporterCollectorSuccess.Delete(lbls)
+	glusterExporterCollectorLastSuccess.Delete(lbls)
+	glusterExporterCollectorErrors.Delete(lbls)
+	glusterExporterCollectorTimeouts.Delete(lbls)
+}
+
+func init() {
+	prometheus.MustRegister(
+		glusterExporterCollectorDuration,
+		glusterExporterCollectorSuccess,
+		glusterExporterCollectorLastSuccess,
+		glusterExporterCollectorErrors,
+		glusterExporterCollectorTimeouts,
+		glusterExporterBuildInfo,
+	)
+	// Add to the global queue for documentation
+	metrics = append(metrics, collectorDurationMetric, collectorSuccessMetric,
+		collectorLastSuccessMetric, collectorErrorsMetric, collectorTimeoutsMetric,
+		gd2EndpointUpMetric, buildInfoMetric)
+
+	glusterExporterBuildInfo.With(prometheus.Labels{
+		"version":   exporterVersion,
+		"goversion": runtime.Version(),
+	}).Set(1)
+}
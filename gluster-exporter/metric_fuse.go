@@ -0,0 +1,289 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gluster/gluster-prometheus/pkg/glusterutils"
+	"github.com/gluster/gluster-prometheus/pkg/glusterutils/glusterconsts"
+	"github.com/gluster/gluster-prometheus/pkg/proc"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// fuseFSType is the file system type of the gluster FUSE mounts
+	fuseFSType = "fuse.glusterfs"
+	// defaultMountTimeout is the time in seconds to wait
+	// for the statfs of a mount before marking it hung
+	defaultMountTimeout = 5
+)
+
+// errMountTimeout is returned when the statfs of the mount does not
+// complete within the timeout, or a previous statfs is still pending
+var errMountTimeout = errors.New("statfs of the mount timed out")
+
+var (
+	fuseMountLabels = []MetricLabel{
+		clusterIDLabel,
+		{
+			Name: "mount_point",
+			Help: "Mount Point of the volume",
+		},
+		{
+			Name: "volume",
+			Help: "Volume Name",
+		},
+		{
+			Name: "server",
+			Help: "Server the volume is mounted from",
+		},
+	}
+
+	fuseMountInfoLabels = []MetricLabel{
+		clusterIDLabel,
+		{
+			Name: "mount_point",
+			Help: "Mount Point of the volume",
+		},
+		{
+			Name: "volume",
+			Help: "Volume Name",
+		},
+		{
+			Name: "server",
+			Help: "Server the volume is mounted from",
+		},
+		{
+			Name: "volfile_server",
+			Help: "Server the client process fetched the volfile from",
+		},
+		{
+			Name: "volfile_id",
+			Help: "Volfile ID of the client process",
+		},
+	}
+
+	fuseGaugeVecs = make(map[string]*ExportedGaugeVec)
+
+	glusterFuseMountInfo = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "fuse_mount_info",
+		Help:      "A metric with a constant '1' value labeled by the volfile server and the volfile ID of the FUSE mount",
+		LongHelp:  "The volfile labels are empty if the client process of the mount is not found",
+		Labels:    fuseMountInfoLabels,
+	}, &fuseGaugeVecs)
+
+	glusterFuseMountResponsive = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "fuse_mount_responsive",
+		Help:      "Whether the statfs of the FUSE mount completed within the timeout (1-responsive, 0-hung or failed)",
+		LongHelp: "The timeout is the 'mount-timeout-in-sec' of the collector. " +
+			"A hung mount is not checked again till its pending statfs completes",
+		Labels: fuseMountLabels,
+	}, &fuseGaugeVecs)
+
+	glusterFuseMountTotal = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "fuse_mount_total_bytes",
+		Help:      "Total capacity of the FUSE mount in bytes",
+		Labels:    fuseMountLabels,
+	}, &fuseGaugeVecs)
+
+	glusterFuseMountFree = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "fuse_mount_free_bytes",
+		Help:      "Free capacity of the FUSE mount in bytes",
+		Labels:    fuseMountLabels,
+	}, &fuseGaugeVecs)
+
+	glusterFuseMountUsed = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "fuse_mount_used_bytes",
+		Help:      "Used capacity of the FUSE mount in bytes",
+		Labels:    fuseMountLabels,
+	}, &fuseGaugeVecs)
+
+	glusterFuseMountInodesTotal = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "fuse_mount_inodes_total",
+		Help:      "Total no of inodes of the FUSE mount",
+		Labels:    fuseMountLabels,
+	}, &fuseGaugeVecs)
+
+	glusterFuseMountInodesFree = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "fuse_mount_inodes_free",
+		Help:      "Free no of inodes of the FUSE mount",
+		Labels:    fuseMountLabels,
+	}, &fuseGaugeVecs)
+
+	glusterFuseClientResidentMemory = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "fuse_client_resident_memory_bytes",
+		Help:      "Resident Memory of the client process of the FUSE mount in bytes",
+		Labels:    fuseMountLabels,
+	}, &fuseGaugeVecs)
+
+	glusterFuseClientCPUSeconds = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "fuse_client_cpu_seconds_total",
+		Help:      "CPU time used by the client process of the FUSE mount in seconds",
+		Labels:    fuseMountLabels,
+		Counter:   true,
+	}, &fuseGaugeVecs)
+)
+
+// pendingStatfs are the mount points with a statfs still running, a
+// hung mount blocks the statfs till the mount recovers or is unmounted
+var pendingStatfs = struct {
+	sync.Mutex
+	mounts map[string]bool
+}{mounts: make(map[string]bool)}
+
+// statfsWithTimeout returns the usage of the mount, or errMountTimeout
+// if the statfs does not complete within the timeout. At most one statfs
+// is run for each mount, to not pile up goroutines on a hung mount
+func statfsWithTimeout(ctx context.Context, mountPoint string, timeout time.Duration) (DiskStatus, error) {
+	pendingStatfs.Lock()
+	if pendingStatfs.mounts[mountPoint] {
+		pendingStatfs.Unlock()
+		return DiskStatus{}, errMountTimeout
+	}
+	pendingStatfs.mounts[mountPoint] = true
+	pendingStatfs.Unlock()
+
+	type result struct {
+		usage DiskStatus
+		err   error
+	}
+	// buffered, the goroutine does not block if the wait timed out
+	done := make(chan result, 1)
+	go func() {
+		usage, err := diskUsage(mountPoint)
+		pendingStatfs.Lock()
+		delete(pendingStatfs.mounts, mountPoint)
+		pendingStatfs.Unlock()
+		done <- result{usage: usage, err: err}
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case res := <-done:
+		return res.usage, res.err
+	case <-ctx.Done():
+		return DiskStatus{}, ctx.Err()
+	case <-timer.C:
+		return DiskStatus{}, errMountTimeout
+	}
+}
+
+// splitMountDevice returns the server and the volume of
+// the FUSE mount device, '<server>:/<volume>'
+func splitMountDevice(device string) (string, string) {
+	parts := strings.SplitN(device, ":", 2)
+	if len(parts) != 2 {
+		return "", strings.TrimPrefix(device, "/")
+	}
+	return parts[0], strings.TrimPrefix(parts[1], "/")
+}
+
+// fuseClients returns the client processes of the FUSE mounts by
+// their mount point, which is the last argument of the process
+func fuseClients() (map[string]proc.Process, error) {
+	procs, err := proc.NewFS(proc.DefaultRoot).Find(func(args []string) bool {
+		p, ok := glusterutils.ClassifyProcess(args)
+		return ok && p.Role == glusterconsts.ProcessRoleFuse
+	})
+	if err != nil {
+		return nil, err
+	}
+	clients := make(map[string]proc.Process)
+	for _, p := range procs {
+		if len(p.Args) > 0 {
+			clients[p.Args[len(p.Args)-1]] = p
+		}
+	}
+	return clients, nil
+}
+
+func getFuseMountLabels(mount ProcMounts) prometheus.Labels {
+	server, volume := splitMountDevice(mount.Device)
+	return prometheus.Labels{
+		"cluster_id":  clusterID,
+		"mount_point": mount.Name,
+		"volume":      volume,
+		"server":      server,
+	}
+}
+
+// fuseMounts exports the FUSE mounts of the volumes on the node, it
+// does not need glusterd and runs on the client nodes as well
+func fuseMounts(ctx context.Context, gluster glusterutils.GInterface) error {
+	// Reset all vecs to not export stale information
+	for _, gaugeVec := range fuseGaugeVecs {
+		gaugeVec.RemoveStaleMetrics()
+	}
+
+	mounts, err := parseProcMounts()
+	if err != nil {
+		// Return without exporting metrics in this cycle
+		return err
+	}
+	clients, err := fuseClients()
+	if err != nil {
+		log.WithError(err).Debug("[Gluster FUSE] Error finding the client processes")
+	}
+
+	timeout := time.Duration(defaultMountTimeout) * time.Second
+	if t := getCollectorConf("gluster_fuse").MountTimeout; t > 0 {
+		timeout = time.Duration(t) * time.Second
+	}
+
+	for _, mount := range mounts {
+		if mount.FSType != fuseFSType {
+			continue
+		}
+		labels := getFuseMountLabels(mount)
+
+		infoLabels := prometheus.Labels{"volfile_server": "", "volfile_id": ""}
+		for k, v := range labels {
+			infoLabels[k] = v
+		}
+		if client, ok := clients[mount.Name]; ok {
+			infoLabels["volfile_server"] = glusterutils.ArgValue(client.Args, "--volfile-server")
+			infoLabels["volfile_id"] = glusterutils.ArgValue(client.Args, "--volfile-id")
+			fuseGaugeVecs[glusterFuseClientResidentMemory].Set(labels, float64(client.ResidentMemory))
+			fuseGaugeVecs[glusterFuseClientCPUSeconds].Set(labels, client.CPUSeconds)
+		}
+		fuseGaugeVecs[glusterFuseMountInfo].Set(infoLabels, 1)
+
+		usage, err := statfsWithTimeout(ctx, mount.Name, timeout)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			log.WithError(err).WithFields(log.Fields{
+				"mount_point": mount.Name,
+			}).Debug("[Gluster FUSE] Error getting the mount usage")
+			fuseGaugeVecs[glusterFuseMountResponsive].Set(labels, 0)
+			continue
+		}
+		fuseGaugeVecs[glusterFuseMountResponsive].Set(labels, 1)
+		fuseGaugeVecs[glusterFuseMountTotal].Set(labels, usage.All)
+		fuseGaugeVecs[glusterFuseMountFree].Set(labels, usage.Free)
+		fuseGaugeVecs[glusterFuseMountUsed].Set(labels, usage.Used)
+		fuseGaugeVecs[glusterFuseMountInodesTotal].Set(labels, usage.InodesAll)
+		fuseGaugeVecs[glusterFuseMountInodesFree].Set(labels, usage.InodesFree)
+	}
+	return nil
+}
+
+func init() {
+	registerMetric("gluster_fuse", collectorDefaults{SyncInterval: 30}, fuseMounts, fuseGaugeVecs)
+}
@@ -0,0 +1,369 @@
+package main
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gluster/gluster-prometheus/pkg/glusterutils"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	// geoRepWorkerStatuses are the states of a geo-replication worker,
+	// each exported as a series of the worker status metric
+	geoRepWorkerStatuses = []string{"Initializing", "Created", "Active",
+		"Passive", "Faulty", "Offline", "Paused", "Stopped"}
+	// geoRepCrawlStatuses are the crawl states of an active worker
+	geoRepCrawlStatuses = []string{"Hybrid Crawl", "History Crawl", "Changelog Crawl"}
+
+	geoRepSessionLabels = []MetricLabel{
+		clusterIDLabel,
+		{
+			Name: "master_volume",
+			Help: "Name of the master volume",
+		},
+		{
+			Name: "slave_host",
+			Help: "Slave Hostname or IP",
+		},
+		{
+			Name: "slave_volume",
+			Help: "Name of the slave volume",
+		},
+		{
+			Name: "slave_user",
+			Help: "User of the geo-replication session on the slave",
+		},
+	}
+
+	geoRepSessionStatusLabels = []MetricLabel{
+		clusterIDLabel,
+		{
+			Name: "master_volume",
+			Help: "Name of the master volume",
+		},
+		{
+			Name: "slave_host",
+			Help: "Slave Hostname or IP",
+		},
+		{
+			Name: "slave_volume",
+			Help: "Name of the slave volume",
+		},
+		{
+			Name: "slave_user",
+			Help: "User of the geo-replication session on the slave",
+		},
+		{
+			Name: "status",
+			Help: "Status of the geo-replication workers",
+		},
+	}
+
+	geoRepWorkerLabels = []MetricLabel{
+		clusterIDLabel,
+		{
+			Name: "master_volume",
+			Help: "Name of the master volume",
+		},
+		{
+			Name: "slave_host",
+			Help: "Slave Hostname or IP",
+		},
+		{
+			Name: "slave_volume",
+			Help: "Name of the slave volume",
+		},
+		{
+			Name: "slave_user",
+			Help: "User of the geo-replication session on the slave",
+		},
+		{
+			Name: "master_host",
+			Help: "Hostname or IP of the master brick",
+		},
+		{
+			Name: "master_brick_path",
+			Help: "Path of the master brick",
+		},
+	}
+
+	geoRepWorkerStatusLabels = []MetricLabel{
+		clusterIDLabel,
+		{
+			Name: "master_volume",
+			Help: "Name of the master volume",
+		},
+		{
+			Name: "slave_host",
+			Help: "Slave Hostname or IP",
+		},
+		{
+			Name: "slave_volume",
+			Help: "Name of the slave volume",
+		},
+		{
+			Name: "slave_user",
+			Help: "User of the geo-replication session on the slave",
+		},
+		{
+			Name: "master_host",
+			Help: "Hostname or IP of the master brick",
+		},
+		{
+			Name: "master_brick_path",
+			Help: "Path of the master brick",
+		},
+		{
+			Name: "status",
+			Help: "Status of the geo-replication worker",
+		},
+	}
+
+	geoRepWorkerCrawlStatusLabels = []MetricLabel{
+		clusterIDLabel,
+		{
+			Name: "master_volume",
+			Help: "Name of the master volume",
+		},
+		{
+			Name: "slave_host",
+			Help: "Slave Hostname or IP",
+		},
+		{
+			Name: "slave_volume",
+			Help: "Name of the slave volume",
+		},
+		{
+			Name: "slave_user",
+			Help: "User of the geo-replication session on the slave",
+		},
+		{
+			Name: "master_host",
+			Help: "Hostname or IP of the master brick",
+		},
+		{
+			Name: "master_brick_path",
+			Help: "Path of the master brick",
+		},
+		{
+			Name: "crawl_status",
+			Help: "Crawl status of the geo-replication worker",
+		},
+	}
+
+	geoRepGaugeVecs = make(map[string]*ExportedGaugeVec)
+
+	glusterGeoRepSessionWorkers = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "georep_session_workers",
+		Help:      "No of workers of the geo-replication session in each status",
+		Labels:    geoRepSessionStatusLabels,
+	}, &geoRepGaugeVecs)
+
+	glusterGeoRepWorkerStatus = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "georep_worker_status",
+		Help:      "Status of the geo-replication worker, 1 for the current status and 0 for the others",
+		LongHelp: "The worker of each master brick is in one of the states Initializing, Created, " +
+			"Active, Passive, Faulty, Offline, Paused or Stopped",
+		Labels: geoRepWorkerStatusLabels,
+	}, &geoRepGaugeVecs)
+
+	glusterGeoRepWorkerCrawlStatus = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "georep_worker_crawl_status",
+		Help:      "Crawl status of the geo-replication worker, 1 for the current crawl status and 0 for the others",
+		LongHelp:  "All the crawl statuses are 0 if the worker is not active",
+		Labels:    geoRepWorkerCrawlStatusLabels,
+	}, &geoRepGaugeVecs)
+
+	glusterGeoRepWorkerLastSynced = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "georep_worker_last_synced_timestamp_seconds",
+		Help:      "Unix timestamp of the master data synced to the slave by the geo-replication worker",
+		LongHelp: "The changes on the master brick till this time are synced to the slave, " +
+			"the geo-replication lag is the time elapsed since then",
+		Labels: geoRepWorkerLabels,
+	}, &geoRepGaugeVecs)
+
+	glusterGeoRepWorkerCheckpoint = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "georep_worker_checkpoint_timestamp_seconds",
+		Help:      "Unix timestamp of the checkpoint set on the geo-replication session",
+		Labels:    geoRepWorkerLabels,
+	}, &geoRepGaugeVecs)
+
+	glusterGeoRepWorkerCheckpointCompleted = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "georep_worker_checkpoint_completed",
+		Help:      "Whether the geo-replication worker completed the checkpoint (1-completed, 0-pending)",
+		Labels:    geoRepWorkerLabels,
+	}, &geoRepGaugeVecs)
+
+	glusterGeoRepWorkerCheckpointCompletion = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "georep_worker_checkpoint_completion_timestamp_seconds",
+		Help:      "Unix timestamp of the checkpoint completion by the geo-replication worker",
+		Labels:    geoRepWorkerLabels,
+	}, &geoRepGaugeVecs)
+
+	glusterGeoRepWorkerPendingEntryOps = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "georep_worker_pending_entry_ops",
+		Help:      "No of entry operations (create, mkdir, rename ...) pending to be synced by the geo-replication worker",
+		Labels:    geoRepWorkerLabels,
+	}, &geoRepGaugeVecs)
+
+	glusterGeoRepWorkerPendingDataOps = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "georep_worker_pending_data_ops",
+		Help:      "No of data operations pending to be synced by the geo-replication worker",
+		Labels:    geoRepWorkerLabels,
+	}, &geoRepGaugeVecs)
+
+	glusterGeoRepWorkerPendingMetaOps = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "georep_worker_pending_meta_ops",
+		Help:      "No of metadata operations (setattr, setxattr ...) pending to be synced by the geo-replication worker",
+		Labels:    geoRepWorkerLabels,
+	}, &geoRepGaugeVecs)
+
+	glusterGeoRepWorkerFailures = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "georep_worker_failures",
+		Help:      "No of sync failures of the geo-replication worker",
+		Labels:    geoRepWorkerLabels,
+	}, &geoRepGaugeVecs)
+)
+
+func getGeoRepSessionLabels(session glusterutils.GeoRepSession) prometheus.Labels {
+	return prometheus.Labels{
+		"cluster_id":    clusterID,
+		"master_volume": session.MasterVolume,
+		"slave_host":    session.SlaveHost,
+		"slave_volume":  session.SlaveVolume,
+		"slave_user":    session.SlaveUser,
+	}
+}
+
+func getGeoRepWorkerLabels(session glusterutils.GeoRepSession, worker glusterutils.GeoRepWorker) prometheus.Labels {
+	labels := getGeoRepSessionLabels(session)
+	labels["master_host"] = worker.MasterNode
+	labels["master_brick_path"] = worker.MasterBrick
+	return labels
+}
+
+// withLabel returns a copy of the labels with the extra label
+func withLabel(labels prometheus.Labels, name, value string) prometheus.Labels {
+	out := make(prometheus.Labels, len(labels)+1)
+	for lname, lvalue := range labels {
+		out[lname] = lvalue
+	}
+	out[name] = value
+	return out
+}
+
+// geoRepStatus normalizes the worker status, like 'Initializing...'
+func geoRepStatus(status string) string {
+	return strings.TrimRight(strings.TrimSpace(status), ".")
+}
+
+// setStateSet exports a series for each of the known states, 1 for the
+// current state and 0 for the others. An unknown current state is
+// exported too, so that it is not hidden
+func setStateSet(gaugeVec string, labels prometheus.Labels, label string, states []string, current string) {
+	known := false
+	for _, state := range states {
+		value := 0.0
+		if state == current {
+			value = 1
+			known = true
+		}
+		geoRepGaugeVecs[gaugeVec].Set(withLabel(labels, label, state), value)
+	}
+	if !known && current != "" && current != "N/A" {
+		geoRepGaugeVecs[gaugeVec].Set(withLabel(labels, label, current), 1)
+	}
+}
+
+func geoRep(ctx context.Context, gluster glusterutils.GInterface) error {
+	isLeader, err := gluster.IsLeader(ctx)
+
+	// Reset all vecs to not export stale information
+	for _, gaugeVec := range geoRepGaugeVecs {
+		gaugeVec.RemoveStaleMetrics()
+	}
+
+	if err != nil {
+		// Unable to find out if the current node is leader
+		// Cannot register geo-replication metrics at this node
+		log.WithError(err).Debug("Unable to find if the current node is leader")
+		return err
+	}
+	// geo-replication status is cluster wide, export only from the leader
+	if !isLeader {
+		return nil
+	}
+
+	sessions, err := gluster.GeoRepStatus(ctx)
+	if err != nil {
+		log.WithError(err).Debug("[Gluster Geo-replication] Error getting geo-replication status")
+		return err
+	}
+
+	for _, session := range sessions {
+		sessionLabels := getGeoRepSessionLabels(session)
+		workers := make(map[string]int)
+		for _, worker := range session.Workers {
+			status := geoRepStatus(worker.Status)
+			workers[status]++
+
+			labels := getGeoRepWorkerLabels(session, worker)
+			setStateSet(glusterGeoRepWorkerStatus, labels, "status", geoRepWorkerStatuses, status)
+			setStateSet(glusterGeoRepWorkerCrawlStatus, labels, "crawl_status", geoRepCrawlStatuses, worker.CrawlStatus)
+
+			if worker.LastSynced > 0 {
+				geoRepGaugeVecs[glusterGeoRepWorkerLastSynced].Set(labels, float64(worker.LastSynced))
+			}
+			if worker.CheckpointTime > 0 {
+				geoRepGaugeVecs[glusterGeoRepWorkerCheckpoint].Set(labels, float64(worker.CheckpointTime))
+				completed := 0.0
+				if worker.CheckpointCompleted {
+					completed = 1
+				}
+				geoRepGaugeVecs[glusterGeoRepWorkerCheckpointCompleted].Set(labels, completed)
+			}
+			if worker.CheckpointCompletionTime > 0 {
+				geoRepGaugeVecs[glusterGeoRepWorkerCheckpointCompletion].Set(labels, float64(worker.CheckpointCompletionTime))
+			}
+
+			// the counters are not available if the worker is not active
+			counters := map[string]int64{
+				glusterGeoRepWorkerPendingEntryOps: worker.PendingEntryOps,
+				glusterGeoRepWorkerPendingDataOps:  worker.PendingDataOps,
+				glusterGeoRepWorkerPendingMetaOps:  worker.PendingMetaOps,
+				glusterGeoRepWorkerFailures:        worker.Failures,
+			}
+			for gaugeVec, value := range counters {
+				if value >= 0 {
+					geoRepGaugeVecs[gaugeVec].Set(labels, float64(value))
+				}
+			}
+		}
+
+		for _, status := range geoRepWorkerStatuses {
+			geoRepGaugeVecs[glusterGeoRepSessionWorkers].Set(withLabel(sessionLabels, "status", status), float64(workers[status]))
+			delete(workers, status)
+		}
+		for status, count := range workers {
+			geoRepGaugeVecs[glusterGeoRepSessionWorkers].Set(withLabel(sessionLabels, "status", status), float64(count))
+		}
+	}
+	return nil
+}
+
+func init() {
+	registerMetric("gluster_georep", collectorDefaults{SyncInterval: 30}, geoRep, geoRepGaugeVecs)
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/gluster/gluster-prometheus/pkg/glusterlog"
+	"github.com/gluster/gluster-prometheus/pkg/glusterutils"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultGlusterLogDir is the directory of the gluster logs
+	defaultGlusterLogDir = "/var/log/glusterfs"
+	// defaultLogTopMsgIDs is the no of message IDs exported for each
+	// log file, the ones with the most messages
+	defaultLogTopMsgIDs = 10
+	// maxTrackedMsgIDs is the max no of message IDs counted
+	// for each log file, to bound the memory
+	maxTrackedMsgIDs = 1000
+)
+
+// defaultLogFiles are the log files followed, relative to the log directory
+var defaultLogFiles = []string{"*.log", "bricks/*.log"}
+
+var (
+	logLabels = []MetricLabel{
+		clusterIDLabel,
+		{
+			Name: "file",
+			Help: "Log file, relative to the gluster log directory",
+		},
+		{
+			Name: "severity",
+			Help: "Severity of the messages, like `error` or `warning`",
+		},
+	}
+
+	logMsgIDLabels = []MetricLabel{
+		clusterIDLabel,
+		{
+			Name: "file",
+			Help: "Log file, relative to the gluster log directory",
+		},
+		{
+			Name: "severity",
+			Help: "Severity of the messages, like `error` or `warning`",
+		},
+		{
+			Name: "msgid",
+			Help: "Message ID of the messages, like `106004`",
+		},
+	}
+
+	logGaugeVecs = make(map[string]*ExportedGaugeVec)
+
+	glusterLogMessages = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "log_messages_total",
+		Help:      "No of messages logged in the gluster log file, since the exporter started",
+		Labels:    logLabels,
+		Counter:   true,
+	}, &logGaugeVecs)
+
+	glusterLogMsgIDMessages = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "log_msgid_messages_total",
+		Help:      "No of warning or worse messages logged in the gluster log file with the message ID",
+		LongHelp: "Counted since the exporter started. " +
+			"Only the 'log-top-msgids' message IDs of each log file with the most messages are exported",
+		Labels:  logMsgIDLabels,
+		Counter: true,
+	}, &logGaugeVecs)
+)
+
+type logMsgIDKey struct {
+	severity string
+	msgID    string
+}
+
+// logFile is a followed log file, with its message counts
+type logFile struct {
+	tailer     *glusterlog.Tailer
+	severities map[string]uint64
+	msgIDs     map[logMsgIDKey]uint64
+}
+
+func (f *logFile) count(line string) {
+	parsed, ok := glusterlog.ParseLine(line)
+	if !ok {
+		return
+	}
+	f.severities[parsed.Severity]++
+	if parsed.MsgID == "" || !glusterlog.IsWarningOrAbove(parsed.Severity) {
+		return
+	}
+	key := logMsgIDKey{severity: parsed.Severity, msgID: parsed.MsgID}
+	if _, ok := f.msgIDs[key]; ok || len(f.msgIDs) < maxTrackedMsgIDs {
+		f.msgIDs[key]++
+	}
+}
+
+// topMsgIDs returns the message IDs with the most messages
+func (f *logFile) topMsgIDs(limit int) []logMsgIDKey {
+	keys := make([]logMsgIDKey, 0, len(f.msgIDs))
+	for key := range f.msgIDs {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if f.msgIDs[keys[i]] != f.msgIDs[keys[j]] {
+			return f.msgIDs[keys[i]] > f.msgIDs[keys[j]]
+		}
+		return keys[i].msgID < keys[j].msgID
+	})
+	if len(keys) > limit {
+		keys = keys[:limit]
+	}
+	return keys
+}
+
+// logFiles are the followed log files by their path, the collector
+// can run again on scrape while its previous run timed out
+var logFiles = struct {
+	sync.Mutex
+	files map[string]*logFile
+	// started is set after the first run, the log files found
+	// later are new, and followed from their start
+	started bool
+}{files: make(map[string]*logFile)}
+
+// findLogFiles returns the log files matching the patterns
+func findLogFiles(dir string, patterns []string) map[string]bool {
+	paths := make(map[string]bool)
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			log.WithError(err).WithField("pattern", pattern).Debug("[Gluster Log] Invalid log file pattern")
+			continue
+		}
+		for _, path := range matches {
+			paths[path] = true
+		}
+	}
+	return paths
+}
+
+// matchLogFile returns true if the log file matches any of the patterns
+func matchLogFile(dir string, patterns []string, path string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := filepath.Match(filepath.Join(dir, pattern), path); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func logMessages(ctx context.Context, gluster glusterutils.GInterface) error {
+	// Reset all vecs to not export stale information
+	for _, gaugeVec := range logGaugeVecs {
+		gaugeVec.RemoveStaleMetrics()
+	}
+
+	collectorConf := getCollectorConf("gluster_log")
+	dir := collectorConf.GlusterLogDir
+	if dir == "" {
+		dir = defaultGlusterLogDir
+	}
+	patterns := collectorConf.LogFiles
+	if len(patterns) == 0 {
+		patterns = defaultLogFiles
+	}
+	topMsgIDs := collectorConf.LogTopMsgIDs
+	if topMsgIDs == 0 {
+		topMsgIDs = defaultLogTopMsgIDs
+	}
+
+	logFiles.Lock()
+	defer logFiles.Unlock()
+
+	paths := findLogFiles(dir, patterns)
+	for path, file := range logFiles.files {
+		// the files being rotated are missing for a while,
+		// they are followed as long as they are configured
+		if !matchLogFile(dir, patterns, path) {
+			_ = file.tailer.Close()
+			delete(logFiles.files, path)
+			continue
+		}
+		paths[path] = true
+	}
+
+	for path := range paths {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		file, ok := logFiles.files[path]
+		if !ok {
+			tailer := glusterlog.NewTailer(path)
+			if logFiles.started {
+				tailer = glusterlog.NewTailerFromStart(path)
+			}
+			file = &logFile{
+				tailer:     tailer,
+				severities: make(map[string]uint64),
+				msgIDs:     make(map[logMsgIDKey]uint64),
+			}
+			logFiles.files[path] = file
+		}
+		if err := file.tailer.ReadLines(file.count); err != nil {
+			log.WithError(err).WithField("file", path).Debug("[Gluster Log] Error reading the log file")
+		}
+
+		name, err := filepath.Rel(dir, path)
+		if err != nil {
+			name = path
+		}
+		for severity, count := range file.severities {
+			logGaugeVecs[glusterLogMessages].Set(prometheus.Labels{
+				"cluster_id": clusterID,
+				"file":       name,
+				"severity":   severity,
+			}, float64(count))
+		}
+		if topMsgIDs < 0 {
+			continue
+		}
+		for _, key := range file.topMsgIDs(topMsgIDs) {
+			logGaugeVecs[glusterLogMsgIDMessages].Set(prometheus.Labels{
+				"cluster_id": clusterID,
+				"file":       name,
+				"severity":   key.severity,
+				"msgid":      key.msgID,
+			}, float64(file.msgIDs[key]))
+		}
+	}
+	logFiles.started = true
+	return nil
+}
+
+func init() {
+	registerMetric("gluster_log", collectorDefaults{SyncInterval: 15}, logMessages, logGaugeVecs)
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"io"
@@ -34,9 +35,9 @@ type myVGDetails struct {
 }
 
 // NewPeerMetrics : provides a way to get the consolidated metrics (such PV, LV, VG counts)
-func NewPeerMetrics() (*PeerMetrics, error) {
+func NewPeerMetrics(ctx context.Context) (*PeerMetrics, error) {
 	cmdStr := "lvm vgs --noheading --reportformat=json -o lv_uuid,lv_name,pool_lv,vg_name,lv_path,lv_count,pv_count,pool_lv_uuid,lv_attr"
-	outBs, err := glusterutils.ExecuteCmd(cmdStr)
+	outBs, err := glusterutils.ExecuteCmdContext(ctx, cmdStr)
 	if err != nil {
 		return nil, err
 	}
@@ -161,7 +162,7 @@ var (
 	}, &peerCountsGaugeVecs)
 )
 
-func peerCounts(gluster glusterutils.GInterface) (err error) {
+func peerCounts(ctx context.Context, gluster glusterutils.GInterface) (err error) {
 	// Reset all vecs to not export stale information
 	for _, gaugeVec := range peerCountsGaugeVecs {
 		gaugeVec.RemoveStaleMetrics()
@@ -172,11 +173,11 @@ func peerCounts(gluster glusterutils.GInterface) (err error) {
 	// 'gluster' is initialized inside 'main' function,
 	// so it is better to check whether it is available or not
 	if gluster != nil {
-		if peerID, err = gluster.LocalPeerID(); err != nil {
+		if peerID, err = gluster.LocalPeerID(ctx); err != nil {
 			return
 		}
 	}
-	pMetrics, err := NewPeerMetrics()
+	pMetrics, err := NewPeerMetrics(ctx)
 	if err != nil {
 		// log the error and then return
 		log.WithError(err).WithFields(log.Fields{
@@ -220,5 +221,5 @@ func peerCounts(gluster glusterutils.GInterface) (err error) {
 }
 
 func init() {
-	registerMetric("gluster_peer_counts", peerCounts)
+	registerMetric("gluster_peer_counts", collectorDefaults{SyncInterval: 5}, peerCounts, peerCountsGaugeVecs)
 }
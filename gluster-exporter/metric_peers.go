@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+
 	"github.com/gluster/gluster-prometheus/pkg/glusterutils"
 	"github.com/prometheus/client_golang/prometheus"
 	log "github.com/sirupsen/logrus"
@@ -52,7 +54,7 @@ var (
 	}, &peerGaugeVecs)
 )
 
-func peerInfo(gluster glusterutils.GInterface) (err error) {
+func peerInfo(ctx context.Context, gluster glusterutils.GInterface) (err error) {
 	// Reset all vecs to not export stale information
 	for _, gaugeVec := range peerGaugeVecs {
 		gaugeVec.RemoveStaleMetrics()
@@ -61,12 +63,12 @@ func peerInfo(gluster glusterutils.GInterface) (err error) {
 	var peerID string
 
 	if gluster != nil {
-		if peerID, err = gluster.LocalPeerID(); err != nil {
+		if peerID, err = gluster.LocalPeerID(ctx); err != nil {
 			return
 		}
 	}
 
-	peers, err := gluster.Peers()
+	peers, err := gluster.Peers(ctx)
 	if err != nil {
 		log.WithError(err).WithFields(log.Fields{"peer": peerID}).Debug("[Gluster Peers] Error:", err)
 		return err
@@ -111,5 +113,5 @@ func peerInfo(gluster glusterutils.GInterface) (err error) {
 }
 
 func init() {
-	registerMetric("gluster_peer_info", peerInfo)
+	registerMetric("gluster_peer_info", collectorDefaults{SyncInterval: 5}, peerInfo, peerGaugeVecs)
 }
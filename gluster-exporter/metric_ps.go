@@ -1,25 +1,17 @@
 package main
 
 import (
-	"io/ioutil"
-	"os/exec"
-	"path/filepath"
+	"context"
 	"strconv"
-	"strings"
 
+	"github.com/gluster/gluster-prometheus/gluster-exporter/conf"
 	"github.com/gluster/gluster-prometheus/pkg/glusterutils"
+	"github.com/gluster/gluster-prometheus/pkg/glusterutils/glusterconsts"
+	"github.com/gluster/gluster-prometheus/pkg/proc"
 	"github.com/prometheus/client_golang/prometheus"
-	log "github.com/sirupsen/logrus"
 )
 
 var (
-	glusterProcs = []string{
-		"glusterd",
-		"glusterfsd",
-		"glusterd2",
-		// TODO: Add more processes
-	}
-
 	labels = []MetricLabel{
 		clusterIDLabel,
 		{
@@ -38,6 +30,34 @@ var (
 			Name: "name",
 			Help: "Name of the Gluster process(Ex: `glusterfsd`, `glusterd` etc)",
 		},
+		{
+			Name: "role",
+			Help: "Role of the Gluster process(Ex: `brick`, `shd`, `fuse` etc)",
+		},
+		{
+			Name: "pid",
+			Help: "PID of the Gluster process, tells apart the processes with the same labels, like the FUSE mounts of a volume or the geo-replication workers",
+		},
+	}
+
+	processUpLabels = []MetricLabel{
+		clusterIDLabel,
+		{
+			Name: "volume",
+			Help: "Volume Name, of the volume whose configuration needs the process",
+		},
+		{
+			Name: "peerid",
+			Help: "Peer ID",
+		},
+		{
+			Name: "brick_path",
+			Help: "Brick Path",
+		},
+		{
+			Name: "role",
+			Help: "Role of the Gluster process(Ex: `glusterd`, `brick`, `shd` etc)",
+		},
 	}
 
 	psGaugeVecs = make(map[string]*ExportedGaugeVec)
@@ -46,7 +66,7 @@ var (
 		Namespace: "gluster",
 		Name:      "cpu_percentage",
 		Help:      "CPU Percentage used by Gluster processes",
-		LongHelp:  "CPU percentage of Gluster process. One metric will be exposed for each process. Note: values of labels will be empty if not applicable to that process. For example, glusterd process will not have labels for volume or brick_path. It is the CPU time used divided by the time the process has been running (cputime/realtime ratio), expressed as a percentage.",
+		LongHelp:  "One metric will be exposed for each process. Note: values of labels will be empty if not applicable to that process. For example, glusterd process will not have labels for volume or brick_path. It is the CPU time used divided by the time the process has been running (cputime/realtime ratio), expressed as a percentage. It is an average over the lifetime of the process, use the rate of gluster_cpu_seconds_total for the current usage.",
 		Labels:    labels,
 	}, &psGaugeVecs)
 
@@ -54,7 +74,7 @@ var (
 		Namespace: "gluster",
 		Name:      "memory_percentage",
 		Help:      "Memory Percentage used by Gluster processes",
-		LongHelp:  "Memory percentage of Gluster process. One metric will be exposed for each process. Note: values of labels will be empty if not applicable to that process. For example, glusterd process will not have labels for volume or brick_path. It is the ratio of the process's resident set size to the physical memory on the machine, expressed as a percentage",
+		LongHelp:  "One metric will be exposed for each process. Note: values of labels will be empty if not applicable to that process. For example, glusterd process will not have labels for volume or brick_path. It is the ratio of the process's resident set size to the physical memory on the machine, expressed as a percentage",
 		Labels:    labels,
 	}, &psGaugeVecs)
 
@@ -62,7 +82,7 @@ var (
 		Namespace: "gluster",
 		Name:      "resident_memory_bytes",
 		Help:      "Resident Memory of Gluster processes in bytes",
-		LongHelp:  "Resident Memory of Gluster process in bytes. One metric will be exposed for each process. Note: values of labels will be empty if not applicable to that process. For example, glusterd process will not have labels for volume or brick_path.",
+		LongHelp:  "One metric will be exposed for each process. Note: values of labels will be empty if not applicable to that process. For example, glusterd process will not have labels for volume or brick_path.",
 		Labels:    labels,
 	}, &psGaugeVecs)
 
@@ -70,7 +90,7 @@ var (
 		Namespace: "gluster",
 		Name:      "virtual_memory_bytes",
 		Help:      "Virtual Memory of Gluster processes in bytes",
-		LongHelp:  "Virtual Memory of Gluster process in bytes. One metric will be exposed for each process. Note: values of labels will be empty if not applicable to that process. For example, glusterd process will not have labels for volume or brick_path.",
+		LongHelp:  "One metric will be exposed for each process. Note: values of labels will be empty if not applicable to that process. For example, glusterd process will not have labels for volume or brick_path.",
 		Labels:    labels,
 	}, &psGaugeVecs)
 
@@ -78,197 +98,280 @@ var (
 		Namespace: "gluster",
 		Name:      "elapsed_time_seconds",
 		Help:      "Elapsed Time of Gluster processes in seconds",
-		LongHelp:  "Elapsed Time or Uptime of Gluster processes in seconds. One metric will be exposed for each process. Note: values of labels will be empty if not applicable to that process. For example, glusterd process will not have labels for volume or brick_path.",
+		LongHelp:  "One metric will be exposed for each process. Note: values of labels will be empty if not applicable to that process. For example, glusterd process will not have labels for volume or brick_path.",
 		Labels:    labels,
 	}, &psGaugeVecs)
-)
 
-func getCmdLine(pid string) ([]string, error) {
-	var args []string
+	glusterCPUSeconds = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "cpu_seconds_total",
+		Help:      "Total user and system CPU time of Gluster processes in seconds",
+		LongHelp:  "One metric will be exposed for each process. Note: values of labels will be empty if not applicable to that process. For example, glusterd process will not have labels for volume or brick_path.",
+		Labels:    labels,
+		Counter:   true,
+	}, &psGaugeVecs)
 
-	out, err := ioutil.ReadFile(filepath.Clean("/proc/" + pid + "/cmdline"))
-	if err != nil {
-		return args, err
-	}
+	glusterReadBytes = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "read_bytes_total",
+		Help:      "Total bytes read from the storage by Gluster processes",
+		LongHelp:  "One metric will be exposed for each process. Note: values of labels will be empty if not applicable to that process. For example, glusterd process will not have labels for volume or brick_path. Not exported if the exporter is not allowed to read the io of the process.",
+		Labels:    labels,
+		Counter:   true,
+	}, &psGaugeVecs)
 
-	return strings.Split(strings.Trim(string(out), "\x00"), "\x00"), nil
-}
+	glusterWriteBytes = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "write_bytes_total",
+		Help:      "Total bytes written to the storage by Gluster processes",
+		LongHelp:  "One metric will be exposed for each process. Note: values of labels will be empty if not applicable to that process. For example, glusterd process will not have labels for volume or brick_path. Not exported if the exporter is not allowed to read the io of the process.",
+		Labels:    labels,
+		Counter:   true,
+	}, &psGaugeVecs)
 
-func getGlusterdLabels(peerID, cmd string) prometheus.Labels {
-	return prometheus.Labels{
-		"cluster_id": clusterID,
-		"name":       cmd,
-		"volume":     "",
-		"peerid":     peerID,
-		"brick_path": "",
-	}
-}
+	glusterOpenFds = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "open_fds",
+		Help:      "No of fds open by Gluster processes",
+		LongHelp:  "One metric will be exposed for each process. Note: values of labels will be empty if not applicable to that process. For example, glusterd process will not have labels for volume or brick_path. Not exported if the exporter is not allowed to read the fds of the process.",
+		Labels:    labels,
+	}, &psGaugeVecs)
 
-func getGlusterFsdLabels(peerID, cmd string, args []string) prometheus.Labels {
-	bpath := ""
-	volume := ""
+	glusterThreads = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "threads",
+		Help:      "No of threads of Gluster processes",
+		LongHelp:  "One metric will be exposed for each process. Note: values of labels will be empty if not applicable to that process. For example, glusterd process will not have labels for volume or brick_path.",
+		Labels:    labels,
+	}, &psGaugeVecs)
 
-	prevArg := ""
+	glusterVoluntaryCtxSwitches = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "voluntary_context_switches_total",
+		Help:      "Total voluntary context switches of Gluster processes",
+		LongHelp:  "Counts the switches like when waiting for io. One metric will be exposed for each process. Note: values of labels will be empty if not applicable to that process. For example, glusterd process will not have labels for volume or brick_path.",
+		Labels:    labels,
+		Counter:   true,
+	}, &psGaugeVecs)
 
-	for _, a := range args {
-		if prevArg == "--brick-name" {
-			bpath = a
-		} else if prevArg == "--volfile-id" {
-			volume = strings.Split(a, ".")[0]
-		}
-		prevArg = a
-	}
+	glusterInvoluntaryCtxSwitches = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "involuntary_context_switches_total",
+		Help:      "Total involuntary context switches of Gluster processes",
+		LongHelp:  "Counts the switches when preempted by the scheduler. One metric will be exposed for each process. Note: values of labels will be empty if not applicable to that process. For example, glusterd process will not have labels for volume or brick_path.",
+		Labels:    labels,
+		Counter:   true,
+	}, &psGaugeVecs)
 
+	glusterProcessUp = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "process_up",
+		Help:      "Whether the Gluster process needed on the node is running (1-running, 0-not running)",
+		LongHelp: "Exported for glusterd and for each process the configuration of the started volumes " +
+			"with bricks on the node needs, like the bricks, the self-heal daemon, quotad, the " +
+			"bitrot daemons, snapd and the gluster NFS server. The single daemons serving all " +
+			"the volumes, like quotad, are exported for each volume needing them.",
+		Labels: processUpLabels,
+	}, &psGaugeVecs)
+)
+
+func getProcLabels(peerID string, pid int, p glusterutils.GlusterProcess) prometheus.Labels {
 	return prometheus.Labels{
 		"cluster_id": clusterID,
-		"name":       cmd,
-		"volume":     volume,
+		"name":       p.Name,
+		"volume":     p.Volume,
 		"peerid":     peerID,
-		"brick_path": bpath,
+		"brick_path": p.BrickPath,
+		"role":       p.Role,
+		"pid":        strconv.Itoa(pid),
 	}
 }
 
-func getUnknownLabels(peerID, cmd string) prometheus.Labels {
+func getProcessUpLabels(peerID, role, volume, brickPath string) prometheus.Labels {
 	return prometheus.Labels{
 		"cluster_id": clusterID,
-		"name":       cmd,
-		"volume":     "",
+		"volume":     volume,
 		"peerid":     peerID,
-		"brick_path": "",
+		"brick_path": brickPath,
+		"role":       role,
 	}
 }
 
-func ps(gluster glusterutils.GInterface) error {
-	// Reset all vecs to not export stale information
-	for _, gaugeVec := range psGaugeVecs {
-		gaugeVec.RemoveStaleMetrics()
-	}
+// isGlusterProc returns true if the process is a gluster process,
+// matched by its arguments instead of its command name, which is
+// truncated and is the same for all the glusterfs daemons
+func isGlusterProc(args []string) bool {
+	_, ok := glusterutils.ClassifyProcess(args)
+	return ok
+}
 
-	args := []string{
-		"--no-header", // No header in the output
-		"-ww",         // To set unlimited width to avoid crop
-		"-o",          // Output Format
-		"pid,pcpu,pmem,rsz,vsz,etimes,comm",
-		"-C",
-		strings.Join(glusterProcs, ","),
+// runningProcs indexes the running gluster processes by their role
+type runningProcs map[string][]glusterutils.GlusterProcess
+
+// running returns true if a process of the role is running for the
+// volume and the brick. The daemons serving all the volumes, like the
+// self-heal daemon before gluster 7, are running for any volume
+func (r runningProcs) running(role, volume, brickPath string) bool {
+	for _, p := range r[role] {
+		if (p.Volume == "" || p.Volume == volume) && p.BrickPath == brickPath {
+			return true
+		}
 	}
+	return false
+}
 
-	out, err := exec.Command("ps", args...).Output() // #nosec
+// volumeNeedsSelfHeal returns true if the volume has replicate or
+// disperse subvolumes, and the self-heal daemon is not disabled
+func volumeNeedsSelfHeal(volume glusterutils.Volume, option string) bool {
+	if volume.Options[option] == "off" {
+		return false
+	}
+	for _, subvol := range volume.SubVolumes {
+		if subvol.Type == glusterconsts.SubvolTypeReplicate || subvol.Type == glusterconsts.SubvolTypeDisperse {
+			return true
+		}
+	}
+	return false
+}
 
+// processUp exports whether each gluster process needed on the node
+// is running: glusterd, and the processes needed by the configuration
+// of the started volumes with bricks on the node
+func processUp(ctx context.Context, gluster glusterutils.GInterface, peerID string, procs runningProcs) error {
+	glusterConfig, err := conf.GConfigFromInterface(gluster)
 	if err != nil {
-		// Return without exporting metrics in this cycle
 		return err
 	}
+	mgmtRole := glusterconsts.ProcessRoleGlusterd
+	shdOption := glusterconsts.SelfHealDaemonGD1
+	bitrotOption := glusterconsts.BitrotGD1
+	quotaOption := glusterconsts.QuotaGD1
+	if glusterConfig.GlusterMgmt == glusterconsts.MgmtGlusterd2 {
+		mgmtRole = glusterconsts.ProcessRoleGlusterd2
+		shdOption = glusterconsts.SelfHealDaemonGD2
+		bitrotOption = glusterconsts.BitrotGD2
+		quotaOption = glusterconsts.QuotaGD2
+	}
+	psGaugeVecs[glusterProcessUp].Set(getProcessUpLabels(peerID, mgmtRole, "", ""),
+		boolToFloat(procs.running(mgmtRole, "", "")))
 
-	peerID, err := gluster.LocalPeerID()
+	// the volumes are needed to know the other processes, the last
+	// known volumes are used when glusterd is down
+	volumes, err := getLastKnownVolumes(ctx, gluster)
 	if err != nil {
 		return err
 	}
-
-	for _, line := range strings.Split(string(out), "\n") {
-		// Sample data:
-		// 6959  0.0  0.6 12840 713660  504076 glusterfs
-		lineDataTmp := strings.Split(line, " ")
-		lineData := []string{}
-		for _, d := range lineDataTmp {
-			if strings.Trim(d, " ") == "" {
-				continue
+	for _, volume := range volumes {
+		if volume.State != glusterconsts.VolumeStateStarted {
+			continue
+		}
+		localBricks := false
+		for _, subvol := range volume.SubVolumes {
+			for _, brick := range subvol.Bricks {
+				if brick.PeerID != peerID {
+					continue
+				}
+				psGaugeVecs[glusterProcessUp].Set(
+					getProcessUpLabels(peerID, glusterconsts.ProcessRoleBrick, volume.Name, brick.Path),
+					boolToFloat(procs.running(glusterconsts.ProcessRoleBrick, volume.Name, brick.Path)))
+				localBricks = true
 			}
-			lineData = append(lineData, d)
 		}
-
-		if len(lineData) < 7 {
+		if !localBricks {
+			// the daemons of the volume are not needed on the node
 			continue
 		}
-		cmdlineArgs, err := getCmdLine(lineData[0])
-		if err != nil {
-			log.WithError(err).WithFields(log.Fields{
-				"command": lineData[6],
-				"pid":     lineData[0],
-			}).Debug("Error getting command line")
-			continue
+		var needed []string
+		if volumeNeedsSelfHeal(volume, shdOption) {
+			needed = append(needed, glusterconsts.ProcessRoleSelfHeal)
 		}
-
-		if len(cmdlineArgs) == 0 {
-			// No cmdline file, may be that process died
-			continue
+		if volume.Options[quotaOption] == "on" {
+			needed = append(needed, glusterconsts.ProcessRoleQuotad)
 		}
-
-		var lbls prometheus.Labels
-		switch lineData[6] {
-		case "glusterd":
-			lbls = getGlusterdLabels(peerID, lineData[6])
-		case "glusterd2":
-			lbls = getGlusterdLabels(peerID, lineData[6])
-		case "glusterfsd":
-			lbls = getGlusterFsdLabels(peerID, lineData[6], cmdlineArgs)
-		default:
-			lbls = getUnknownLabels(peerID, lineData[6])
+		if volume.Options[bitrotOption] == "on" {
+			needed = append(needed, glusterconsts.ProcessRoleBitd, glusterconsts.ProcessRoleScrubd)
 		}
-
-		pcpu, err := strconv.ParseFloat(lineData[1], 64)
-		if err != nil {
-			log.WithError(err).WithFields(log.Fields{
-				"value":   lineData[1],
-				"command": lineData[6],
-				"pid":     lineData[0],
-			}).Debug("Unable to parse pcpu value")
-			continue
+		if volume.Options[glusterconsts.USSGD1] == "on" {
+			needed = append(needed, glusterconsts.ProcessRoleSnapd)
 		}
-
-		pmem, err := strconv.ParseFloat(lineData[2], 64)
-		if err != nil {
-			log.WithError(err).WithFields(log.Fields{
-				"value":   lineData[2],
-				"command": lineData[6],
-				"pid":     lineData[0],
-			}).Debug("Unable to parse pmem value")
-			continue
+		if volume.Options[glusterconsts.NFSDisableGD1] == "off" {
+			needed = append(needed, glusterconsts.ProcessRoleNFS)
 		}
-		rsz, err := strconv.ParseFloat(lineData[3], 64)
-		if err != nil {
-			log.WithError(err).WithFields(log.Fields{
-				"value":   lineData[3],
-				"command": lineData[6],
-				"pid":     lineData[0],
-			}).Debug("Unable to parse rsz value")
-			continue
+		for _, role := range needed {
+			psGaugeVecs[glusterProcessUp].Set(getProcessUpLabels(peerID, role, volume.Name, ""),
+				boolToFloat(procs.running(role, volume.Name, "")))
 		}
+	}
+	return nil
+}
 
-		vsz, err := strconv.ParseFloat(lineData[4], 64)
-		if err != nil {
-			log.WithError(err).WithFields(log.Fields{
-				"value":   lineData[4],
-				"command": lineData[6],
-				"pid":     lineData[0],
-			}).Debug("Unable to parse vsz value")
-			continue
-		}
+func ps(ctx context.Context, gluster glusterutils.GInterface) error {
+	// Reset all vecs to not export stale information
+	for _, gaugeVec := range psGaugeVecs {
+		gaugeVec.RemoveStaleMetrics()
+	}
 
-		etimes, err := strconv.ParseFloat(lineData[5], 64)
-		if err != nil {
-			log.WithError(err).WithFields(log.Fields{
-				"value":   lineData[5],
-				"command": lineData[6],
-				"pid":     lineData[0],
-			}).Debug("Unable to parse etimes value")
-			continue
-		}
+	procFS := proc.NewFS(proc.DefaultRoot)
+	procs, err := procFS.Find(isGlusterProc)
+	if err != nil {
+		// Return without exporting metrics in this cycle
+		return err
+	}
+	pids := make([]int, len(procs))
+	for idx, p := range procs {
+		pids[idx] = p.PID
+	}
+	glusterutils.RecordProcesses(proc.DefaultRoot, pids)
+	uptime, err := procFS.Uptime()
+	if err != nil {
+		return err
+	}
+	memTotal, err := procFS.MemTotal()
+	if err != nil {
+		return err
+	}
+
+	peerID, err := gluster.LocalPeerID(ctx)
+	if err != nil {
+		return err
+	}
 
-		// Convert to bytes from kilo bytes
-		vsz = vsz * 1024
-		rsz = rsz * 1024
+	running := make(runningProcs)
+	for _, p := range procs {
+		glusterProc, _ := glusterutils.ClassifyProcess(p.Args)
+		running[glusterProc.Role] = append(running[glusterProc.Role], glusterProc)
+		lbls := getProcLabels(peerID, p.PID, glusterProc)
+		elapsed := uptime - p.StartTime
+		// Same as the pcpu of ps, the cpu time divided by the
+		// time the process has been running
+		var pcpu float64
+		if elapsed > 0 {
+			pcpu = p.CPUSeconds / elapsed * 100
+		}
 
 		// Update the Metrics
 		psGaugeVecs[glusterCPUPercentage].Set(lbls, pcpu)
-		psGaugeVecs[glusterMemoryPercentage].Set(lbls, pmem)
-		psGaugeVecs[glusterResidentMemory].Set(lbls, rsz)
-		psGaugeVecs[glusterVirtualMemory].Set(lbls, vsz)
-		psGaugeVecs[glusterElapsedTime].Set(lbls, etimes)
+		psGaugeVecs[glusterMemoryPercentage].Set(lbls, float64(p.ResidentMemory)/float64(memTotal)*100)
+		psGaugeVecs[glusterResidentMemory].Set(lbls, float64(p.ResidentMemory))
+		psGaugeVecs[glusterVirtualMemory].Set(lbls, float64(p.VirtualMemory))
+		psGaugeVecs[glusterElapsedTime].Set(lbls, elapsed)
+		psGaugeVecs[glusterCPUSeconds].Set(lbls, p.CPUSeconds)
+		psGaugeVecs[glusterThreads].Set(lbls, float64(p.Threads))
+		psGaugeVecs[glusterVoluntaryCtxSwitches].Set(lbls, float64(p.VoluntaryCtxSwitches))
+		psGaugeVecs[glusterInvoluntaryCtxSwitches].Set(lbls, float64(p.NonVoluntaryCtxSwitches))
+		// the io and the fds of the processes of other users are not
+		// readable, unless the exporter runs as root
+		if p.ReadBytes >= 0 {
+			psGaugeVecs[glusterReadBytes].Set(lbls, float64(p.ReadBytes))
+			psGaugeVecs[glusterWriteBytes].Set(lbls, float64(p.WriteBytes))
+		}
+		if p.OpenFds >= 0 {
+			psGaugeVecs[glusterOpenFds].Set(lbls, float64(p.OpenFds))
+		}
 	}
-	return nil
+	return processUp(ctx, gluster, peerID, running)
 }
 
 func init() {
-	registerMetric("gluster_ps", ps)
+	registerMetric("gluster_ps", collectorDefaults{SyncInterval: 5}, ps, psGaugeVecs)
 }
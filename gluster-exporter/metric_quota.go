@@ -0,0 +1,234 @@
+package main
+
+import (
+	"context"
+
+	"github.com/gluster/gluster-prometheus/gluster-exporter/conf"
+	"github.com/gluster/gluster-prometheus/pkg/glusterutils"
+	"github.com/gluster/gluster-prometheus/pkg/glusterutils/glusterconsts"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	quotaLabels = []MetricLabel{
+		clusterIDLabel,
+		{
+			Name: "volume",
+			Help: "Volume Name",
+		},
+		{
+			Name: "path",
+			Help: "Path of the directory in the volume",
+		},
+	}
+
+	quotaGaugeVecs = make(map[string]*ExportedGaugeVec)
+
+	glusterQuotaHardLimit = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "quota_hard_limit_bytes",
+		Help:      "Hard limit of the directory usage in bytes",
+		Labels:    quotaLabels,
+	}, &quotaGaugeVecs)
+
+	glusterQuotaSoftLimitPercent = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "quota_soft_limit_percent",
+		Help:      "Soft limit of the directory usage as a percentage of the hard limit",
+		Labels:    quotaLabels,
+	}, &quotaGaugeVecs)
+
+	glusterQuotaUsed = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "quota_used_bytes",
+		Help:      "Usage of the directory in bytes",
+		Labels:    quotaLabels,
+	}, &quotaGaugeVecs)
+
+	glusterQuotaAvailable = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "quota_available_bytes",
+		Help:      "Bytes available to the directory till the hard limit",
+		Labels:    quotaLabels,
+	}, &quotaGaugeVecs)
+
+	glusterQuotaSoftLimitExceeded = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "quota_soft_limit_exceeded",
+		Help:      "Whether the directory usage exceeded the soft limit (1-exceeded, 0-within the limit)",
+		Labels:    quotaLabels,
+	}, &quotaGaugeVecs)
+
+	glusterQuotaHardLimitExceeded = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "quota_hard_limit_exceeded",
+		Help:      "Whether the directory usage reached the hard limit (1-exceeded, 0-within the limit)",
+		Labels:    quotaLabels,
+	}, &quotaGaugeVecs)
+
+	glusterQuotaObjectsHardLimit = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "quota_objects_hard_limit",
+		Help:      "Hard limit of the no of files and directories in the directory",
+		Labels:    quotaLabels,
+	}, &quotaGaugeVecs)
+
+	glusterQuotaObjectsSoftLimitPercent = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "quota_objects_soft_limit_percent",
+		Help:      "Soft limit of the no of files and directories as a percentage of the hard limit",
+		Labels:    quotaLabels,
+	}, &quotaGaugeVecs)
+
+	glusterQuotaObjectsUsed = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "quota_objects_used",
+		Help:      "No of files and directories in the directory",
+		Labels:    quotaLabels,
+	}, &quotaGaugeVecs)
+
+	glusterQuotaObjectsAvailable = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "quota_objects_available",
+		Help:      "No of files and directories which can be created in the directory till the hard limit",
+		Labels:    quotaLabels,
+	}, &quotaGaugeVecs)
+
+	glusterQuotaObjectsSoftLimitExceeded = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "quota_objects_soft_limit_exceeded",
+		Help:      "Whether the no of files and directories exceeded the soft limit (1-exceeded, 0-within the limit)",
+		Labels:    quotaLabels,
+	}, &quotaGaugeVecs)
+
+	glusterQuotaObjectsHardLimitExceeded = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "quota_objects_hard_limit_exceeded",
+		Help:      "Whether the no of files and directories reached the hard limit (1-exceeded, 0-within the limit)",
+		Labels:    quotaLabels,
+	}, &quotaGaugeVecs)
+
+	// quotaMetrics are the metrics of each quota limit type
+	quotaMetrics = map[string]quotaMetricNames{
+		glusterutils.QuotaTypeUsage: {
+			hardLimit:         glusterQuotaHardLimit,
+			softLimitPercent:  glusterQuotaSoftLimitPercent,
+			used:              glusterQuotaUsed,
+			available:         glusterQuotaAvailable,
+			softLimitExceeded: glusterQuotaSoftLimitExceeded,
+			hardLimitExceeded: glusterQuotaHardLimitExceeded,
+		},
+		glusterutils.QuotaTypeObjects: {
+			hardLimit:         glusterQuotaObjectsHardLimit,
+			softLimitPercent:  glusterQuotaObjectsSoftLimitPercent,
+			used:              glusterQuotaObjectsUsed,
+			available:         glusterQuotaObjectsAvailable,
+			softLimitExceeded: glusterQuotaObjectsSoftLimitExceeded,
+			hardLimitExceeded: glusterQuotaObjectsHardLimitExceeded,
+		},
+	}
+)
+
+// quotaMetricNames are the names of the metrics of a quota limit type
+type quotaMetricNames struct {
+	hardLimit         string
+	softLimitPercent  string
+	used              string
+	available         string
+	softLimitExceeded string
+	hardLimitExceeded string
+}
+
+func getQuotaLabels(volume string, limit glusterutils.QuotaLimit) prometheus.Labels {
+	return prometheus.Labels{
+		"cluster_id": clusterID,
+		"volume":     volume,
+		"path":       limit.Path,
+	}
+}
+
+// boolToFloat returns 1 for true and 0 for false
+func boolToFloat(value bool) float64 {
+	if value {
+		return 1
+	}
+	return 0
+}
+
+func quota(ctx context.Context, gluster glusterutils.GInterface) error {
+	isLeader, err := gluster.IsLeader(ctx)
+
+	// Reset all vecs to not export stale information
+	for _, gaugeVec := range quotaGaugeVecs {
+		gaugeVec.RemoveStaleMetrics()
+	}
+
+	if err != nil {
+		// Unable to find out if the current node is leader
+		// Cannot register quota metrics at this node
+		log.WithError(err).Debug("Unable to find if the current node is leader")
+		return err
+	}
+	// the quota usage is aggregated by glusterd
+	// across the bricks, export only from the leader
+	if !isLeader {
+		return nil
+	}
+
+	volumes, err := gluster.VolumeInfo(ctx)
+	if err != nil {
+		return err
+	}
+	var glusterConfig *conf.GConfig
+	if glusterConfig, err = conf.GConfigFromInterface(gluster); err != nil {
+		return err
+	}
+
+	for _, volume := range volumes {
+		if volume.State != glusterconsts.VolumeStateStarted {
+			continue
+		}
+		if glusterConfig.GlusterMgmt != glusterconsts.MgmtGlusterd2 {
+			if value := volume.Options[glusterconsts.QuotaGD1]; value != "on" {
+				// quota is not enabled for the volume
+				continue
+			}
+		}
+		limits, err := gluster.QuotaList(ctx, volume.Name)
+		if err != nil {
+			log.WithError(err).WithFields(log.Fields{
+				"volume": volume.Name,
+			}).Debug("[Gluster Quota] Error getting quota list")
+			return err
+		}
+		for _, limit := range limits {
+			names, ok := quotaMetrics[limit.Type]
+			if !ok {
+				continue
+			}
+			labels := getQuotaLabels(volume.Name, limit)
+			// the limits and the usage are not available
+			// if the directory is not accessible
+			if limit.HardLimit >= 0 {
+				quotaGaugeVecs[names.hardLimit].Set(labels, float64(limit.HardLimit))
+			}
+			if limit.SoftLimitPercent >= 0 {
+				quotaGaugeVecs[names.softLimitPercent].Set(labels, limit.SoftLimitPercent)
+			}
+			if limit.Used >= 0 {
+				quotaGaugeVecs[names.used].Set(labels, float64(limit.Used))
+			}
+			if limit.Available >= 0 {
+				quotaGaugeVecs[names.available].Set(labels, float64(limit.Available))
+			}
+			quotaGaugeVecs[names.softLimitExceeded].Set(labels, boolToFloat(limit.SoftLimitExceeded))
+			quotaGaugeVecs[names.hardLimitExceeded].Set(labels, boolToFloat(limit.HardLimitExceeded))
+		}
+	}
+	return nil
+}
+
+func init() {
+	registerMetric("gluster_quota", collectorDefaults{SyncInterval: 30}, quota, quotaGaugeVecs)
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+
+	"github.com/gluster/gluster-prometheus/pkg/glusterutils"
+	"github.com/gluster/gluster-prometheus/pkg/glusterutils/glusterconsts"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	rebalanceTaskLabels = []MetricLabel{
+		clusterIDLabel,
+		{
+			Name: "volume",
+			Help: "Volume Name",
+		},
+		{
+			Name: "task",
+			Help: "Type of the task, rebalance or remove-brick",
+		},
+	}
+
+	rebalanceNodeLabels = []MetricLabel{
+		clusterIDLabel,
+		{
+			Name: "volume",
+			Help: "Volume Name",
+		},
+		{
+			Name: "task",
+			Help: "Type of the task, rebalance or remove-brick",
+		},
+		{
+			Name: "host",
+			Help: "Host name or IP, as reported by glusterd",
+		},
+		{
+			Name: "peerid",
+			Help: "Peer ID of the host",
+		},
+	}
+
+	rebalanceGaugeVecs = make(map[string]*ExportedGaugeVec)
+
+	glusterRebalanceFiles = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "rebalance_files",
+		Help:      "No of files rebalanced by the node",
+		LongHelp:  "For a remove-brick task, the files migrated off the removed bricks",
+		Labels:    rebalanceNodeLabels,
+	}, &rebalanceGaugeVecs)
+
+	glusterRebalanceBytes = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "rebalance_bytes",
+		Help:      "Size of the data rebalanced by the node in bytes",
+		Labels:    rebalanceNodeLabels,
+	}, &rebalanceGaugeVecs)
+
+	glusterRebalanceScannedFiles = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "rebalance_scanned_files",
+		Help:      "No of files scanned by the node",
+		Labels:    rebalanceNodeLabels,
+	}, &rebalanceGaugeVecs)
+
+	glusterRebalanceFailures = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "rebalance_failures",
+		Help:      "No of files which the node failed to rebalance",
+		Labels:    rebalanceNodeLabels,
+	}, &rebalanceGaugeVecs)
+
+	glusterRebalanceSkippedFiles = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "rebalance_skipped_files",
+		Help:      "No of files skipped by the node",
+		LongHelp: "Files are skipped when the destination " +
+			"brick has less free space, or the file is open",
+		Labels: rebalanceNodeLabels,
+	}, &rebalanceGaugeVecs)
+
+	glusterRebalanceRunTime = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "rebalance_run_time_seconds",
+		Help:      "Time the node has been running the task in seconds",
+		Labels:    rebalanceNodeLabels,
+	}, &rebalanceGaugeVecs)
+
+	glusterRebalanceStatus = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "rebalance_status",
+		Help:      "Status code of the task on the node",
+		LongHelp: "The codes are 0-not started, 1-in progress, 2-stopped, 3-completed, 4-failed, " +
+			"5-fix-layout in progress, 6-fix-layout stopped, 7-fix-layout completed, 8-fix-layout failed",
+		Labels: rebalanceNodeLabels,
+	}, &rebalanceGaugeVecs)
+
+	glusterRebalanceTimeLeft = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "rebalance_estimated_time_left_seconds",
+		Help:      "Estimated time to complete the task in seconds",
+		LongHelp: "Only exported while the task is " +
+			"in progress and glusterd has an estimate, which is not available during the first 10 minutes",
+		Labels: rebalanceTaskLabels,
+	}, &rebalanceGaugeVecs)
+)
+
+func getRebalanceTaskLabels(status glusterutils.RebalanceStatus) prometheus.Labels {
+	return prometheus.Labels{
+		"cluster_id": clusterID,
+		"volume":     status.Volume,
+		"task":       status.TaskType,
+	}
+}
+
+func getRebalanceNodeLabels(status glusterutils.RebalanceStatus, node glusterutils.RebalanceNodeStatus) prometheus.Labels {
+	return prometheus.Labels{
+		"cluster_id": clusterID,
+		"volume":     status.Volume,
+		"task":       status.TaskType,
+		"host":       node.Hostname,
+		"peerid":     node.PeerID,
+	}
+}
+
+func rebalance(ctx context.Context, gluster glusterutils.GInterface) error {
+	isLeader, err := gluster.IsLeader(ctx)
+
+	// Reset all vecs to not export stale information
+	for _, gaugeVec := range rebalanceGaugeVecs {
+		gaugeVec.RemoveStaleMetrics()
+	}
+
+	if err != nil {
+		// Unable to find out if the current node is leader
+		// Cannot register rebalance metrics at this node
+		log.WithError(err).Debug("Unable to find if the current node is leader")
+		return err
+	}
+	// the rebalance status of all the nodes is available
+	// from any node, export only from the leader
+	if !isLeader {
+		return nil
+	}
+
+	volumes, err := gluster.VolumeInfo(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, volume := range volumes {
+		// glusterd reports the tasks of the started volumes only
+		if volume.State != glusterconsts.VolumeStateStarted {
+			continue
+		}
+		statuses, err := gluster.RebalanceStatus(ctx, volume.Name)
+		if err != nil {
+			log.WithError(err).WithFields(log.Fields{
+				"volume": volume.Name,
+			}).Debug("[Gluster Rebalance] Error getting rebalance status")
+			return err
+		}
+		for _, status := range statuses {
+			for _, node := range status.Nodes {
+				labels := getRebalanceNodeLabels(status, node)
+				rebalanceGaugeVecs[glusterRebalanceFiles].Set(labels, float64(node.RebalancedFiles))
+				rebalanceGaugeVecs[glusterRebalanceBytes].Set(labels, float64(node.RebalancedSize))
+				rebalanceGaugeVecs[glusterRebalanceScannedFiles].Set(labels, float64(node.ScannedFiles))
+				rebalanceGaugeVecs[glusterRebalanceFailures].Set(labels, float64(node.Failures))
+				rebalanceGaugeVecs[glusterRebalanceSkippedFiles].Set(labels, float64(node.Skipped))
+				rebalanceGaugeVecs[glusterRebalanceRunTime].Set(labels, node.RunTime)
+				rebalanceGaugeVecs[glusterRebalanceStatus].Set(labels, float64(node.Status))
+			}
+			if status.TimeLeft >= 0 {
+				rebalanceGaugeVecs[glusterRebalanceTimeLeft].Set(getRebalanceTaskLabels(status), float64(status.TimeLeft))
+			}
+		}
+	}
+	return nil
+}
+
+func init() {
+	registerMetric("gluster_rebalance", collectorDefaults{SyncInterval: 30}, rebalance, rebalanceGaugeVecs)
+}
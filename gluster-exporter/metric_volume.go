@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"strings"
 
@@ -46,6 +47,22 @@ var (
 		Labels:    volumeHealLabels,
 	}, &volumeHealGaugeVecs)
 
+	glusterVolumeHealPendingCount = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "volume_heal_pending_count",
+		Help:      "self heal count for volume pending heal",
+		LongHelp:  "Only exported with the 'summary' heal-info-mode",
+		Labels:    volumeHealLabels,
+	}, &volumeHealGaugeVecs)
+
+	glusterVolumeHealPossiblyHealingCount = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "volume_heal_possibly_healing_count",
+		Help:      "self heal count for volume possibly being healed",
+		LongHelp:  "Only exported with the 'summary' heal-info-mode",
+		Labels:    volumeHealLabels,
+	}, &volumeHealGaugeVecs)
+
 	volumeProfileInfoLabels = []MetricLabel{
 		clusterIDLabel,
 		{
@@ -310,8 +327,8 @@ func getVolumeHealLabels(volname string, host string, brick string) prometheus.L
 
 }
 
-func healCounts(gluster glusterutils.GInterface) error {
-	isLeader, err := gluster.IsLeader()
+func healCounts(ctx context.Context, gluster glusterutils.GInterface) error {
+	isLeader, err := gluster.IsLeader(ctx)
 
 	// Reset all vecs to not export stale information
 	for _, gaugeVec := range volumeHealGaugeVecs {
@@ -327,21 +344,21 @@ func healCounts(gluster glusterutils.GInterface) error {
 	if !isLeader {
 		return nil
 	}
-	volumes, err := gluster.VolumeInfo()
+	volumes, err := gluster.VolumeInfo(ctx)
 	if err != nil {
 		return err
 	}
 
 	// locHealInfoFunc is a function literal, which takes
-	// arg1: f1 a function which takes a string and returns ([]HealEntry, error)
+	// arg1: f1 a function which takes a context and a string and returns ([]HealEntry, error)
 	// (can be 'HealInfo' or 'SplitBrainHealInfo')
 	// arg2: gVect a pointer to GaugeVec
 	// (can be either 'glusterVolumeHealCount' or 'glusterVolumeSplitBrainHealCount')
 	// arg3: volName a string representing the volume name
 	// arg4: errStr the error string in case of error
-	locHealInfoFunc := func(f1 func(string) ([]glusterutils.HealEntry, error), gVect string, volName string, errStr string) {
+	locHealInfoFunc := func(f1 func(context.Context, string) ([]glusterutils.HealEntry, error), gVect string, volName string, errStr string) {
 		// Get the heal count
-		heals, err := f1(volName)
+		heals, err := f1(ctx, volName)
 		if err != nil {
 			log.WithError(err).WithFields(log.Fields{
 				"volume": volName,
@@ -349,11 +366,26 @@ func healCounts(gluster glusterutils.GInterface) error {
 			return
 		}
 		for _, healinfo := range heals {
+			// heal count is not known for the disconnected bricks
+			if healinfo.NumHealEntries < 0 {
+				continue
+			}
 			labels := getVolumeHealLabels(volName, healinfo.Hostname, healinfo.Brick)
 			volumeHealGaugeVecs[gVect].Set(labels, float64(healinfo.NumHealEntries))
 		}
 	}
 
+	// the summary, used by default, has all the counts, gathered in a
+	// single crawl
+	if getCollectorConf("gluster_volume_heal").HealInfoMode != conf.HealInfoModeFull {
+		for _, volume := range volumes {
+			if strings.Contains(volume.Type, "Replicate") || strings.Contains(volume.Type, "Disperse") {
+				healSummaryCounts(ctx, gluster, volume)
+			}
+		}
+		return nil
+	}
+
 	for _, volume := range volumes {
 		name := volume.Name
 		if strings.Contains(volume.Type, "Replicate") {
@@ -367,6 +399,31 @@ func healCounts(gluster glusterutils.GInterface) error {
 	return nil
 }
 
+// healSummaryCounts exports the heal counts of the volume from the heal
+// info summary, the split brain count only for the replicate volumes
+func healSummaryCounts(ctx context.Context, gluster glusterutils.GInterface, volume glusterutils.Volume) {
+	summaries, err := gluster.HealInfoSummary(ctx, volume.Name)
+	if err != nil {
+		log.WithError(err).WithFields(log.Fields{
+			"volume": volume.Name,
+		}).Debug("Error getting heal info summary")
+		return
+	}
+	for _, summary := range summaries {
+		// heal counts are not known for the disconnected bricks
+		if summary.Total < 0 {
+			continue
+		}
+		labels := getVolumeHealLabels(volume.Name, summary.Hostname, summary.Brick)
+		volumeHealGaugeVecs[glusterVolumeHealCount].Set(labels, float64(summary.Total))
+		volumeHealGaugeVecs[glusterVolumeHealPendingCount].Set(labels, float64(summary.Pending))
+		volumeHealGaugeVecs[glusterVolumeHealPossiblyHealingCount].Set(labels, float64(summary.PossiblyHealing))
+		if strings.Contains(volume.Type, "Replicate") {
+			volumeHealGaugeVecs[glusterVolumeSplitBrainHealCount].Set(labels, float64(summary.SplitBrain))
+		}
+	}
+}
+
 func getVolumeProfileInfoLabels(volname string, brick string) prometheus.Labels {
 	return prometheus.Labels{
 		"cluster_id": clusterID,
@@ -397,13 +454,13 @@ func getBrickHost(vol glusterutils.Volume, brickname string) string {
 	return ""
 }
 
-func profileInfo(gluster glusterutils.GInterface) error {
+func profileInfo(ctx context.Context, gluster glusterutils.GInterface) error {
 	// Reset all vecs to not export stale information
 	for _, gaugeVec := range volumeProfileGaugeVecs {
 		gaugeVec.RemoveStaleMetrics()
 	}
 
-	isLeader, err := gluster.IsLeader()
+	isLeader, err := gluster.IsLeader(ctx)
 
 	if err != nil {
 		log.WithError(err).Debug("Unable to find if the current node is leader")
@@ -413,7 +470,7 @@ func profileInfo(gluster glusterutils.GInterface) error {
 		return nil
 	}
 
-	volumes, err := gluster.VolumeInfo()
+	volumes, err := gluster.VolumeInfo(ctx)
 	if err != nil {
 		return err
 	}
@@ -432,7 +489,7 @@ func profileInfo(gluster glusterutils.GInterface) error {
 			newEntryOpType(), newINodeOpType()}
 	)
 	for _, volume := range volumes {
-		err := gluster.EnableVolumeProfiling(volume)
+		err := gluster.EnableVolumeProfiling(ctx, volume)
 		if err != nil {
 			log.WithError(err).WithFields(log.Fields{
 				"volume": volume.Name,
@@ -444,7 +501,7 @@ func profileInfo(gluster glusterutils.GInterface) error {
 			continue
 		}
 		name := volume.Name
-		profileinfo, err := gluster.VolumeProfileInfo(name)
+		profileinfo, err := gluster.VolumeProfileInfo(ctx, name)
 		if err != nil {
 			log.WithError(err).WithFields(log.Fields{
 				"volume": name,
@@ -487,6 +544,8 @@ func profileInfo(gluster glusterutils.GInterface) error {
 }
 
 func init() {
-	registerMetric("gluster_volume_heal", healCounts)
-	registerMetric("gluster_volume_profile", profileInfo)
+	// heal info crawls the pending entries of every brick
+	registerMetric("gluster_volume_heal", collectorDefaults{SyncInterval: 60}, healCounts, volumeHealGaugeVecs)
+	// enabling the profiling sets the diagnostics options of every volume
+	registerMetric("gluster_volume_profile", collectorDefaults{SyncInterval: 60, Disabled: true}, profileInfo, volumeProfileGaugeVecs)
 }
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"context"
+	"net"
+
+	"github.com/gluster/gluster-prometheus/pkg/glusterutils"
+	"github.com/gluster/gluster-prometheus/pkg/glusterutils/glusterconsts"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+// defaultClientLabelLimit is the max no of client hosts of a volume
+// exported as labels, when not configured
+const defaultClientLabelLimit = 100
+
+var (
+	volumeClientsLabels = []MetricLabel{
+		clusterIDLabel,
+		{
+			Name: "volume",
+			Help: "Volume Name",
+		},
+	}
+
+	brickClientsLabels = []MetricLabel{
+		clusterIDLabel,
+		{
+			Name: "volume",
+			Help: "Volume Name",
+		},
+		{
+			Name: "host",
+			Help: "Host name or IP of the brick",
+		},
+		{
+			Name: "brick_path",
+			Help: "Brick Path",
+		},
+	}
+
+	brickClientLabels = []MetricLabel{
+		clusterIDLabel,
+		{
+			Name: "volume",
+			Help: "Volume Name",
+		},
+		{
+			Name: "host",
+			Help: "Host name or IP of the brick",
+		},
+		{
+			Name: "brick_path",
+			Help: "Brick Path",
+		},
+		{
+			Name: "client",
+			Help: "Address of the client, without the port of the connection",
+		},
+	}
+
+	volumeClientsGaugeVecs = make(map[string]*ExportedGaugeVec)
+
+	glusterVolumeClientHosts = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "volume_client_hosts",
+		Help:      "No of distinct client hosts connected to the online bricks of the volume",
+		Labels:    volumeClientsLabels,
+	}, &volumeClientsGaugeVecs)
+
+	glusterBrickClientConnections = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "brick_client_connections",
+		Help:      "No of client connections to the brick",
+		LongHelp: "It includes the connections " +
+			"of the gluster daemons like the self-heal daemon. Not exported for the offline bricks",
+		Labels: brickClientsLabels,
+	}, &volumeClientsGaugeVecs)
+
+	glusterBrickClientReadBytes = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "brick_client_read_bytes",
+		Help:      "Bytes read by the brick from the connections of the client",
+		LongHelp: "A gauge, as it sums the bytes of the current connections of the client, " +
+			"it drops when a connection is closed or the brick restarts. Not exported " +
+			"if the volume has more client hosts than the 'client-label-limit' of the collector",
+		Labels: brickClientLabels,
+	}, &volumeClientsGaugeVecs)
+
+	glusterBrickClientWriteBytes = registerExportedGaugeVec(Metric{
+		Namespace: "gluster",
+		Name:      "brick_client_write_bytes",
+		Help:      "Bytes written by the brick to the connections of the client",
+		LongHelp: "A gauge like gluster_brick_client_read_bytes, it drops when a connection " +
+			"is closed or the brick restarts. Not exported " +
+			"if the volume has more client hosts than the 'client-label-limit' of the collector",
+		Labels: brickClientLabels,
+	}, &volumeClientsGaugeVecs)
+)
+
+func getBrickClientsLabels(brick glusterutils.BrickClients) prometheus.Labels {
+	return prometheus.Labels{
+		"cluster_id": clusterID,
+		"volume":     brick.Volume,
+		"host":       brick.Hostname,
+		"brick_path": brick.Path,
+	}
+}
+
+func getBrickClientLabels(brick glusterutils.BrickClients, client string) prometheus.Labels {
+	return prometheus.Labels{
+		"cluster_id": clusterID,
+		"volume":     brick.Volume,
+		"host":       brick.Hostname,
+		"brick_path": brick.Path,
+		"client":     client,
+	}
+}
+
+// clientHost returns the address of the client without the port of
+// the connection, the connections of a client differ only by the port
+func clientHost(hostname string) string {
+	host, _, err := net.SplitHostPort(hostname)
+	if err != nil {
+		return hostname
+	}
+	return host
+}
+
+func volumeClients(ctx context.Context, gluster glusterutils.GInterface) error {
+	isLeader, err := gluster.IsLeader(ctx)
+
+	// Reset all vecs to not export stale information
+	for _, gaugeVec := range volumeClientsGaugeVecs {
+		gaugeVec.RemoveStaleMetrics()
+	}
+
+	if err != nil {
+		// Unable to find out if the current node is leader
+		// Cannot register volume client metrics at this node
+		log.WithError(err).Debug("Unable to find if the current node is leader")
+		return err
+	}
+	// the clients of all the bricks are
+	// available from any node, export only from the leader
+	if !isLeader {
+		return nil
+	}
+
+	volumes, err := gluster.VolumeInfo(ctx)
+	if err != nil {
+		return err
+	}
+
+	// a negative limit exports only the counts
+	labelLimit := getCollectorConf("gluster_volume_clients").ClientLabelLimit
+	if labelLimit == 0 {
+		labelLimit = defaultClientLabelLimit
+	}
+
+	for _, volume := range volumes {
+		if volume.State != glusterconsts.VolumeStateStarted {
+			continue
+		}
+		bricks, err := gluster.VolumeClients(ctx, volume.Name)
+		if err != nil {
+			log.WithError(err).WithFields(log.Fields{
+				"volume": volume.Name,
+			}).Debug("[Gluster Volume Clients] Error getting volume clients")
+			return err
+		}
+
+		// bytes read and written by each brick, per client host
+		type clientBytes struct {
+			read  uint64
+			write uint64
+		}
+		brickClientBytes := make([]map[string]*clientBytes, len(bricks))
+		hosts := make(map[string]struct{})
+		for idx, brick := range bricks {
+			if brick.Status != 1 {
+				continue
+			}
+			brickClientBytes[idx] = make(map[string]*clientBytes)
+			for _, client := range brick.Clients {
+				host := clientHost(client.Hostname)
+				hosts[host] = struct{}{}
+				if _, ok := brickClientBytes[idx][host]; !ok {
+					brickClientBytes[idx][host] = &clientBytes{}
+				}
+				brickClientBytes[idx][host].read += client.BytesRead
+				brickClientBytes[idx][host].write += client.BytesWrite
+			}
+			volumeClientsGaugeVecs[glusterBrickClientConnections].Set(getBrickClientsLabels(brick), float64(brick.ClientCount))
+		}
+		volumeClientsGaugeVecs[glusterVolumeClientHosts].Set(prometheus.Labels{
+			"cluster_id": clusterID,
+			"volume":     volume.Name,
+		}, float64(len(hosts)))
+
+		if labelLimit < 0 || len(hosts) > labelLimit {
+			log.WithFields(log.Fields{
+				"volume":       volume.Name,
+				"client_hosts": len(hosts),
+				"limit":        labelLimit,
+			}).Debug("[Gluster Volume Clients] Too many client hosts, exporting only the counts")
+			continue
+		}
+		for idx, brick := range bricks {
+			for host, bytes := range brickClientBytes[idx] {
+				labels := getBrickClientLabels(brick, host)
+				volumeClientsGaugeVecs[glusterBrickClientReadBytes].Set(labels, float64(bytes.read))
+				volumeClientsGaugeVecs[glusterBrickClientWriteBytes].Set(labels, float64(bytes.write))
+			}
+		}
+	}
+	return nil
+}
+
+func init() {
+	registerMetric("gluster_volume_clients", collectorDefaults{SyncInterval: 30}, volumeClients, volumeClientsGaugeVecs)
+}
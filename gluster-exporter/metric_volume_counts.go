@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+
 	"github.com/gluster/gluster-prometheus/pkg/glusterutils"
 	"github.com/gluster/gluster-prometheus/pkg/glusterutils/glusterconsts"
 
@@ -87,13 +89,13 @@ func getVolumeLabels(volname string) prometheus.Labels {
 	}
 }
 
-func volumeCounts(gluster glusterutils.GInterface) error {
+func volumeCounts(ctx context.Context, gluster glusterutils.GInterface) error {
 	// Reset all vecs to not export stale information
 	for _, gaugeVec := range volumeCountGaugeVecs {
 		gaugeVec.RemoveStaleMetrics()
 	}
 
-	isLeader, err := gluster.IsLeader()
+	isLeader, err := gluster.IsLeader(ctx)
 
 	if err != nil {
 		log.WithError(err).Debug("Unable to find if the current node is leader")
@@ -102,11 +104,11 @@ func volumeCounts(gluster glusterutils.GInterface) error {
 	if !isLeader {
 		return nil
 	}
-	volumes, err := gluster.VolumeInfo()
+	volumes, err := gluster.VolumeInfo(ctx)
 	if err != nil {
 		return err
 	}
-	snapshots, err := gluster.Snapshots()
+	snapshots, err := gluster.Snapshots(ctx)
 	if err != nil {
 		return err
 	}
@@ -158,5 +160,5 @@ func volumeCounts(gluster glusterutils.GInterface) error {
 }
 
 func init() {
-	registerMetric("gluster_volume_counts", volumeCounts)
+	registerMetric("gluster_volume_counts", collectorDefaults{SyncInterval: 5}, volumeCounts, volumeCountGaugeVecs)
 }
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"strconv"
 
 	"github.com/gluster/gluster-prometheus/pkg/glusterutils"
@@ -105,7 +106,7 @@ var (
 	}, &volStatusGaugeVecs)
 )
 
-func volumeInfo(gluster glusterutils.GInterface) (err error) {
+func volumeInfo(ctx context.Context, gluster glusterutils.GInterface) (err error) {
 	// Reset all vecs to not export stale information
 	for _, gaugeVec := range volStatusGaugeVecs {
 		gaugeVec.RemoveStaleMetrics()
@@ -114,19 +115,19 @@ func volumeInfo(gluster glusterutils.GInterface) (err error) {
 	var peerID string
 
 	if gluster != nil {
-		if peerID, err = gluster.LocalPeerID(); err != nil {
+		if peerID, err = gluster.LocalPeerID(ctx); err != nil {
 			return
 		}
 	}
 
-	volumes, err := gluster.VolumeStatus()
+	volumes, err := gluster.VolumeStatus(ctx)
 	if err != nil {
 		log.WithError(err).WithFields(log.Fields{"peer": peerID}).Debug("[Gluster Volume Status] Error:", err)
 		return err
 	}
 
 	// Get monitored gluster instance FQDN
-	peers, err := gluster.Peers()
+	peers, err := gluster.Peers(ctx)
 	if err != nil {
 		log.WithError(err).WithFields(log.Fields{"peer": peerID}).Debug("[Gluster Volume Status] Error:", err)
 		return err
@@ -174,5 +175,5 @@ func volumeInfo(gluster glusterutils.GInterface) (err error) {
 }
 
 func init() {
-	registerMetric("gluster_volume_status", volumeInfo)
+	registerMetric("gluster_volume_status", collectorDefaults{SyncInterval: 5}, volumeInfo, volStatusGaugeVecs)
 }
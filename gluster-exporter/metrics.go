@@ -1,6 +1,7 @@
 package main
 
 import (
+	"sync"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -22,6 +23,9 @@ type Metric struct {
 	Disabled  bool
 	Labels    []MetricLabel
 	TTL       time.Duration
+	// Counter exports the metric as a counter, for the values read
+	// from a cumulative source like the cpu time of a process
+	Counter bool
 }
 
 // LabelNames returns list of Prometheus labels
@@ -43,6 +47,13 @@ type MetricWithTTL struct {
 	Labels      prometheus.Labels
 }
 
+// constSample represents a gauge value collected during a scrape,
+// which is exported as a const metric
+type constSample struct {
+	LabelValues []string
+	Value       float64
+}
+
 // ExportedGaugeVec represents each GaugeVec with additional information
 type ExportedGaugeVec struct {
 	Namespace string
@@ -53,6 +64,33 @@ type ExportedGaugeVec struct {
 	GaugeVec  *prometheus.GaugeVec
 	Metrics   map[uint64]MetricWithTTL
 	TTL       time.Duration
+	Desc      *prometheus.Desc
+	ValueType prometheus.ValueType
+	// scrapeMode is set when the owning collector runs on scrape,
+	// values are then buffered in 'samples' instead of the GaugeVec
+	scrapeMode bool
+	// the counters are always buffered in 'samples', since their
+	// values are set like the gauges, and exported as const metrics
+	samples     map[uint64]constSample
+	samplesLock sync.Mutex
+	// registered is the collector registered with Prometheus, the
+	// GaugeVec or the samples collector of a counter
+	registered prometheus.Collector
+}
+
+// samplesCollector exports the buffered samples of a counter
+type samplesCollector struct {
+	gaugeVec *ExportedGaugeVec
+}
+
+// Describe implements prometheus.Collector
+func (c samplesCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.gaugeVec.Desc
+}
+
+// Collect implements prometheus.Collector
+func (c samplesCollector) Collect(ch chan<- prometheus.Metric) {
+	c.gaugeVec.collectSamples(ch)
 }
 
 func registerExportedGaugeVec(m Metric, exported *map[string]*ExportedGaugeVec) string {
@@ -65,8 +103,6 @@ func registerExportedGaugeVec(m Metric, exported *map[string]*ExportedGaugeVec)
 		m.LabelNames(),
 	)
 
-	// Register the metric with Prometheus
-	prometheus.MustRegister(gaugeVec)
 	ttl := m.TTL
 	if ttl == 0 {
 		ttl = defaultMetricTTL
@@ -75,7 +111,7 @@ func registerExportedGaugeVec(m Metric, exported *map[string]*ExportedGaugeVec)
 	// Add the metric to the global queue
 	metrics = append(metrics, m)
 
-	(*exported)[m.Name] = &ExportedGaugeVec{
+	exportedGaugeVec := &ExportedGaugeVec{
 		Namespace: m.Namespace,
 		Name:      m.Name,
 		Help:      m.Help,
@@ -84,7 +120,24 @@ func registerExportedGaugeVec(m Metric, exported *map[string]*ExportedGaugeVec)
 		GaugeVec:  gaugeVec,
 		Metrics:   make(map[uint64]MetricWithTTL),
 		TTL:       ttl,
+		Desc: prometheus.NewDesc(
+			prometheus.BuildFQName(m.Namespace, "", m.Name),
+			m.Help,
+			m.LabelNames(),
+			nil,
+		),
+		ValueType:  prometheus.GaugeValue,
+		registered: gaugeVec,
 	}
+	if m.Counter {
+		exportedGaugeVec.ValueType = prometheus.CounterValue
+		exportedGaugeVec.registered = samplesCollector{gaugeVec: exportedGaugeVec}
+		exportedGaugeVec.resetSamples()
+	}
+
+	// Register the metric with Prometheus
+	prometheus.MustRegister(exportedGaugeVec.registered)
+	(*exported)[m.Name] = exportedGaugeVec
 	return m.Name
 }
 
@@ -102,13 +155,18 @@ func (gv *ExportedGaugeVec) setMetricLastUpdated(labels prometheus.Labels) {
 // RemoveStaleMetrics removes all the stale metrics which are not
 // exported for TTL period.
 func (gv *ExportedGaugeVec) RemoveStaleMetrics() {
-	if gv.TTL == 0 {
+	// Const metrics exported on scrape never go stale
+	if gv.TTL == 0 || gv.scrapeMode {
 		return
 	}
 
 	now := time.Now()
-	for _, metric := range gv.Metrics {
+	for hash, metric := range gv.Metrics {
 		if metric.LastUpdated.Add(gv.TTL).Before(now) {
+			if gv.ValueType == prometheus.CounterValue {
+				gv.deleteSample(hash)
+				continue
+			}
 			gv.GaugeVec.Delete(metric.Labels)
 		}
 	}
@@ -116,6 +174,81 @@ func (gv *ExportedGaugeVec) RemoveStaleMetrics() {
 
 // Set updates the Gauge Value and last update time
 func (gv *ExportedGaugeVec) Set(labels prometheus.Labels, value float64) {
-	gv.GaugeVec.With(labels).Set(value)
+	if gv.scrapeMode {
+		gv.addSample(labels, value)
+		return
+	}
+	if gv.ValueType == prometheus.CounterValue {
+		gv.addSample(labels, value)
+	} else {
+		gv.GaugeVec.With(labels).Set(value)
+	}
 	gv.setMetricLastUpdated(labels)
 }
+
+// enableScrapeMode unregisters the GaugeVec from Prometheus, from now on
+// the values are only exported as const metrics by the owning collector
+func (gv *ExportedGaugeVec) enableScrapeMode() {
+	prometheus.Unregister(gv.registered)
+	gv.scrapeMode = true
+	gv.resetSamples()
+}
+
+// disableScrapeMode registers the GaugeVec with Prometheus again, when
+// the owning collector is switched back to run in the background
+func (gv *ExportedGaugeVec) disableScrapeMode() {
+	if !gv.scrapeMode {
+		return
+	}
+	gv.scrapeMode = false
+	gv.resetSamples()
+	prometheus.MustRegister(gv.registered)
+}
+
+// reset removes all the values, when the owning collector is stopped
+func (gv *ExportedGaugeVec) reset() {
+	gv.GaugeVec.Reset()
+	gv.Metrics = make(map[uint64]MetricWithTTL)
+	gv.resetSamples()
+}
+
+func (gv *ExportedGaugeVec) resetSamples() {
+	gv.samplesLock.Lock()
+	defer gv.samplesLock.Unlock()
+	gv.samples = make(map[uint64]constSample)
+}
+
+func (gv *ExportedGaugeVec) deleteSample(hash uint64) {
+	gv.samplesLock.Lock()
+	defer gv.samplesLock.Unlock()
+	delete(gv.samples, hash)
+}
+
+func (gv *ExportedGaugeVec) addSample(labels prometheus.Labels, value float64) {
+	labelValues := make([]string, len(gv.Labels))
+	for idx, name := range gv.Labels {
+		labelValues[idx] = labels[name]
+	}
+	gv.samplesLock.Lock()
+	defer gv.samplesLock.Unlock()
+	// Same label combination set again in a cycle, last value wins
+	gv.samples[model.LabelsToSignature(labels)] = constSample{
+		LabelValues: labelValues,
+		Value:       value,
+	}
+}
+
+// collectSamples sends the buffered samples as const metrics
+func (gv *ExportedGaugeVec) collectSamples(ch chan<- prometheus.Metric) {
+	gv.samplesLock.Lock()
+	defer gv.samplesLock.Unlock()
+	for _, sample := range gv.samples {
+		metric, err := prometheus.NewConstMetric(gv.Desc,
+			gv.ValueType, sample.Value, sample.LabelValues...)
+		if err != nil {
+			ch <- prometheus.NewInvalidMetric(gv.Desc, err)
+			continue
+		}
+		ch <- metric
+	}
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"os"
+	"os/signal"
+	"reflect"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/gluster/gluster-prometheus/gluster-exporter/conf"
+	"github.com/gluster/gluster-prometheus/pkg/glusterutils"
+	"github.com/gluster/gluster-prometheus/pkg/logging"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// configCheckInterval is how often the config file is checked for changes
+const configCheckInterval = 5 * time.Second
+
+// initLogging creates the log directory and (re)opens the log file
+func initLogging(exporterConf *conf.Config) error {
+	logFile := strings.ToLower(exporterConf.LogFile)
+	if logFile != "stderr" && logFile != "-" && logFile != "stdout" {
+		if err := os.MkdirAll(exporterConf.LogDir, 0750); err != nil {
+			return err
+		}
+	}
+	return logging.Init(exporterConf.LogDir, exporterConf.LogFile, exporterConf.LogLevel)
+}
+
+// restartOptions returns the options which changed, but are only
+// applied on the start of the exporter
+func restartOptions(running, loaded *conf.Config) []string {
+	// the running gluster configuration has its defaults set, the
+	// cluster id, which labels all the metrics, is named on its own
+	runningGConfig, loadedGConfig := *running.GConfig(), *loaded.GConfig()
+	glusterutils.SetDefaultConfig(&loadedGConfig)
+	runningGConfig.GlusterClusterID, loadedGConfig.GlusterClusterID = "", ""
+	var changed []string
+	for _, option := range []struct {
+		name            string
+		running, loaded interface{}
+	}{
+		{"listen-address", running.ListenAddress, loaded.ListenAddress},
+		{"port", running.Port, loaded.Port},
+		{"web-config-file", running.WebConfigFile, loaded.WebConfigFile},
+		{"metrics-path", running.MetricsPath, loaded.MetricsPath},
+		{"events-path", running.EventsPath, loaded.EventsPath},
+		{"events-bearer-token", running.EventsToken, loaded.EventsToken},
+		{"events-secret", running.EventsSecret, loaded.EventsSecret},
+		{"gluster-cluster-id", running.GlusterClusterID, loaded.GlusterClusterID},
+		{"gluster configuration", runningGConfig, loadedGConfig},
+	} {
+		if !reflect.DeepEqual(option.running, option.loaded) {
+			changed = append(changed, option.name)
+		}
+	}
+	return changed
+}
+
+// configReloader reloads the config file on SIGHUP or when the file
+// changes, and applies the new configuration to the running exporter
+type configReloader struct {
+	path string
+	// applied is the configuration last applied, the changes of the
+	// restart options are warned about once against it
+	applied   *conf.Config
+	gluster   glusterutils.GInterface
+	scheduler *collectorScheduler
+	// modTime and size are of the config file last loaded
+	modTime time.Time
+	size    int64
+}
+
+func newConfigReloader(path string, started *conf.Config, gi glusterutils.GInterface, scheduler *collectorScheduler) *configReloader {
+	r := &configReloader{
+		path:      path,
+		applied:   started,
+		gluster:   gi,
+		scheduler: scheduler,
+	}
+	r.changed()
+	return r
+}
+
+// changed returns true if the config file changed since it was last
+// checked. A missing file, like while it is replaced, is not a change
+func (r *configReloader) changed() bool {
+	info, err := os.Stat(r.path)
+	if err != nil {
+		return false
+	}
+	if info.ModTime().Equal(r.modTime) && info.Size() == r.size {
+		return false
+	}
+	r.modTime = info.ModTime()
+	r.size = info.Size()
+	return true
+}
+
+// watch reloads the configuration on SIGHUP or when the config file
+// changes, it never returns
+func (r *configReloader) watch() {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	ticker := time.NewTicker(configCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-hup:
+			r.changed()
+			r.reload("SIGHUP")
+		case <-ticker.C:
+			if r.changed() {
+				r.reload("file change")
+			}
+		}
+	}
+}
+
+// reload loads the config file and applies it. An invalid configuration
+// is rejected, and the running configuration is kept
+func (r *configReloader) reload(trigger string) {
+	logger := log.WithFields(log.Fields{
+		"config":  r.path,
+		"trigger": trigger,
+	})
+	exporterConf, err := loadConfig(r.path)
+	if err != nil {
+		logger.WithError(err).Error("Invalid config, keeping the running config")
+		return
+	}
+
+	// the log file is reopened even if unchanged, for logrotate
+	if err := initLogging(exporterConf); err != nil {
+		logger.WithError(err).Error("Failed to reopen the log file")
+	}
+	for _, option := range restartOptions(r.applied, exporterConf) {
+		logger.WithField("option", option).Warn("Changed option is only applied on restart")
+	}
+	if gc, ok := r.gluster.(*glusterutils.GCache); ok {
+		gc.Reconfigure(time.Duration(exporterConf.CacheTTL)*time.Second, exporterConf.CacheEnabledFuncs)
+	}
+	r.scheduler.apply(exporterConf)
+	r.applied = exporterConf
+	logger.Info("Reloaded the config")
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gluster/gluster-prometheus/gluster-exporter/conf"
+	"github.com/gluster/gluster-prometheus/pkg/glusterutils"
+)
+
+func TestRestartOptions(t *testing.T) {
+	newConf := func(clusterID string, port int) *conf.Config {
+		gConfig := &conf.GConfig{GlusterClusterID: clusterID}
+		glusterutils.SetDefaultConfig(gConfig)
+		return &conf.Config{Globals: &conf.Globals{GConfig: gConfig, Port: port}}
+	}
+	running := newConf("cluster-a", 9713)
+	if changed := restartOptions(running, newConf("cluster-a", 9713)); len(changed) != 0 {
+		t.Errorf("expected no changes, got %v", changed)
+	}
+	expected := []string{"port", "gluster-cluster-id"}
+	if changed := restartOptions(running, newConf("cluster-b", 9714)); !reflect.DeepEqual(changed, expected) {
+		t.Errorf("expected %v, got %v", expected, changed)
+	}
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gluster/gluster-prometheus/gluster-exporter/conf"
+	"github.com/gluster/gluster-prometheus/pkg/glusterutils"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+// runningCollector is an enabled collector, with the
+// mode and the settings it was started with
+type runningCollector struct {
+	metric glusterMetric
+	mode   string
+	// interval and intervals are only used in the background mode, the
+	// new sync interval is sent on intervals to reschedule the collector
+	interval  time.Duration
+	intervals chan time.Duration
+	cancel    context.CancelFunc
+	done      chan struct{}
+	// timeout and scrape are only used in the scrape mode
+	timeout time.Duration
+	scrape  *scrapeCollector
+}
+
+// runInBackground runs the collector every sync interval, till the
+// context is cancelled. A new sync interval applies from the last run
+func (c *runningCollector) runInBackground(ctx context.Context, gi glusterutils.GInterface, interval time.Duration) {
+	defer close(c.done)
+	for {
+		if err := c.metric.run(ctx, gi); err != nil && ctx.Err() == nil {
+			log.WithError(err).WithFields(log.Fields{
+				"name": c.metric.name,
+			}).Debug("failed to export metric")
+		}
+		lastRun := time.Now()
+		timer := time.NewTimer(interval)
+	wait:
+		for {
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case interval = <-c.intervals:
+				timer.Stop()
+				timer = time.NewTimer(time.Until(lastRun.Add(interval)))
+			case <-timer.C:
+				break wait
+			}
+		}
+	}
+}
+
+// reschedule changes the sync interval of the background collector
+func (c *runningCollector) reschedule(interval time.Duration) {
+	c.interval = interval
+	// the previous interval is replaced if not received yet
+	select {
+	case <-c.intervals:
+	default:
+	}
+	c.intervals <- interval
+}
+
+// collectorsPath is the path of the list of the collectors
+const collectorsPath = "/collectors"
+
+// collectorStatus is a collector listed by the /collectors endpoint
+type collectorStatus struct {
+	Name   string `json:"name"`
+	Active bool   `json:"active"`
+	// Reason tells why the collector is enabled or disabled
+	Reason string `json:"reason"`
+	Mode   string `json:"mode,omitempty"`
+	// SyncInterval is only set in the background mode
+	SyncInterval float64 `json:"sync_interval_seconds,omitempty"`
+}
+
+// collectorScheduler runs the enabled collectors, in the background or
+// on scrape, and applies the changes of their configuration on reload
+type collectorScheduler struct {
+	gluster glusterutils.GInterface
+	lock    sync.Mutex
+	running map[string]*runningCollector
+	// statuses are the collectors as of the last apply, read
+	// by the /collectors endpoint while collectors are stopping
+	statuses     []collectorStatus
+	statusesLock sync.Mutex
+}
+
+func newCollectorScheduler(gi glusterutils.GInterface) *collectorScheduler {
+	return &collectorScheduler{
+		gluster: gi,
+		running: make(map[string]*runningCollector),
+	}
+}
+
+// apply starts the collectors enabled by the configuration, stops the
+// disabled ones, and restarts or reschedules the ones with changes
+func (s *collectorScheduler) apply(exporterConf *conf.Config) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	setCollectorsConf(exporterConf.CollectorsConf)
+	scrapeTimeout := time.Duration(exporterConf.ScrapeTimeout) * time.Second
+	defer s.setStatuses(exporterConf)
+
+	for _, m := range glusterMetrics {
+		current := s.running[m.name]
+		collectorConf, ok := exporterConf.CollectorsConf[m.name]
+		if !ok || collectorConf.Disabled {
+			if current != nil {
+				s.stop(current)
+				removeCollectorMetrics(m.name)
+				log.WithField("name", m.name).Info("Stopped the collector")
+			}
+			continue
+		}
+
+		mode := exporterConf.CollectorMode
+		if collectorConf.Mode != "" {
+			mode = collectorConf.Mode
+		}
+		interval := defaultInterval
+		if collectorConf.SyncInterval > 0 {
+			interval = time.Duration(collectorConf.SyncInterval)
+		}
+		interval *= time.Second
+
+		if current != nil && current.mode == mode {
+			if mode != conf.CollectorModeScrape {
+				if current.interval != interval {
+					current.reschedule(interval)
+					log.WithFields(log.Fields{
+						"name":     m.name,
+						"interval": interval,
+					}).Info("Rescheduled the collector")
+				}
+				continue
+			}
+			if current.timeout == scrapeTimeout {
+				continue
+			}
+		}
+		if current != nil {
+			s.stop(current)
+		}
+		s.start(m, mode, interval, scrapeTimeout)
+		if current != nil {
+			log.WithFields(log.Fields{
+				"name": m.name,
+				"mode": mode,
+			}).Info("Restarted the collector")
+		}
+	}
+}
+
+func (s *collectorScheduler) start(m glusterMetric, mode string, interval, scrapeTimeout time.Duration) {
+	initCollectorMetrics(m.name)
+	c := &runningCollector{metric: m, mode: mode}
+	if mode == conf.CollectorModeScrape {
+		c.timeout = scrapeTimeout
+		c.scrape = newScrapeCollector(m, s.gluster, scrapeTimeout)
+		prometheus.MustRegister(c.scrape)
+	} else {
+		for _, gaugeVec := range m.gaugeVecs {
+			gaugeVec.disableScrapeMode()
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		c.interval = interval
+		c.intervals = make(chan time.Duration, 1)
+		c.cancel = cancel
+		c.done = make(chan struct{})
+		go c.runInBackground(ctx, s.gluster, interval)
+	}
+	s.running[m.name] = c
+}
+
+// stop stops the collector and removes its values, the run in progress
+// is waited for, so that it sets no values after
+func (s *collectorScheduler) stop(c *runningCollector) {
+	if c.scrape != nil {
+		prometheus.Unregister(c.scrape)
+		c.scrape.wait()
+	} else {
+		c.cancel()
+		<-c.done
+	}
+	for _, gaugeVec := range c.metric.gaugeVecs {
+		gaugeVec.reset()
+	}
+	delete(s.running, c.metric.name)
+}
+
+func (s *collectorScheduler) setStatuses(exporterConf *conf.Config) {
+	statuses := make([]collectorStatus, 0, len(glusterMetrics))
+	for _, m := range glusterMetrics {
+		status := collectorStatus{
+			Name:   m.name,
+			Reason: collectorReason(exporterConf, m.name),
+		}
+		if c, ok := s.running[m.name]; ok {
+			status.Active = true
+			status.Mode = c.mode
+			if c.scrape == nil {
+				status.SyncInterval = c.interval.Seconds()
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	s.statusesLock.Lock()
+	defer s.statusesLock.Unlock()
+	s.statuses = statuses
+}
+
+// ServeHTTP lists the collectors in JSON, with their state
+func (s *collectorScheduler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.statusesLock.Lock()
+	statuses := s.statuses
+	s.statusesLock.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(statuses); err != nil {
+		log.WithError(err).Debug("Failed to write the collectors")
+	}
+}
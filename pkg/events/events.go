@@ -0,0 +1,154 @@
+// Package events parses the gluster events pushed by glustereventsd to the
+// webhooks registered with 'gluster-eventsapi webhook-add', and verifies
+// their authorization
+package events
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// TestEvent is the event of the request without a body sent by
+	// 'gluster-eventsapi webhook-add' and 'webhook-test', to check
+	// that the webhook is reachable
+	TestEvent = "TEST"
+	// jwtIssuer is the issuer of the tokens signed by glustereventsd
+	jwtIssuer = "gluster"
+	// jwtLeeway is the clock skew allowed between the
+	// gluster nodes and the exporter, for the token expiry
+	jwtLeeway = 30 * time.Second
+)
+
+var (
+	// ErrUnauthorized is returned when the authorization of the event
+	// is missing, or does not match the token or the secret
+	ErrUnauthorized = errors.New("unauthorized event")
+	// ErrInvalidEvent is returned when the event is not a gluster event
+	ErrInvalidEvent = errors.New("invalid event")
+
+	// eventNameRE matches the gluster event names, like 'PEER_DETACH'
+	eventNameRE = regexp.MustCompile(`^[A-Z][A-Z0-9_]{0,63}$`)
+)
+
+// Event is a gluster event, the message is specific to the event
+type Event struct {
+	NodeID    string                 `json:"nodeid"`
+	Timestamp int64                  `json:"ts"`
+	Name      string                 `json:"event"`
+	Message   map[string]interface{} `json:"message"`
+}
+
+// Time returns the time of the event
+func (e Event) Time() time.Time {
+	return time.Unix(e.Timestamp, 0)
+}
+
+// Parse parses the event, an empty body is the TestEvent
+func Parse(r io.Reader) (Event, error) {
+	var event Event
+	if err := json.NewDecoder(r).Decode(&event); err != nil {
+		if err == io.EOF {
+			return Event{Name: TestEvent}, nil
+		}
+		return Event{}, err
+	}
+	if !eventNameRE.MatchString(event.Name) {
+		return Event{}, ErrInvalidEvent
+	}
+	return event, nil
+}
+
+// Verifier verifies the authorization of the events, the 'Authorization'
+// header sent by glustereventsd for the webhook. The webhooks added with
+// '--bearer_token' send the token as is, and the webhooks added with
+// '--secret' send a JWT signed with the secret (HS256), for the event
+type Verifier struct {
+	Token  string
+	Secret string
+}
+
+// jwtHeader is the header of the tokens signed by glustereventsd
+type jwtHeader struct {
+	Algorithm string `json:"alg"`
+}
+
+// jwtClaims are the claims of the tokens signed by glustereventsd
+type jwtClaims struct {
+	Issuer    string `json:"iss"`
+	Subject   string `json:"sub"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// Enabled returns true if the events need to be authorized
+func (v Verifier) Enabled() bool {
+	return v.Token != "" || v.Secret != ""
+}
+
+// Verify verifies the 'Authorization' header of the event
+func (v Verifier) Verify(authorization string, event Event, now time.Time) error {
+	if !v.Enabled() {
+		return nil
+	}
+	const prefix = "Bearer "
+	if !strings.HasPrefix(authorization, prefix) {
+		return ErrUnauthorized
+	}
+	token := strings.TrimPrefix(authorization, prefix)
+	if v.Token != "" && hmac.Equal([]byte(token), []byte(v.Token)) {
+		return nil
+	}
+	if v.Secret != "" {
+		return verifyJWT(token, v.Secret, event.Name, now)
+	}
+	return ErrUnauthorized
+}
+
+func decodeSegment(segment string, value interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(segment, "="))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, value)
+}
+
+// verifyJWT verifies the token signed by glustereventsd for the event
+func verifyJWT(token, secret, eventName string, now time.Time) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return ErrUnauthorized
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil || header.Algorithm != "HS256" {
+		return ErrUnauthorized
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[2], "="))
+	if err != nil {
+		return ErrUnauthorized
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return ErrUnauthorized
+	}
+
+	var claims jwtClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return ErrUnauthorized
+	}
+	if claims.Issuer != jwtIssuer || claims.Subject != eventName {
+		return ErrUnauthorized
+	}
+	if now.After(time.Unix(claims.ExpiresAt, 0).Add(jwtLeeway)) {
+		return ErrUnauthorized
+	}
+	return nil
+}
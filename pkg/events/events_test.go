@@ -0,0 +1,97 @@
+package events
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// peerDetachJWT is the token signed with the secret 's3cr3t' by
+// glustereventsd for the PEER_DETACH event at 1791782067
+const peerDetachJWT = "eyJ0eXAiOiJKV1QiLCJhbGciOiJIUzI1NiJ9." +
+	"eyJleHAiOjE3OTE3ODIxMjcsImlzcyI6ImdsdXN0ZXIiLCJzdWIiOiJQRUVSX0RFVEFDSCIsImlhdCI6MTc5MTc4MjA2N30." +
+	"axjlkTksmkWbtnBZIxFIaCwY2P0SpGtosxHisADSdpg"
+
+func TestParse(t *testing.T) {
+	body := `{"nodeid": "8f0ec4a2-2c44-4a8e-9a5b-1f4e5a9b0c01", "ts": 1791782067,
+		"event": "PEER_DETACH", "message": {"host": "server3"}}`
+	event, err := Parse(strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("Parse failed: %s", err)
+	}
+	expected := Event{
+		NodeID:    "8f0ec4a2-2c44-4a8e-9a5b-1f4e5a9b0c01",
+		Timestamp: 1791782067,
+		Name:      "PEER_DETACH",
+		Message:   map[string]interface{}{"host": "server3"},
+	}
+	if !reflect.DeepEqual(event, expected) {
+		t.Errorf("expected %+v, got %+v", expected, event)
+	}
+
+	event, err = Parse(strings.NewReader(""))
+	if err != nil || event.Name != TestEvent {
+		t.Errorf("expected the test event, got %+v, %v", event, err)
+	}
+
+	for _, body := range []string{`{"event": "peer detach"}`, `{"ts": 1791782067}`, `not json`} {
+		if _, err := Parse(strings.NewReader(body)); err == nil {
+			t.Errorf("%s: expected an error", body)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	event := Event{Name: "PEER_DETACH", Timestamp: 1791782067}
+	now := time.Unix(1791782070, 0)
+	tests := []struct {
+		name          string
+		verifier      Verifier
+		authorization string
+		event         Event
+		now           time.Time
+		valid         bool
+	}{
+		{name: "disabled", valid: true},
+		{name: "token", verifier: Verifier{Token: "t0ken"}, authorization: "Bearer t0ken", valid: true},
+		{name: "wrong token", verifier: Verifier{Token: "t0ken"}, authorization: "Bearer other"},
+		{name: "missing", verifier: Verifier{Token: "t0ken"}},
+		{name: "jwt", verifier: Verifier{Secret: "s3cr3t"}, authorization: "Bearer " + peerDetachJWT, valid: true},
+		{name: "jwt wrong secret", verifier: Verifier{Secret: "other"}, authorization: "Bearer " + peerDetachJWT},
+		{name: "jwt other event", verifier: Verifier{Secret: "s3cr3t"}, authorization: "Bearer " + peerDetachJWT,
+			event: Event{Name: "VOLUME_STOP"}},
+		{name: "jwt expired", verifier: Verifier{Secret: "s3cr3t"}, authorization: "Bearer " + peerDetachJWT,
+			now: time.Unix(1791782127, 0).Add(time.Hour)},
+		{name: "jwt tampered", verifier: Verifier{Secret: "s3cr3t"}, authorization: "Bearer " + peerDetachJWT + "x"},
+	}
+	for _, tt := range tests {
+		if tt.event.Name == "" {
+			tt.event = event
+		}
+		if tt.now.IsZero() {
+			tt.now = now
+		}
+		err := tt.verifier.Verify(tt.authorization, tt.event, tt.now)
+		if tt.valid && err != nil {
+			t.Errorf("%s: expected valid, got %s", tt.name, err)
+		}
+		if !tt.valid && err != ErrUnauthorized {
+			t.Errorf("%s: expected %s, got %v", tt.name, ErrUnauthorized, err)
+		}
+	}
+}
+
+func TestMetricName(t *testing.T) {
+	for name, expected := range map[string]string{
+		"PEER_DETACH":      "PEER_DETACH",
+		"QUORUM_LOST":      "QUORUM_LOST",
+		"AFR_SPLIT_BRAIN":  "AFR_SPLIT_BRAIN",
+		"NOT_A_GLUSTER_EV": OtherEvent,
+		"PEER_DETACH_2":    OtherEvent,
+	} {
+		if got := MetricName(name); got != expected {
+			t.Errorf("%s: expected %s, got %s", name, expected, got)
+		}
+	}
+}
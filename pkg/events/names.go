@@ -0,0 +1,96 @@
+package events
+
+// OtherEvent is the name the events not known to glustereventsd are
+// counted under, so that the events can't create unbounded label values
+const OtherEvent = "OTHER"
+
+// knownEvents are the events sent by glustereventsd, as listed
+// in 'events/eventskeygen.py' of glusterfs without the 'EVENT_' prefix
+var knownEvents = map[string]struct{}{}
+
+func init() {
+	for _, name := range []string{
+		// peer
+		"PEER_ATTACH", "PEER_DETACH",
+		// volume
+		"VOLUME_CREATE", "VOLUME_START", "VOLUME_STOP", "VOLUME_DELETE",
+		"VOLUME_SET", "VOLUME_RESET",
+		"VOLUME_ADD_BRICK", "VOLUME_ADD_BRICK_FAILED",
+		"VOLUME_REMOVE_BRICK_START", "VOLUME_REMOVE_BRICK_START_FAILED",
+		"VOLUME_REMOVE_BRICK_COMMIT", "VOLUME_REMOVE_BRICK_COMMIT_FAILED",
+		"VOLUME_REMOVE_BRICK_STOP", "VOLUME_REMOVE_BRICK_STOP_FAILED",
+		"VOLUME_REMOVE_BRICK_FORCE", "VOLUME_REMOVE_BRICK_FORCE_FAILED",
+		"VOLUME_REMOVE_BRICK_FAILED",
+		"VOLUME_REBALANCE_START", "VOLUME_REBALANCE_STOP",
+		"VOLUME_REBALANCE_FAILED", "VOLUME_REBALANCE_COMPLETE",
+		// geo-replication
+		"GEOREP_CREATE", "GEOREP_START", "GEOREP_STOP", "GEOREP_PAUSE",
+		"GEOREP_RESUME", "GEOREP_DELETE", "GEOREP_CONFIG_SET",
+		"GEOREP_CONFIG_RESET", "GEOREP_FAULTY",
+		"GEOREP_CHECKPOINT_COMPLETED", "GEOREP_ACTIVE", "GEOREP_PASSIVE",
+		// bitrot
+		"BITROT_ENABLE", "BITROT_DISABLE", "BITROT_SCRUB_THROTTLE",
+		"BITROT_SCRUB_FREQ", "BITROT_SCRUB_OPTION", "BITROT_SCRUB",
+		"BITROT_BAD_FILE",
+		// quota
+		"QUOTA_ENABLE", "QUOTA_DISABLE", "QUOTA_SET_USAGE_LIMIT",
+		"QUOTA_SET_OBJECTS_LIMIT", "QUOTA_REMOVE_USAGE_LIMIT",
+		"QUOTA_REMOVE_OBJECTS_LIMIT", "QUOTA_ALERT_TIME",
+		"QUOTA_SOFT_TIMEOUT", "QUOTA_HARD_TIMEOUT",
+		"QUOTA_DEFAULT_SOFT_LIMIT", "QUOTA_CROSSED_SOFT_LIMIT",
+		// snapshot
+		"SNAPSHOT_CREATED", "SNAPSHOT_CREATE_FAILED",
+		"SNAPSHOT_ACTIVATED", "SNAPSHOT_ACTIVATE_FAILED",
+		"SNAPSHOT_DEACTIVATED", "SNAPSHOT_DEACTIVATE_FAILED",
+		"SNAPSHOT_SOFT_LIMIT_REACHED", "SNAPSHOT_HARD_LIMIT_REACHED",
+		"SNAPSHOT_RESTORED", "SNAPSHOT_RESTORE_FAILED",
+		"SNAPSHOT_DELETED", "SNAPSHOT_DELETE_FAILED",
+		"SNAPSHOT_CLONED", "SNAPSHOT_CLONE_FAILED",
+		"SNAPSHOT_CONFIG_UPDATED", "SNAPSHOT_CONFIG_UPDATE_FAILED",
+		"SNAPSHOT_SCHEDULER_INITIALISED", "SNAPSHOT_SCHEDULER_INIT_FAILED",
+		"SNAPSHOT_SCHEDULER_ENABLED", "SNAPSHOT_SCHEDULER_ENABLE_FAILED",
+		"SNAPSHOT_SCHEDULER_DISABLED", "SNAPSHOT_SCHEDULER_DISABLE_FAILED",
+		"SNAPSHOT_SCHEDULER_SCHEDULE_ADDED", "SNAPSHOT_SCHEDULER_SCHEDULE_ADD_FAILED",
+		"SNAPSHOT_SCHEDULER_SCHEDULE_EDITED", "SNAPSHOT_SCHEDULER_SCHEDULE_EDIT_FAILED",
+		"SNAPSHOT_SCHEDULER_SCHEDULE_DELETED", "SNAPSHOT_SCHEDULER_SCHEDULE_DELETE_FAILED",
+		// posix and changelog
+		"POSIX_SAME_GFID", "POSIX_ALREADY_PART_OF_VOLUME",
+		"POSIX_BRICK_NOT_IN_VOLUME", "POSIX_BRICK_VERIFICATION_FAILED",
+		"POSIX_ACL_NOT_SUPPORTED", "POSIX_HEALTH_CHECK_FAILED",
+		"CHANGELOG_BARRIER_TIMEOUT",
+		// glusterd
+		"SVC_MANAGER_FAILED", "SVC_CONNECTED", "SVC_DISCONNECTED",
+		"PEER_STORE_FAILURE", "PEER_RPC_CREATE_FAILED", "PEER_REJECT",
+		"PEER_CONNECT", "PEER_DISCONNECT", "PEER_NOT_FOUND", "UNKNOWN_PEER",
+		"BRICK_START_FAILED", "BRICK_STOP_FAILED", "BRICK_DISCONNECTED",
+		"BRICK_CONNECTED", "BRICKPATH_RESOLVE_FAILED", "NOTIFY_UNKNOWN_OP",
+		"QUORUM_LOST", "QUORUM_REGAINED",
+		"REBALANCE_START_FAILED", "REBALANCE_STATUS_UPDATE_FAILED",
+		"IMPORT_QUOTA_CONF_FAILED", "IMPORT_VOLUME_FAILED",
+		"IMPORT_BRICK_FAILED", "COMPARE_FRIEND_VOLUME_FAILED",
+		"NFS_GANESHA_EXPORT_FAILED",
+		// tier
+		"TIER_ATTACH", "TIER_ATTACH_FORCE", "TIER_DETACH_START",
+		"TIER_DETACH_STOP", "TIER_DETACH_COMMIT", "TIER_DETACH_FORCE",
+		"TIER_PAUSE", "TIER_RESUME", "TIER_WATERMARK_HI",
+		"TIER_WATERMARK_DROPPED_TO_MID", "TIER_WATERMARK_RAISED_TO_MID",
+		"TIER_WATERMARK_DROPPED_TO_LOW",
+		// afr and ec
+		"AFR_QUORUM_MET", "AFR_QUORUM_FAIL", "AFR_SUBVOL_UP",
+		"AFR_SUBVOLS_DOWN", "AFR_SPLIT_BRAIN",
+		"EC_MIN_BRICKS_NOT_UP", "EC_MIN_BRICKS_UP",
+		// client
+		"CLIENT_CONNECT", "CLIENT_AUTH_REJECT", "CLIENT_DISCONNECT",
+	} {
+		knownEvents[name] = struct{}{}
+	}
+}
+
+// MetricName returns the name the event is counted under, the
+// event itself if known to glustereventsd, or OtherEvent
+func MetricName(name string) string {
+	if _, ok := knownEvents[name]; ok {
+		return name
+	}
+	return OtherEvent
+}
@@ -0,0 +1,51 @@
+// Package glusterlog follows the gluster log files the way 'tail -F' does,
+// and parses the severity and the message ID of their lines
+package glusterlog
+
+import (
+	"regexp"
+)
+
+// severities are the names of the severity letters of the log lines
+var severities = map[string]string{
+	"T": "trace",
+	"D": "debug",
+	"I": "info",
+	"N": "notice",
+	"W": "warning",
+	"E": "error",
+	"C": "critical",
+	"A": "alert",
+	"M": "emergency",
+}
+
+// lineRE matches the gluster log lines, like
+// '[2026-10-12 03:14:27.512334 +0000] E [MSGID: 106004] [glusterd-handler.c:6204:...] 0-management: ...',
+// the message ID is missing in the lines logged without one
+var lineRE = regexp.MustCompile(`^\[[^\]]+\]\s+([TDINWECAM])\s+(?:\[MSGID:\s*(\d+)\])?`)
+
+// Line is a parsed log line
+type Line struct {
+	Severity string
+	MsgID    string
+}
+
+// ParseLine parses the severity and the message ID of the log line, it
+// returns false for the lines not in the gluster log format, like the
+// continuation lines of a multi-line message
+func ParseLine(line string) (Line, bool) {
+	matches := lineRE.FindStringSubmatch(line)
+	if matches == nil {
+		return Line{}, false
+	}
+	return Line{Severity: severities[matches[1]], MsgID: matches[2]}, true
+}
+
+// IsWarningOrAbove returns true if the severity is warning or worse
+func IsWarningOrAbove(severity string) bool {
+	switch severity {
+	case "warning", "error", "critical", "alert", "emergency":
+		return true
+	}
+	return false
+}
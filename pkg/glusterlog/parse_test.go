@@ -0,0 +1,46 @@
+package glusterlog
+
+import (
+	"testing"
+)
+
+func TestParseLine(t *testing.T) {
+	tests := []struct {
+		line     string
+		expected Line
+		ok       bool
+	}{
+		{
+			line: "[2026-10-12 03:14:27.512334 +0000] E [MSGID: 106004] [glusterd-handler.c:6204:" +
+				"__glusterd_peer_rpc_notify] 0-management: Peer <server3> (<5c7e3d2f>), in state " +
+				"<Peer in Cluster>, has disconnected from glusterd.",
+			expected: Line{Severity: "error", MsgID: "106004"},
+			ok:       true,
+		},
+		{
+			// the older gluster versions log the time without the zone
+			line: "[2026-10-12 03:14:27.601200] W [MSGID: 113026] [posix-entry-ops.c:1345:posix_mkdir] " +
+				"0-rep3-posix: mkdir (/dir): gfid (abc) is already associated with directory",
+			expected: Line{Severity: "warning", MsgID: "113026"},
+			ok:       true,
+		},
+		{
+			line:     "[2026-10-12 03:14:28.000001 +0000] I [rpc-clnt.c:1000:rpc_clnt_connection_init] 0-rep3-client-0: setting frame-timeout to 42",
+			expected: Line{Severity: "info"},
+			ok:       true,
+		},
+		{
+			// the continuation lines of a multi-line message
+			line: "+------------------------------------------------------------------------------+",
+		},
+		{
+			line: "[2026-10-12 03:14:27.512334]  : volume status rep3 : SUCCESS",
+		},
+	}
+	for _, tt := range tests {
+		line, ok := ParseLine(tt.line)
+		if ok != tt.ok || line != tt.expected {
+			t.Errorf("%q: expected %+v %t, got %+v %t", tt.line, tt.expected, tt.ok, line, ok)
+		}
+	}
+}
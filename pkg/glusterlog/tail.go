@@ -0,0 +1,156 @@
+package glusterlog
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// readBufferSize is the size of the reads from the log file
+	readBufferSize = 64 * 1024
+	// maxLineSize is the max size of a line, the longer
+	// lines are split
+	maxLineSize = 64 * 1024
+)
+
+// Tailer follows a log file by its path like 'tail -F'. It starts at the
+// end of the file, and reads the lines appended since the last read. A
+// rotated file is read till its end before the new file is opened, and
+// a truncated file is read again from its start
+type Tailer struct {
+	path string
+	file *os.File
+	info os.FileInfo
+	// partial is the last line read, without its newline yet
+	partial []byte
+	// fromStart is set once the file at the path is known, the
+	// files created later, like on rotation, are read from the start
+	fromStart bool
+}
+
+// NewTailer returns a Tailer following the log file, from its end
+func NewTailer(path string) *Tailer {
+	return &Tailer{path: filepath.Clean(path)}
+}
+
+// NewTailerFromStart returns a Tailer following the log file from its
+// start, for the log files created after the existing ones were followed
+func NewTailerFromStart(path string) *Tailer {
+	return &Tailer{path: filepath.Clean(path), fromStart: true}
+}
+
+func (t *Tailer) open() error {
+	fromStart := t.fromStart
+	t.fromStart = true
+	f, err := os.Open(t.path)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	if !fromStart {
+		if _, err := f.Seek(0, io.SeekEnd); err != nil {
+			_ = f.Close()
+			return err
+		}
+	}
+	t.file = f
+	t.info = info
+	t.partial = nil
+	return nil
+}
+
+// Close closes the log file
+func (t *Tailer) Close() error {
+	if t.file == nil {
+		return nil
+	}
+	err := t.file.Close()
+	t.file = nil
+	t.partial = nil
+	return err
+}
+
+// readLines reads the file till its end, and calls fn for each line
+func (t *Tailer) readLines(fn func(line string)) error {
+	buf := make([]byte, readBufferSize)
+	for {
+		n, err := t.file.Read(buf)
+		data := buf[:n]
+		for len(data) > 0 {
+			idx := bytes.IndexByte(data, '\n')
+			if idx < 0 {
+				t.partial = append(t.partial, data...)
+				if len(t.partial) >= maxLineSize {
+					fn(string(t.partial))
+					t.partial = nil
+				}
+				break
+			}
+			fn(string(append(t.partial, data[:idx]...)))
+			t.partial = nil
+			data = data[idx+1:]
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// ReadLines calls fn for each line appended to the log file since the
+// last call. A missing file is not an error, it is read once created
+func (t *Tailer) ReadLines(fn func(line string)) error {
+	if t.file == nil {
+		if err := t.open(); err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+	}
+	// the rest of the current file, even if it was rotated
+	if err := t.readLines(fn); err != nil {
+		return err
+	}
+
+	info, err := os.Stat(t.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// rotated, till the new file is created
+			return nil
+		}
+		return err
+	}
+	if !os.SameFile(info, t.info) {
+		_ = t.Close()
+		if err := t.open(); err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		return t.readLines(fn)
+	}
+
+	offset, err := t.file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if info.Size() < offset {
+		// truncated, like by the 'copytruncate' of logrotate
+		if _, err := t.file.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		t.partial = nil
+		return t.readLines(fn)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package glusterlog
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func appendFile(t *testing.T, path string, data string) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	if _, err := f.WriteString(data); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readLines(t *testing.T, tailer *Tailer) []string {
+	var lines []string
+	if err := tailer.ReadLines(func(line string) {
+		lines = append(lines, line)
+	}); err != nil {
+		t.Fatalf("ReadLines failed: %s", err)
+	}
+	return lines
+}
+
+func TestTailer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "glusterlog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+	path := filepath.Join(dir, "glusterd.log")
+	appendFile(t, path, "old 1\nold 2\n")
+
+	tailer := NewTailer(path)
+	defer func() {
+		_ = tailer.Close()
+	}()
+	steps := []struct {
+		name     string
+		change   func()
+		expected []string
+	}{
+		{
+			// starts at the end of the existing file
+			name:   "start",
+			change: func() {},
+		},
+		{
+			name:     "append",
+			change:   func() { appendFile(t, path, "line 1\nline 2\npartial") },
+			expected: []string{"line 1", "line 2"},
+		},
+		{
+			name:     "complete the partial line",
+			change:   func() { appendFile(t, path, " line\n") },
+			expected: []string{"partial line"},
+		},
+		{
+			// the rest of the rotated file is read before the new file
+			name: "rotate",
+			change: func() {
+				appendFile(t, path, "before rotate\n")
+				if err := os.Rename(path, path+"-20261012"); err != nil {
+					t.Fatal(err)
+				}
+				appendFile(t, path, "after rotate\n")
+			},
+			expected: []string{"before rotate", "after rotate"},
+		},
+		{
+			name: "rotated, not created yet",
+			change: func() {
+				if err := os.Rename(path, path+"-20261013"); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+		{
+			name:     "created",
+			change:   func() { appendFile(t, path, "new 1\n") },
+			expected: []string{"new 1"},
+		},
+		{
+			name: "truncate",
+			change: func() {
+				if err := os.Truncate(path, 0); err != nil {
+					t.Fatal(err)
+				}
+				appendFile(t, path, "t\n")
+			},
+			expected: []string{"t"},
+		},
+	}
+	for _, step := range steps {
+		step.change()
+		if lines := readLines(t, tailer); !reflect.DeepEqual(lines, step.expected) {
+			t.Errorf("%s: expected %q, got %q", step.name, step.expected, lines)
+		}
+	}
+}
+
+func TestTailerMissingFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "glusterlog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+	path := filepath.Join(dir, "glustershd.log")
+
+	tailer := NewTailer(path)
+	defer func() {
+		_ = tailer.Close()
+	}()
+	if lines := readLines(t, tailer); len(lines) != 0 {
+		t.Errorf("expected no lines, got %q", lines)
+	}
+	// a file created later is read from its start
+	appendFile(t, path, "first\n")
+	if lines := readLines(t, tailer); !reflect.DeepEqual(lines, []string{"first"}) {
+		t.Errorf("expected %q, got %q", []string{"first"}, lines)
+	}
+
+	fromStart := NewTailerFromStart(path)
+	defer func() {
+		_ = fromStart.Close()
+	}()
+	if lines := readLines(t, fromStart); !reflect.DeepEqual(lines, []string{"first"}) {
+		t.Errorf("expected %q, got %q", []string{"first"}, lines)
+	}
+}
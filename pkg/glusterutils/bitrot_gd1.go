@@ -0,0 +1,134 @@
+package glusterutils
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// scrubTimeLayout is the layout of the last completed scrub
+	// time, the scrubber reports it in UTC
+	scrubTimeLayout = "2006-01-02 15:04:05"
+	// scrubNodeSeparator separates the nodes in the scrub status
+	scrubNodeSeparator = "====="
+)
+
+// scrubGFIDRE matches the corrupted objects, listed by their GFID and
+// followed by the brick and the path in the recent gluster versions
+var scrubGFIDRE = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+
+// parseScrubDuration returns the duration of the last scrub in
+// seconds, reported as days:hours:minutes:seconds
+func parseScrubDuration(value string) int64 {
+	multipliers := []int64{1, 60, 60 * 60, 24 * 60 * 60}
+	parts := strings.Split(strings.TrimSpace(value), ":")
+	if len(parts) > len(multipliers) {
+		return 0
+	}
+	var secs int64
+	for idx := range parts {
+		part, err := strconv.ParseInt(parts[len(parts)-1-idx], 10, 64)
+		if err != nil {
+			return 0
+		}
+		secs += part * multipliers[idx]
+	}
+	return secs
+}
+
+// parseScrubTime returns the unix time of the last completed
+// scrub, 0 if no scrub completed yet
+func parseScrubTime(value string) int64 {
+	t, err := time.ParseInLocation(scrubTimeLayout, strings.TrimSpace(value), time.UTC)
+	if err != nil {
+		return 0
+	}
+	return t.Unix()
+}
+
+// parseScrubCount returns the value of a scrub status counter
+func parseScrubCount(value string) int64 {
+	count, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return count
+}
+
+// parseScrubStatus parses the plain scrub status, a list of
+// '<field>: <value>' lines, with a section for each node
+func parseScrubStatus(out []byte) BitrotScrubStatus {
+	var status BitrotScrubStatus
+	var node *BitrotScrubNodeStatus
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, scrubNodeSeparator) {
+			if node != nil {
+				status.Nodes = append(status.Nodes, *node)
+				node = nil
+			}
+			continue
+		}
+		if node != nil && scrubGFIDRE.MatchString(line) {
+			node.CorruptedObjects = append(node.CorruptedObjects, scrubGFIDRE.FindString(line))
+			continue
+		}
+		// the field names can contain a colon, like
+		// 'Duration of last scrub (D:M:H:M:S): 0:0:0:1'
+		parts := strings.SplitN(line, ": ", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		field, value := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		switch field {
+		case "Volume name":
+			status.Volume = value
+		case "State of scrub":
+			status.State = value
+		case "Scrub impact":
+			status.Throttle = value
+		case "Scrub frequency":
+			status.Frequency = value
+		case "Node":
+			node = &BitrotScrubNodeStatus{Hostname: value}
+		}
+		if node == nil {
+			continue
+		}
+		switch field {
+		case "Number of Scrubbed files":
+			node.ScrubbedFiles = parseScrubCount(value)
+		case "Number of Skipped files":
+			node.SkippedFiles = parseScrubCount(value)
+		case "Last completed scrub time":
+			node.LastScrubTime = parseScrubTime(value)
+		case "Duration of last scrub (D:M:H:M:S)":
+			node.LastScrubDuration = parseScrubDuration(value)
+		case "Error count":
+			node.ErrorCount = parseScrubCount(value)
+		}
+	}
+	if node != nil {
+		status.Nodes = append(status.Nodes, *node)
+	}
+	return status
+}
+
+// BitrotScrubStatus returns the scrubber status of the volume (GD1).
+// The scrub status has no XML output, the plain output is parsed
+func (g *GD1) BitrotScrubStatus(ctx context.Context, vol string) (BitrotScrubStatus, error) {
+	out, err := g.execGlusterPlain(ctx, "volume", "bitrot", vol, "scrub", "status")
+	if err != nil {
+		return BitrotScrubStatus{}, err
+	}
+	return parseScrubStatus(out), nil
+}
@@ -0,0 +1,73 @@
+package glusterutils
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGD1BitrotScrubStatus(t *testing.T) {
+	utcTime := func(value string) int64 {
+		ts, err := time.Parse(scrubTimeLayout, value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ts.Unix()
+	}
+	expected := BitrotScrubStatus{
+		Volume:    "rep3",
+		State:     "Active (Idle)",
+		Throttle:  "lazy",
+		Frequency: "biweekly",
+		Nodes: []BitrotScrubNodeStatus{
+			{
+				Hostname:          "localhost",
+				ScrubbedFiles:     15320,
+				SkippedFiles:      12,
+				LastScrubTime:     utcTime("2026-10-12 03:14:27"),
+				LastScrubDuration: 1*60*60 + 25*60 + 7,
+				ErrorCount:        2,
+				CorruptedObjects: []string{"5b4e2d1c-3a9f-4e8b-9c7d-6f5e4d3c2b1a",
+					"c1d2e3f4-a5b6-4c7d-8e9f-0a1b2c3d4e5f"},
+			},
+			{
+				Hostname:          "server2",
+				ScrubbedFiles:     15318,
+				LastScrubTime:     utcTime("2026-10-12 03:10:02"),
+				LastScrubDuration: 1*60*60 + 20*60 + 42,
+			},
+			{
+				// no scrub completed yet
+				Hostname: "server3",
+			},
+		},
+	}
+
+	g, cleanup := newFakeGD1("bitrot")
+	defer cleanup()
+	status, err := g.BitrotScrubStatus(context.Background(), "rep3")
+	if err != nil {
+		t.Fatalf("BitrotScrubStatus failed: %s", err)
+	}
+	if !reflect.DeepEqual(status, expected) {
+		t.Errorf("expected %+v, got %+v", expected, status)
+	}
+}
+
+func TestParseScrubDuration(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected int64
+	}{
+		{value: "0:0:0:1", expected: 1},
+		{value: "2:3:4:5", expected: 2*24*60*60 + 3*60*60 + 4*60 + 5},
+		{value: "3725", expected: 3725},
+		{value: "N/A", expected: 0},
+	}
+	for _, tt := range tests {
+		if duration := parseScrubDuration(tt.value); duration != tt.expected {
+			t.Errorf("%q: expected %d, got %d", tt.value, tt.expected, duration)
+		}
+	}
+}
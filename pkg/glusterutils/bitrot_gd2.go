@@ -0,0 +1,41 @@
+package glusterutils
+
+import (
+	"context"
+
+	"github.com/gluster/glusterd2/pkg/restclient"
+	bitrotapi "github.com/gluster/glusterd2/plugins/bitrot/api"
+)
+
+// BitrotScrubStatus returns the scrubber status of the volume (GD2)
+func (g *GD2) BitrotScrubStatus(ctx context.Context, vol string) (BitrotScrubStatus, error) {
+	var scrubStatus bitrotapi.ScrubStatus
+	err := g.pool.call(ctx, "BitrotScrubStatus", func(client *restclient.Client) (err error) {
+		scrubStatus, err = client.BitrotScrubStatus(vol)
+		return err
+	})
+	if err != nil {
+		return BitrotScrubStatus{}, err
+	}
+	status := BitrotScrubStatus{
+		Volume:    scrubStatus.Volume,
+		State:     scrubStatus.State,
+		Throttle:  scrubStatus.Throttle,
+		Frequency: scrubStatus.Frequency,
+		Nodes:     make([]BitrotScrubNodeStatus, len(scrubStatus.Nodes)),
+	}
+	for idx, node := range scrubStatus.Nodes {
+		// the counters are reported as strings, the
+		// duration of the last scrub in seconds
+		status.Nodes[idx] = BitrotScrubNodeStatus{
+			Hostname:          node.Node,
+			ScrubbedFiles:     parseScrubCount(node.NumScrubbedFiles),
+			SkippedFiles:      parseScrubCount(node.NumSkippedFiles),
+			LastScrubTime:     parseScrubTime(node.LastScrubCompletedTime),
+			LastScrubDuration: parseScrubDuration(node.LastScrubDuration),
+			ErrorCount:        parseScrubCount(node.ErrorCount),
+			CorruptedObjects:  node.CorruptedObjects,
+		}
+	}
+	return status, nil
+}
@@ -1,13 +1,14 @@
 package glusterutils
 
 import (
+	"context"
 	"encoding/xml"
 )
 
 // VolumeBrickStatus gets brick status info from glusterd2 using rest api
-func (g GD1) VolumeBrickStatus(vol string) ([]BrickStatus, error) {
+func (g GD1) VolumeBrickStatus(ctx context.Context, vol string) ([]BrickStatus, error) {
 	// Run gluster volume status {vol}
-	out, err := g.execGluster("volume", "status", vol, "detail")
+	out, err := g.execGluster(ctx, "volume", "status", vol, "detail")
 	if err != nil {
 		return nil, err
 	}
@@ -1,12 +1,19 @@
 package glusterutils
 
+import (
+	"context"
+
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/restclient"
+)
+
 // VolumeBrickStatus gets brick status info from glusterd2 using rest api
-func (g GD2) VolumeBrickStatus(vol string) ([]BrickStatus, error) {
-	client, err := initRESTClient(g.config)
-	if err != nil {
-		return nil, err
-	}
-	brickstatusinfo, err := client.BricksStatus(vol)
+func (g GD2) VolumeBrickStatus(ctx context.Context, vol string) ([]BrickStatus, error) {
+	var brickstatusinfo api.BricksStatusResp
+	err := g.pool.call(ctx, "BricksStatus", func(client *restclient.Client) (err error) {
+		brickstatusinfo, err = client.BricksStatus(vol)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,118 @@
+package glusterutils
+
+import (
+	"context"
+	"encoding/xml"
+)
+
+// volumeStatusDetail runs the volume status of the given detail mode,
+// like 'mem' or 'fd', and unmarshals its XML output
+func (g *GD1) volumeStatusDetail(ctx context.Context, vol string, mode string, v interface{}) error {
+	// Run gluster volume status {vol} {mode} --xml
+	out, err := g.execGluster(ctx, "volume", "status", vol, mode)
+	if err != nil {
+		return err
+	}
+	return xml.Unmarshal(out, v)
+}
+
+// VolumeMemStatus returns the memory usage of each brick process of
+// the volume (glusterd)
+func (g *GD1) VolumeMemStatus(ctx context.Context, vol string) ([]BrickMemStatus, error) {
+	var memStatus gd1VolumeMemStatus
+	if err := g.volumeStatusDetail(ctx, vol, "mem", &memStatus); err != nil {
+		return nil, err
+	}
+
+	bricks := make([]BrickMemStatus, len(memStatus.Bricks))
+	for idx, brick := range memStatus.Bricks {
+		bricks[idx] = BrickMemStatus{
+			Hostname: brick.Hostname,
+			PeerID:   brick.PeerID,
+			Path:     brick.Path,
+			Volume:   vol,
+			Status:   brick.Status,
+			Mallinfo: Mallinfo(brick.Mallinfo),
+		}
+		for _, pool := range brick.Mempools {
+			bricks[idx].Mempools = append(bricks[idx].Mempools, Mempool(pool))
+		}
+	}
+	return bricks, nil
+}
+
+// VolumeInodeStatus returns the inode table sizes of each brick
+// process of the volume (glusterd)
+func (g *GD1) VolumeInodeStatus(ctx context.Context, vol string) ([]BrickInodeStatus, error) {
+	var inodeStatus gd1VolumeInodeStatus
+	if err := g.volumeStatusDetail(ctx, vol, "inode", &inodeStatus); err != nil {
+		return nil, err
+	}
+
+	bricks := make([]BrickInodeStatus, len(inodeStatus.Bricks))
+	for idx, brick := range inodeStatus.Bricks {
+		bricks[idx] = BrickInodeStatus{
+			Hostname: brick.Hostname,
+			PeerID:   brick.PeerID,
+			Path:     brick.Path,
+			Volume:   vol,
+			Status:   brick.Status,
+		}
+		for _, table := range brick.InodeTables {
+			bricks[idx].ActiveSize += table.ActiveSize
+			bricks[idx].LRUSize += table.LRUSize
+			bricks[idx].PurgeSize += table.PurgeSize
+		}
+	}
+	return bricks, nil
+}
+
+// VolumeFdStatus returns the open fds of each brick process of the
+// volume (glusterd)
+func (g *GD1) VolumeFdStatus(ctx context.Context, vol string) ([]BrickFdStatus, error) {
+	var fdStatus gd1VolumeFdStatus
+	if err := g.volumeStatusDetail(ctx, vol, "fd", &fdStatus); err != nil {
+		return nil, err
+	}
+
+	bricks := make([]BrickFdStatus, len(fdStatus.Bricks))
+	for idx, brick := range fdStatus.Bricks {
+		bricks[idx] = BrickFdStatus{
+			Hostname:    brick.Hostname,
+			PeerID:      brick.PeerID,
+			Path:        brick.Path,
+			Volume:      vol,
+			Status:      brick.Status,
+			Connections: len(brick.Connections),
+		}
+		for _, conn := range brick.Connections {
+			bricks[idx].OpenFds += int64(len(conn.Fds))
+		}
+	}
+	return bricks, nil
+}
+
+// VolumeCallpoolStatus returns the pending calls of each brick process
+// of the volume (glusterd)
+func (g *GD1) VolumeCallpoolStatus(ctx context.Context, vol string) ([]BrickCallpoolStatus, error) {
+	var callpoolStatus gd1VolumeCallpoolStatus
+	if err := g.volumeStatusDetail(ctx, vol, "callpool", &callpoolStatus); err != nil {
+		return nil, err
+	}
+
+	bricks := make([]BrickCallpoolStatus, len(callpoolStatus.Bricks))
+	for idx, brick := range callpoolStatus.Bricks {
+		bricks[idx] = BrickCallpoolStatus{
+			Hostname:   brick.Hostname,
+			PeerID:     brick.PeerID,
+			Path:       brick.Path,
+			Volume:     vol,
+			Status:     brick.Status,
+			CallStacks: len(brick.CallStacks),
+		}
+		for _, stack := range brick.CallStacks {
+			bricks[idx].CallFrames += stack.FrameCount
+		}
+	}
+	return bricks, nil
+}
@@ -0,0 +1,83 @@
+package glusterutils
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestGD1VolumeBrickStats(t *testing.T) {
+	g, cleanup := newFakeGD1("brick-stats")
+	defer cleanup()
+	ctx := context.Background()
+
+	memStatus, err := g.VolumeMemStatus(ctx, "rep3")
+	if err != nil {
+		t.Fatalf("VolumeMemStatus failed: %s", err)
+	}
+	expectedMem := []BrickMemStatus{
+		{
+			Hostname: "server1", PeerID: peerID1, Path: "/bricks/rep3", Volume: "rep3", Status: 1,
+			Mallinfo: Mallinfo{Arena: 4382720, Ordblks: 412, Smblks: 87, Hblks: 18, Hblkhd: 17432576,
+				Fsmblks: 6480, Uordblks: 3862544, Fordblks: 520176, Keepcost: 123456},
+			Mempools: []Mempool{
+				{Name: "rep3-server:fd_t", HotCount: 3, ColdCount: 1021, PaddedSize: 108, AllocCount: 19, MaxAlloc: 12},
+				{Name: "rep3-server:inode_t", HotCount: 214, ColdCount: 32554, PaddedSize: 156, AllocCount: 2531, MaxAlloc: 240},
+			},
+		},
+		{
+			Hostname: "server2", PeerID: peerID2, Path: "/bricks/rep3", Volume: "rep3", Status: 1,
+			Mallinfo: Mallinfo{Arena: 4251648, Ordblks: 412, Smblks: 87, Hblks: 18, Hblkhd: 17432576,
+				Fsmblks: 6480, Uordblks: 3712000, Fordblks: 539648, Keepcost: 123456},
+			Mempools: []Mempool{
+				{Name: "rep3-server:fd_t", ColdCount: 1024, PaddedSize: 108, AllocCount: 7, MaxAlloc: 4},
+				{Name: "rep3-server:inode_t", HotCount: 198, ColdCount: 32570, PaddedSize: 156, AllocCount: 2410, MaxAlloc: 231, PoolMisses: 2},
+			},
+		},
+		// the details of an offline brick are not listed
+		{Hostname: "server3", PeerID: peerID3, Path: "/bricks/rep3", Volume: "rep3"},
+	}
+	if !reflect.DeepEqual(memStatus, expectedMem) {
+		t.Errorf("expected %+v, got %+v", expectedMem, memStatus)
+	}
+
+	inodeStatus, err := g.VolumeInodeStatus(ctx, "rep3")
+	if err != nil {
+		t.Fatalf("VolumeInodeStatus failed: %s", err)
+	}
+	expectedInode := []BrickInodeStatus{
+		{Hostname: "server1", PeerID: peerID1, Path: "/bricks/rep3", Volume: "rep3", Status: 1, ActiveSize: 214, LRUSize: 16384},
+		// the inode tables of all the connections are summed
+		{Hostname: "server2", PeerID: peerID2, Path: "/bricks/rep3", Volume: "rep3", Status: 1, ActiveSize: 200, LRUSize: 15872, PurgeSize: 1},
+		{Hostname: "server3", PeerID: peerID3, Path: "/bricks/rep3", Volume: "rep3"},
+	}
+	if !reflect.DeepEqual(inodeStatus, expectedInode) {
+		t.Errorf("expected %+v, got %+v", expectedInode, inodeStatus)
+	}
+
+	fdStatus, err := g.VolumeFdStatus(ctx, "rep3")
+	if err != nil {
+		t.Fatalf("VolumeFdStatus failed: %s", err)
+	}
+	expectedFd := []BrickFdStatus{
+		{Hostname: "server1", PeerID: peerID1, Path: "/bricks/rep3", Volume: "rep3", Status: 1, Connections: 3, OpenFds: 3},
+		{Hostname: "server2", PeerID: peerID2, Path: "/bricks/rep3", Volume: "rep3", Status: 1, Connections: 1, OpenFds: 1},
+		{Hostname: "server3", PeerID: peerID3, Path: "/bricks/rep3", Volume: "rep3"},
+	}
+	if !reflect.DeepEqual(fdStatus, expectedFd) {
+		t.Errorf("expected %+v, got %+v", expectedFd, fdStatus)
+	}
+
+	callpoolStatus, err := g.VolumeCallpoolStatus(ctx, "rep3")
+	if err != nil {
+		t.Fatalf("VolumeCallpoolStatus failed: %s", err)
+	}
+	expectedCallpool := []BrickCallpoolStatus{
+		{Hostname: "server1", PeerID: peerID1, Path: "/bricks/rep3", Volume: "rep3", Status: 1, CallStacks: 2, CallFrames: 5},
+		{Hostname: "server2", PeerID: peerID2, Path: "/bricks/rep3", Volume: "rep3", Status: 1},
+		{Hostname: "server3", PeerID: peerID3, Path: "/bricks/rep3", Volume: "rep3"},
+	}
+	if !reflect.DeepEqual(callpoolStatus, expectedCallpool) {
+		t.Errorf("expected %+v, got %+v", expectedCallpool, callpoolStatus)
+	}
+}
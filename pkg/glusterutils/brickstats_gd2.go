@@ -0,0 +1,32 @@
+package glusterutils
+
+import (
+	"context"
+)
+
+// The glusterd2 REST client has no API for the detailed brick process
+// status yet, like the memory, inode, fd and callpool details
+
+// VolumeMemStatus returns the memory usage of each brick process of
+// the volume (GD2)
+func (g *GD2) VolumeMemStatus(ctx context.Context, vol string) ([]BrickMemStatus, error) {
+	return nil, ErrNotSupported
+}
+
+// VolumeInodeStatus returns the inode table sizes of each brick
+// process of the volume (GD2)
+func (g *GD2) VolumeInodeStatus(ctx context.Context, vol string) ([]BrickInodeStatus, error) {
+	return nil, ErrNotSupported
+}
+
+// VolumeFdStatus returns the open fds of each brick process of the
+// volume (GD2)
+func (g *GD2) VolumeFdStatus(ctx context.Context, vol string) ([]BrickFdStatus, error) {
+	return nil, ErrNotSupported
+}
+
+// VolumeCallpoolStatus returns the pending calls of each brick process
+// of the volume (GD2)
+func (g *GD2) VolumeCallpoolStatus(ctx context.Context, vol string) ([]BrickCallpoolStatus, error) {
+	return nil, ErrNotSupported
+}
@@ -1,6 +1,7 @@
 package glusterutils
 
 import (
+	"context"
 	"errors"
 	"sync"
 	"time"
@@ -8,14 +9,31 @@ import (
 	"github.com/gluster/gluster-prometheus/gluster-exporter/conf"
 )
 
+// CacheableFuncs are the names of the functions which
+// can be cached, the values of 'cache-enabled-funcs'
+var CacheableFuncs = []string{
+	"EnableVolumeProfiling", "HealInfo", "SplitBrainHealInfo", "HealInfoSummary",
+	"IsLeader", "LocalPeerID", "Peers", "Snapshots", "VolumeBrickStatus",
+	"VolumeInfo", "VolumeProfileStatus", "VolumeProfileInfo", "GeoRepStatus",
+	"RebalanceStatus", "QuotaList", "BitrotScrubStatus", "VolumeClients",
+	"VolumeMemStatus", "VolumeInodeStatus", "VolumeFdStatus", "VolumeCallpoolStatus",
+}
+
+// errCacheType is returned if a cached value is not of the type
+// returned by the function
+var errCacheType = errors.New("[CacheError] Unable to convert back to a valid return type")
+
 // GCache is a wrapper around 'GInterface' object
 type GCache struct {
-	gd                GInterface
-	ttl               time.Duration
+	gd  GInterface
+	ttl time.Duration
+	// lock protects the maps and the settings, it is never held
+	// during a call, the calls are serialized by 'keyLocks'
 	lock              sync.Mutex
 	lastCallValueMap  map[string]interface{}
 	lastCallTimeMap   map[string]time.Time
 	cacheEnabledFuncs map[string]struct{}
+	keyLocks          map[string]chan struct{}
 }
 
 // NewGCacheWithTTL method creates a new GCache wrapper instance.
@@ -24,9 +42,10 @@ func NewGCacheWithTTL(gd GInterface, ttl time.Duration) *GCache {
 	var gc = new(GCache)
 	gc.gd = gd
 	gc.ttl = 1 * time.Minute // default to 1 minute
-	gc.SetTTL(ttl)
+	gc.setTTL(ttl)
 	gc.lastCallValueMap = make(map[string]interface{})
 	gc.lastCallTimeMap = make(map[string]time.Time)
+	gc.keyLocks = make(map[string]chan struct{})
 	// functions for which caching have to be enabled
 	// are added to the below map
 	gc.cacheEnabledFuncs = make(map[string]struct{})
@@ -41,11 +60,19 @@ func NewGCache(gd GInterface) *GCache {
 
 // TTL method returns the current time_to_live duration
 func (gc *GCache) TTL() time.Duration {
+	gc.lock.Lock()
+	defer gc.lock.Unlock()
 	return gc.ttl
 }
 
 // SetTTL method sets a new time_to_live
 func (gc *GCache) SetTTL(ttl time.Duration) {
+	gc.lock.Lock()
+	defer gc.lock.Unlock()
+	gc.setTTL(ttl)
+}
+
+func (gc *GCache) setTTL(ttl time.Duration) {
 	// accepts 0 or durations in Seconds
 	if ttl == time.Duration(0) || ttl >= time.Second {
 		gc.ttl = ttl
@@ -56,6 +83,20 @@ func (gc *GCache) SetTTL(ttl time.Duration) {
 // for the given list of functions.
 // If the provided function is not there in the existing list, it will be ignored
 func (gc *GCache) EnableCacheForFuncs(fNames []string) {
+	gc.lock.Lock()
+	defer gc.lock.Unlock()
+	for _, fName := range fNames {
+		gc.cacheEnabledFuncs[fName] = struct{}{}
+	}
+}
+
+// Reconfigure method replaces the time_to_live and the list of
+// functions with caching enabled, like on a reload of the configuration
+func (gc *GCache) Reconfigure(ttl time.Duration, fNames []string) {
+	gc.lock.Lock()
+	defer gc.lock.Unlock()
+	gc.setTTL(ttl)
+	gc.cacheEnabledFuncs = make(map[string]struct{})
 	for _, fName := range fNames {
 		gc.cacheEnabledFuncs[fName] = struct{}{}
 	}
@@ -82,256 +123,339 @@ func (gc *GCache) timeForNewCall(funcName string, origFuncName string) (ret bool
 	return false
 }
 
-// EnableVolumeProfiling method wraps the GInterface.EnableVolumeProfiling call
-func (gc *GCache) EnableVolumeProfiling(vInfo Volume) error {
+// keyLock returns the lock of the cached call, a channel
+// so that the wait for the lock can be bounded by a context
+func (gc *GCache) keyLock(localName string) chan struct{} {
 	gc.lock.Lock()
 	defer gc.lock.Unlock()
-	const origName = "EnableVolumeProfiling"
-	// caching the result for each volume
-	var localName = origName + "-" + vInfo.ID + "-" + vInfo.Name
-	var retVal error
-	if gc.timeForNewCall(localName, origName) {
-		if retVal = gc.gd.EnableVolumeProfiling(vInfo); retVal != nil {
-			return retVal
-		}
-		// reset the last called time only on a successful call
-		gc.lastCallTimeMap[localName] = time.Now()
-		gc.lastCallValueMap[localName] = retVal
+	keyLock, ok := gc.keyLocks[localName]
+	if !ok {
+		keyLock = make(chan struct{}, 1)
+		gc.keyLocks[localName] = keyLock
 	}
-	return retVal
+	return keyLock
 }
 
-// HealInfo method wraps the GInterface.HealInfo call
-func (gc *GCache) HealInfo(vol string) ([]HealEntry, error) {
+// cached returns the value of the last call 'localName' of the function
+// 'origName' while within the TTL, or makes the call. The calls with the
+// same 'localName' are run one at a time, so that the waiting calls use
+// the value of the call in progress, but the calls with other names are
+// not blocked by it. The wait for the call in progress ends with the
+// context
+func (gc *GCache) cached(ctx context.Context, localName string, origName string,
+	call func() (interface{}, error)) (interface{}, error) {
 	gc.lock.Lock()
-	defer gc.lock.Unlock()
+	_, enabled := gc.cacheEnabledFuncs[origName]
+	gc.lock.Unlock()
+	// if the caching is not enabled for this function, always call
+	if !enabled {
+		return call()
+	}
+
+	keyLock := gc.keyLock(localName)
+	select {
+	case keyLock <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() { <-keyLock }()
+
+	gc.lock.Lock()
+	newCall := gc.timeForNewCall(localName, origName)
+	value := gc.lastCallValueMap[localName]
+	gc.lock.Unlock()
+	if !newCall {
+		return value, nil
+	}
+	value, err := call()
+	if err != nil {
+		return value, err
+	}
+	// reset the last called time only on a successful call
+	gc.lock.Lock()
+	gc.lastCallTimeMap[localName] = time.Now()
+	gc.lastCallValueMap[localName] = value
+	gc.lock.Unlock()
+	return value, nil
+}
+
+// EnableVolumeProfiling method wraps the GInterface.EnableVolumeProfiling call
+func (gc *GCache) EnableVolumeProfiling(ctx context.Context, vInfo Volume) error {
+	// caching the result for each volume
+	const origName = "EnableVolumeProfiling"
+	_, err := gc.cached(ctx, origName+"-"+vInfo.ID+"-"+vInfo.Name, origName, func() (interface{}, error) {
+		return nil, gc.gd.EnableVolumeProfiling(ctx, vInfo)
+	})
+	return err
+}
+
+// HealInfo method wraps the GInterface.HealInfo call
+func (gc *GCache) HealInfo(ctx context.Context, vol string) ([]HealEntry, error) {
 	// adding the argument[s] also to the 'localName'
 	// as we want to cache the function call with each argument
 	// it will be wrong to cache the results for only one volume
 	// and show the same result throughout for other volumes
 	const origName = "HealInfo"
-	var localName = origName + "-" + vol
-	var retVal []HealEntry
-	var err error
-	var ok bool
-	if gc.timeForNewCall(localName, origName) {
-		if retVal, err = gc.gd.HealInfo(vol); err != nil {
-			return retVal, err
-		}
-		// reset the last called time only on a successful call
-		gc.lastCallTimeMap[localName] = time.Now()
-		gc.lastCallValueMap[localName] = retVal
-	}
-	if retVal, ok = gc.lastCallValueMap[localName].([]HealEntry); !ok {
-		err = errors.New("[CacheError] Unable to convert back to a valid return type")
+	value, err := gc.cached(ctx, origName+"-"+vol, origName, func() (interface{}, error) {
+		return gc.gd.HealInfo(ctx, vol)
+	})
+	retVal, ok := value.([]HealEntry)
+	if err == nil && !ok {
+		err = errCacheType
 	}
 	return retVal, err
 }
 
 // SplitBrainHealInfo wraps the GInterface.SplitBrainHealInfo call
-func (gc *GCache) SplitBrainHealInfo(vol string) ([]HealEntry, error) {
-	gc.lock.Lock()
-	defer gc.lock.Unlock()
+func (gc *GCache) SplitBrainHealInfo(ctx context.Context, vol string) ([]HealEntry, error) {
 	// adding the argument[s] also to the 'localName'
 	// as we want to cache the function call with each argument
 	// it will be wrong to cache the results for only one volume
 	// and show the same result throughout for other volumes
 	const origName = "SplitBrainHealInfo"
-	var localName = origName + "-" + vol
-	var retVal []HealEntry
-	var err error
-	var ok bool
-	if gc.timeForNewCall(localName, origName) {
-		if retVal, err = gc.gd.SplitBrainHealInfo(vol); err != nil {
-			return retVal, err
-		}
-		// reset the last called time only on a successful call
-		gc.lastCallTimeMap[localName] = time.Now()
-		gc.lastCallValueMap[localName] = retVal
-	}
-	if retVal, ok = gc.lastCallValueMap[localName].([]HealEntry); !ok {
-		err = errors.New("[CacheError] Unable to convert back to a valid return type")
+	value, err := gc.cached(ctx, origName+"-"+vol, origName, func() (interface{}, error) {
+		return gc.gd.SplitBrainHealInfo(ctx, vol)
+	})
+	retVal, ok := value.([]HealEntry)
+	if err == nil && !ok {
+		err = errCacheType
+	}
+	return retVal, err
+}
+
+// HealInfoSummary method wraps the GInterface.HealInfoSummary call
+func (gc *GCache) HealInfoSummary(ctx context.Context, vol string) ([]HealSummary, error) {
+	const origName = "HealInfoSummary"
+	value, err := gc.cached(ctx, origName+"-"+vol, origName, func() (interface{}, error) {
+		return gc.gd.HealInfoSummary(ctx, vol)
+	})
+	retVal, ok := value.([]HealSummary)
+	if err == nil && !ok {
+		err = errCacheType
 	}
 	return retVal, err
 }
 
 // IsLeader method wraps the GInterface.IsLeader call
-func (gc *GCache) IsLeader() (bool, error) {
-	gc.lock.Lock()
-	defer gc.lock.Unlock()
-	const localName = "IsLeader"
-	var retVal bool
-	var err error
-	var ok bool
-	if gc.timeForNewCall(localName, localName) {
-		if retVal, err = gc.gd.IsLeader(); err != nil {
-			return retVal, err
-		}
-		// reset the last called time only on a successful call
-		gc.lastCallTimeMap[localName] = time.Now()
-		gc.lastCallValueMap[localName] = retVal
-	}
-	if retVal, ok = gc.lastCallValueMap[localName].(bool); !ok {
-		err = errors.New("[CacheError] Unable to convert back to a valid return type")
+func (gc *GCache) IsLeader(ctx context.Context) (bool, error) {
+	const origName = "IsLeader"
+	value, err := gc.cached(ctx, origName, origName, func() (interface{}, error) {
+		return gc.gd.IsLeader(ctx)
+	})
+	retVal, ok := value.(bool)
+	if err == nil && !ok {
+		err = errCacheType
 	}
 	return retVal, err
 }
 
 // LocalPeerID method wraps the GInterface.LocalPeerID call
-func (gc *GCache) LocalPeerID() (string, error) {
-	gc.lock.Lock()
-	defer gc.lock.Unlock()
-	const localName = "LocalPeerID"
-	var retVal string
-	var err error
-	var ok bool
-	if gc.timeForNewCall(localName, localName) {
-		if retVal, err = gc.gd.LocalPeerID(); err != nil {
-			return retVal, err
-		}
-		// reset the last called time only on a successful call
-		gc.lastCallTimeMap[localName] = time.Now()
-		gc.lastCallValueMap[localName] = retVal
-	}
-	if retVal, ok = gc.lastCallValueMap[localName].(string); !ok {
-		err = errors.New("[CacheError] Unable to convert back to a valid return type")
+func (gc *GCache) LocalPeerID(ctx context.Context) (string, error) {
+	const origName = "LocalPeerID"
+	value, err := gc.cached(ctx, origName, origName, func() (interface{}, error) {
+		return gc.gd.LocalPeerID(ctx)
+	})
+	retVal, ok := value.(string)
+	if err == nil && !ok {
+		err = errCacheType
 	}
 	return retVal, err
 }
 
 // Peers method wraps the GInterface.Peers call
-func (gc *GCache) Peers() ([]Peer, error) {
-	gc.lock.Lock()
-	defer gc.lock.Unlock()
-	const localName = "Peers"
-	var retVal []Peer
-	var err error
-	var ok bool
-	if gc.timeForNewCall(localName, localName) {
-		if retVal, err = gc.gd.Peers(); err != nil {
-			return retVal, err
-		}
-		// reset the last called time only on a successful call
-		gc.lastCallTimeMap[localName] = time.Now()
-		gc.lastCallValueMap[localName] = retVal
-	}
-	if retVal, ok = gc.lastCallValueMap[localName].([]Peer); !ok {
-		err = errors.New("[CacheError] Unable to convert back to a valid return type")
+func (gc *GCache) Peers(ctx context.Context) ([]Peer, error) {
+	const origName = "Peers"
+	value, err := gc.cached(ctx, origName, origName, func() (interface{}, error) {
+		return gc.gd.Peers(ctx)
+	})
+	retVal, ok := value.([]Peer)
+	if err == nil && !ok {
+		err = errCacheType
 	}
 	return retVal, err
 }
 
 // Snapshots method wraps the GInterface.Snapshots call
-func (gc *GCache) Snapshots() ([]Snapshot, error) {
-	gc.lock.Lock()
-	defer gc.lock.Unlock()
-	const localName = "Snapshots"
-	var retVal []Snapshot
-	var err error
-	var ok bool
-	if gc.timeForNewCall(localName, localName) {
-		if retVal, err = gc.gd.Snapshots(); err != nil {
-			return retVal, err
-		}
-		// reset the last called time only on a successful call
-		gc.lastCallTimeMap[localName] = time.Now()
-		gc.lastCallValueMap[localName] = retVal
-	}
-	if retVal, ok = gc.lastCallValueMap[localName].([]Snapshot); !ok {
-		err = errors.New("[CacheError] Unable to convert back to a valid return type")
+func (gc *GCache) Snapshots(ctx context.Context) ([]Snapshot, error) {
+	const origName = "Snapshots"
+	value, err := gc.cached(ctx, origName, origName, func() (interface{}, error) {
+		return gc.gd.Snapshots(ctx)
+	})
+	retVal, ok := value.([]Snapshot)
+	if err == nil && !ok {
+		err = errCacheType
 	}
 	return retVal, err
 }
 
 // VolumeBrickStatus method wraps the GInterface.VolumeBrickStatus call
-func (gc *GCache) VolumeBrickStatus(vol string) ([]BrickStatus, error) {
-	gc.lock.Lock()
-	defer gc.lock.Unlock()
+func (gc *GCache) VolumeBrickStatus(ctx context.Context, vol string) ([]BrickStatus, error) {
 	// caching the results for each volume
 	const origName = "VolumeBrickStatus"
-	var localName = origName + "-" + vol
-	var retVal []BrickStatus
-	var err error
-	var ok bool
-	if gc.timeForNewCall(localName, origName) {
-		if retVal, err = gc.gd.VolumeBrickStatus(vol); err != nil {
-			return retVal, err
-		}
-		// reset the last called time only on a successful call
-		gc.lastCallTimeMap[localName] = time.Now()
-		gc.lastCallValueMap[localName] = retVal
-	}
-	if retVal, ok = gc.lastCallValueMap[localName].([]BrickStatus); !ok {
-		err = errors.New("[CacheError] Unable to convert back to a valid return type")
+	value, err := gc.cached(ctx, origName+"-"+vol, origName, func() (interface{}, error) {
+		return gc.gd.VolumeBrickStatus(ctx, vol)
+	})
+	retVal, ok := value.([]BrickStatus)
+	if err == nil && !ok {
+		err = errCacheType
 	}
 	return retVal, err
 }
 
 // VolumeInfo method wraps the GInterface.VolumeInfo call
-func (gc *GCache) VolumeInfo() ([]Volume, error) {
-	gc.lock.Lock()
-	defer gc.lock.Unlock()
-	const localName = "VolumeInfo"
-	var retVal []Volume
-	var err error
-	var ok bool
-	if gc.timeForNewCall(localName, localName) {
-		if retVal, err = gc.gd.VolumeInfo(); err != nil {
-			return retVal, err
-		}
-		// reset the last called time only on a successful call
-		gc.lastCallTimeMap[localName] = time.Now()
-		gc.lastCallValueMap[localName] = retVal
-	}
-	if retVal, ok = gc.lastCallValueMap[localName].([]Volume); !ok {
-		err = errors.New("[CacheError] Unable to convert back to a valid return type")
+func (gc *GCache) VolumeInfo(ctx context.Context) ([]Volume, error) {
+	const origName = "VolumeInfo"
+	value, err := gc.cached(ctx, origName, origName, func() (interface{}, error) {
+		return gc.gd.VolumeInfo(ctx)
+	})
+	retVal, ok := value.([]Volume)
+	if err == nil && !ok {
+		err = errCacheType
 	}
 	return retVal, err
 }
 
 // VolumeStatus method wraps the GInterface.VolumeStatus call
-func (gc *GCache) VolumeStatus() ([]VolumeStatus, error) {
-	gc.lock.Lock()
-	defer gc.lock.Unlock()
+func (gc *GCache) VolumeStatus(ctx context.Context) ([]VolumeStatus, error) {
 	// caching the results for each volume
-	const localName = "VolumeProfileStatus"
-	var retVal []VolumeStatus
-	var err error
-	var ok bool
-	if gc.timeForNewCall(localName, localName) {
-		if retVal, err = gc.gd.VolumeStatus(); err != nil {
-			return retVal, err
-		}
-		// reset the last called time only on a successful call
-		gc.lastCallTimeMap[localName] = time.Now()
-		gc.lastCallValueMap[localName] = retVal
-	}
-	if retVal, ok = gc.lastCallValueMap[localName].([]VolumeStatus); !ok {
-		err = errors.New("[CacheError] Unable to convert back to a valid return type")
+	const origName = "VolumeProfileStatus"
+	value, err := gc.cached(ctx, origName, origName, func() (interface{}, error) {
+		return gc.gd.VolumeStatus(ctx)
+	})
+	retVal, ok := value.([]VolumeStatus)
+	if err == nil && !ok {
+		err = errCacheType
 	}
 	return retVal, err
 }
 
 // VolumeProfileInfo method wraps the GInterface.VolumeProfileInfo call
-func (gc *GCache) VolumeProfileInfo(vol string) ([]ProfileInfo, error) {
-	gc.lock.Lock()
-	defer gc.lock.Unlock()
+func (gc *GCache) VolumeProfileInfo(ctx context.Context, vol string) ([]ProfileInfo, error) {
 	// caching the results for each volume
 	const origName = "VolumeProfileInfo"
-	var localName = origName + "-" + vol
-	var retVal []ProfileInfo
-	var err error
-	var ok bool
-	if gc.timeForNewCall(localName, origName) {
-		if retVal, err = gc.gd.VolumeProfileInfo(vol); err != nil {
-			return retVal, err
-		}
-		// reset the last called time only on a successful call
-		gc.lastCallTimeMap[localName] = time.Now()
-		gc.lastCallValueMap[localName] = retVal
-	}
-	if retVal, ok = gc.lastCallValueMap[localName].([]ProfileInfo); !ok {
-		err = errors.New("[CacheError] Unable to convert back to a valid return type")
+	value, err := gc.cached(ctx, origName+"-"+vol, origName, func() (interface{}, error) {
+		return gc.gd.VolumeProfileInfo(ctx, vol)
+	})
+	retVal, ok := value.([]ProfileInfo)
+	if err == nil && !ok {
+		err = errCacheType
+	}
+	return retVal, err
+}
+
+// GeoRepStatus method wraps the GInterface.GeoRepStatus call
+func (gc *GCache) GeoRepStatus(ctx context.Context) ([]GeoRepSession, error) {
+	const origName = "GeoRepStatus"
+	value, err := gc.cached(ctx, origName, origName, func() (interface{}, error) {
+		return gc.gd.GeoRepStatus(ctx)
+	})
+	retVal, ok := value.([]GeoRepSession)
+	if err == nil && !ok {
+		err = errCacheType
+	}
+	return retVal, err
+}
+
+// RebalanceStatus method wraps the GInterface.RebalanceStatus call
+func (gc *GCache) RebalanceStatus(ctx context.Context, vol string) ([]RebalanceStatus, error) {
+	const origName = "RebalanceStatus"
+	value, err := gc.cached(ctx, origName+"-"+vol, origName, func() (interface{}, error) {
+		return gc.gd.RebalanceStatus(ctx, vol)
+	})
+	retVal, ok := value.([]RebalanceStatus)
+	if err == nil && !ok {
+		err = errCacheType
+	}
+	return retVal, err
+}
+
+// QuotaList method wraps the GInterface.QuotaList call
+func (gc *GCache) QuotaList(ctx context.Context, vol string) ([]QuotaLimit, error) {
+	const origName = "QuotaList"
+	value, err := gc.cached(ctx, origName+"-"+vol, origName, func() (interface{}, error) {
+		return gc.gd.QuotaList(ctx, vol)
+	})
+	retVal, ok := value.([]QuotaLimit)
+	if err == nil && !ok {
+		err = errCacheType
+	}
+	return retVal, err
+}
+
+// BitrotScrubStatus method wraps the GInterface.BitrotScrubStatus call
+func (gc *GCache) BitrotScrubStatus(ctx context.Context, vol string) (BitrotScrubStatus, error) {
+	const origName = "BitrotScrubStatus"
+	value, err := gc.cached(ctx, origName+"-"+vol, origName, func() (interface{}, error) {
+		return gc.gd.BitrotScrubStatus(ctx, vol)
+	})
+	retVal, ok := value.(BitrotScrubStatus)
+	if err == nil && !ok {
+		err = errCacheType
+	}
+	return retVal, err
+}
+
+// VolumeClients method wraps the GInterface.VolumeClients call
+func (gc *GCache) VolumeClients(ctx context.Context, vol string) ([]BrickClients, error) {
+	const origName = "VolumeClients"
+	value, err := gc.cached(ctx, origName+"-"+vol, origName, func() (interface{}, error) {
+		return gc.gd.VolumeClients(ctx, vol)
+	})
+	retVal, ok := value.([]BrickClients)
+	if err == nil && !ok {
+		err = errCacheType
+	}
+	return retVal, err
+}
+
+// VolumeMemStatus method wraps the GInterface.VolumeMemStatus call
+func (gc *GCache) VolumeMemStatus(ctx context.Context, vol string) ([]BrickMemStatus, error) {
+	const origName = "VolumeMemStatus"
+	value, err := gc.cached(ctx, origName+"-"+vol, origName, func() (interface{}, error) {
+		return gc.gd.VolumeMemStatus(ctx, vol)
+	})
+	retVal, ok := value.([]BrickMemStatus)
+	if err == nil && !ok {
+		err = errCacheType
+	}
+	return retVal, err
+}
+
+// VolumeInodeStatus method wraps the GInterface.VolumeInodeStatus call
+func (gc *GCache) VolumeInodeStatus(ctx context.Context, vol string) ([]BrickInodeStatus, error) {
+	const origName = "VolumeInodeStatus"
+	value, err := gc.cached(ctx, origName+"-"+vol, origName, func() (interface{}, error) {
+		return gc.gd.VolumeInodeStatus(ctx, vol)
+	})
+	retVal, ok := value.([]BrickInodeStatus)
+	if err == nil && !ok {
+		err = errCacheType
+	}
+	return retVal, err
+}
+
+// VolumeFdStatus method wraps the GInterface.VolumeFdStatus call
+func (gc *GCache) VolumeFdStatus(ctx context.Context, vol string) ([]BrickFdStatus, error) {
+	const origName = "VolumeFdStatus"
+	value, err := gc.cached(ctx, origName+"-"+vol, origName, func() (interface{}, error) {
+		return gc.gd.VolumeFdStatus(ctx, vol)
+	})
+	retVal, ok := value.([]BrickFdStatus)
+	if err == nil && !ok {
+		err = errCacheType
+	}
+	return retVal, err
+}
+
+// VolumeCallpoolStatus method wraps the GInterface.VolumeCallpoolStatus call
+func (gc *GCache) VolumeCallpoolStatus(ctx context.Context, vol string) ([]BrickCallpoolStatus, error) {
+	const origName = "VolumeCallpoolStatus"
+	value, err := gc.cached(ctx, origName+"-"+vol, origName, func() (interface{}, error) {
+		return gc.gd.VolumeCallpoolStatus(ctx, vol)
+	})
+	retVal, ok := value.([]BrickCallpoolStatus)
+	if err == nil && !ok {
+		err = errCacheType
 	}
 	return retVal, err
 }
@@ -0,0 +1,163 @@
+package glusterutils
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// countingGluster counts the calls of IsLeader, the other
+// methods of GInterface are not used by the tests
+type countingGluster struct {
+	GInterface
+	calls int
+}
+
+func (g *countingGluster) IsLeader(ctx context.Context) (bool, error) {
+	g.calls++
+	return true, nil
+}
+
+func TestGCacheReconfigure(t *testing.T) {
+	gd := &countingGluster{}
+	gc := NewGCacheWithTTL(gd, time.Minute)
+	gc.EnableCacheForFuncs([]string{"IsLeader"})
+
+	for i := 0; i < 2; i++ {
+		if _, err := gc.IsLeader(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if gd.calls != 1 {
+		t.Errorf("expected the cached value to be used, got %d calls", gd.calls)
+	}
+
+	gc.Reconfigure(2*time.Minute, []string{"Peers"})
+	if gc.TTL() != 2*time.Minute {
+		t.Errorf("expected the new TTL, got %s", gc.TTL())
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := gc.IsLeader(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if gd.calls != 3 {
+		t.Errorf("expected the caching to be disabled, got %d calls", gd.calls)
+	}
+
+	// an invalid TTL is ignored, like by SetTTL
+	gc.Reconfigure(time.Millisecond, []string{"IsLeader"})
+	if gc.TTL() != 2*time.Minute {
+		t.Errorf("expected the TTL to be kept, got %s", gc.TTL())
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := gc.IsLeader(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// the value of the last call is still within the TTL
+	if gd.calls != 3 {
+		t.Errorf("expected the caching to be enabled again, got %d calls", gd.calls)
+	}
+}
+
+func TestCacheableFuncs(t *testing.T) {
+	// every cacheable function is a method of GCache, VolumeStatus
+	// is cached by the name 'VolumeProfileStatus'
+	methods := map[string]string{"VolumeProfileStatus": "VolumeStatus"}
+	gcType := reflect.TypeOf(&GCache{})
+	for _, fName := range CacheableFuncs {
+		method := fName
+		if name, ok := methods[fName]; ok {
+			method = name
+		}
+		if _, ok := gcType.MethodByName(method); !ok {
+			t.Errorf("%s is not a method of GCache", fName)
+		}
+	}
+}
+
+// blockingGluster blocks Peers till released, and counts the calls
+type blockingGluster struct {
+	GInterface
+	started chan struct{}
+	release chan struct{}
+	lock    sync.Mutex
+	calls   map[string]int
+}
+
+func (g *blockingGluster) count(name string) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.calls[name]++
+}
+
+func (g *blockingGluster) Peers(ctx context.Context) ([]Peer, error) {
+	g.count("Peers")
+	g.started <- struct{}{}
+	<-g.release
+	return []Peer{{ID: "peer-1"}}, nil
+}
+
+func (g *blockingGluster) IsLeader(ctx context.Context) (bool, error) {
+	g.count("IsLeader")
+	return true, nil
+}
+
+func TestGCacheKeyLock(t *testing.T) {
+	gd := &blockingGluster{
+		started: make(chan struct{}, 2),
+		release: make(chan struct{}),
+		calls:   make(map[string]int),
+	}
+	gc := NewGCacheWithTTL(gd, time.Minute)
+	gc.EnableCacheForFuncs([]string{"Peers", "IsLeader"})
+
+	peers := make(chan []Peer, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			value, err := gc.Peers(context.Background())
+			if err != nil {
+				t.Error(err)
+			}
+			peers <- value
+		}()
+	}
+	<-gd.started
+
+	// a slow call doesn't block the other functions
+	done := make(chan struct{})
+	go func() {
+		if _, err := gc.IsLeader(context.Background()); err != nil {
+			t.Error(err)
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected IsLeader not to wait for Peers")
+	}
+
+	// the wait for the call in progress ends with the context
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := gc.Peers(ctx); err != context.DeadlineExceeded {
+		t.Errorf("expected the wait to time out, got %v", err)
+	}
+
+	close(gd.release)
+	for i := 0; i < 2; i++ {
+		if value := <-peers; len(value) != 1 {
+			t.Errorf("expected the peer, got %v", value)
+		}
+	}
+	// the waiting call uses the value of the call in progress
+	gd.lock.Lock()
+	defer gd.lock.Unlock()
+	if gd.calls["Peers"] != 1 {
+		t.Errorf("expected a single call of Peers, got %d calls", gd.calls["Peers"])
+	}
+}
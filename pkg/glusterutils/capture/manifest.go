@@ -0,0 +1,93 @@
+// Package capture defines the format of the recorded command outputs,
+// used by the record mode of the exporter and replayed by the tests.
+//
+// A capture is a directory with a 'commands.json' manifest, listing the
+// recorded commands, and the files holding their outputs. The record mode
+// archives it as a tar.gz file.
+package capture
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// ManifestFile is the name of the manifest in a capture directory
+	ManifestFile = "commands.json"
+	// DefaultCmd is the command recorded when a manifest entry has none
+	DefaultCmd = "gluster"
+	// Glusterd2Cmd is the command recorded for the glusterd2 REST requests
+	Glusterd2Cmd = "glusterd2"
+)
+
+// Command represents a recorded command and its outputs.
+// Seq is the order of the call in the capture, a command run more than
+// once is recorded for each call. Stdout and Stderr are file names
+// relative to the capture directory. For the glusterd2 requests, Args
+// are the method and the request URI and ExitCode is the HTTP status
+// code of the response
+type Command struct {
+	Seq      int      `json:"seq"`
+	Cmd      string   `json:"cmd,omitempty"`
+	Args     []string `json:"args"`
+	ExitCode int      `json:"exit-code"`
+	Stdout   string   `json:"stdout,omitempty"`
+	Stderr   string   `json:"stderr,omitempty"`
+}
+
+// Manifest lists the recorded commands of a capture
+type Manifest struct {
+	Commands []Command `json:"commands"`
+}
+
+// LoadManifest reads the manifest of the capture directory
+func LoadManifest(dir string) (*Manifest, error) {
+	data, err := ioutil.ReadFile(filepath.Join(filepath.Clean(dir), ManifestFile))
+	if err != nil {
+		return nil, err
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, err
+	}
+	return &manifest, nil
+}
+
+// NormalizeArgs drops the options (like --remote-host=<...> or --nolog)
+// from the arguments, what remains identifies a command. The --xml option
+// is kept, as the plain and the XML outputs of a command differ
+func NormalizeArgs(args []string) []string {
+	var out []string
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "--") && arg != "--xml" {
+			continue
+		}
+		out = append(out, arg)
+	}
+	return out
+}
+
+// matches returns true if the recorded command is the given command
+func (c *Command) matches(cmd string, args []string) bool {
+	recordedCmd := c.Cmd
+	if recordedCmd == "" {
+		recordedCmd = DefaultCmd
+	}
+	if recordedCmd != cmd {
+		return false
+	}
+	return strings.Join(NormalizeArgs(c.Args), " ") == strings.Join(NormalizeArgs(args), " ")
+}
+
+// Find returns the first recorded call matching the command and its
+// arguments
+func (m *Manifest) Find(cmd string, args []string) (*Command, bool) {
+	for idx := range m.Commands {
+		if m.Commands[idx].matches(cmd, args) {
+			return &m.Commands[idx], true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,70 @@
+package capture
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// recordingTransport records the glusterd2 REST responses
+type recordingTransport struct {
+	recorder  *Recorder
+	transport http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	args := []string{req.Method, req.URL.RequestURI()}
+	if err := t.recorder.Record(Glusterd2Cmd, args, resp.StatusCode, body, nil); err != nil {
+		log.WithError(err).WithField("request", args).Warn("failed to record glusterd2 response")
+	}
+	return resp, nil
+}
+
+// Proxy returns the endpoint of a local HTTP proxy to the given
+// glusterd2 endpoint, which records the responses. The REST client
+// talks to the proxy in plain HTTP, the transport is used to reach the
+// glusterd2 endpoint. One proxy is started for each endpoint
+func (r *Recorder) Proxy(endpoint string, transport http.RoundTripper) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if proxyEndpoint, ok := r.proxies[endpoint]; ok {
+		return proxyEndpoint, nil
+	}
+
+	target, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	proxy.Transport = &recordingTransport{recorder: r, transport: transport}
+	go func() {
+		if err := http.Serve(listener, proxy); err != nil {
+			log.WithError(err).WithField("endpoint", endpoint).Error("glusterd2 recording proxy stopped")
+		}
+	}()
+
+	proxyEndpoint := "http://" + listener.Addr().String()
+	r.proxies[endpoint] = proxyEndpoint
+	return proxyEndpoint, nil
+}
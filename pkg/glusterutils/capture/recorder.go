@@ -0,0 +1,236 @@
+package capture
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	unsafeNameChars = regexp.MustCompile("[^A-Za-z0-9._]+")
+	maxNameLength   = 64
+
+	errRecorderClosed = errors.New("recorder is closed")
+)
+
+// Recorder saves the outputs of the commands run by the exporter into a
+// capture directory, and archives it when closed. Every output of each
+// command is kept, in the order of the calls
+type Recorder struct {
+	dir      string
+	archive  string
+	mu       sync.Mutex
+	closed   bool
+	manifest Manifest
+	proxies  map[string]string
+}
+
+// NewRecorder creates a timestamped capture directory under the given
+// directory, and returns a Recorder saving into it. The capture is
+// archived as '<capture directory>.tar.gz' when the Recorder is closed
+func NewRecorder(baseDir string) (*Recorder, error) {
+	name := "gluster-exporter-" + time.Now().UTC().Format("20060102T150405Z")
+	dir := filepath.Join(filepath.Clean(baseDir), name)
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, err
+	}
+	r := &Recorder{
+		dir:      dir,
+		archive:  dir + ".tar.gz",
+		manifest: Manifest{Commands: []Command{}},
+		proxies:  make(map[string]string),
+	}
+	if err := r.writeManifest(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Dir returns the capture directory
+func (r *Recorder) Dir() string {
+	return r.dir
+}
+
+// Archive returns the path of the archive written on Close
+func (r *Recorder) Archive() string {
+	return r.archive
+}
+
+// Close stops the recording, archives the capture directory and
+// removes it
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+
+	tmpFile := r.archive + ".tmp"
+	if err := writeArchive(tmpFile, r.dir); err != nil {
+		_ = os.Remove(tmpFile)
+		return err
+	}
+	if err := os.Rename(tmpFile, r.archive); err != nil {
+		return err
+	}
+	return os.RemoveAll(r.dir)
+}
+
+// writeArchive writes the directory into a tar.gz archive, under the
+// name of the directory
+func writeArchive(path, dir string) (err error) {
+	file, err := os.OpenFile(filepath.Clean(path), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0640)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+	gzw := gzip.NewWriter(file)
+	tw := tar.NewWriter(gzw)
+
+	root := filepath.Dir(dir)
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		name, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(name)
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		data, err := os.Open(filepath.Clean(path))
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(tw, data)
+		_ = data.Close()
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gzw.Close()
+}
+
+// AddFile copies the file into the capture directory, it is used for
+// the files read by the exporter like 'glusterd.info'
+func (r *Recorder) AddFile(path string) error {
+	data, err := ioutil.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(r.dir, filepath.Base(path)), data, 0640)
+}
+
+// ReplaceFiles replaces the subdirectory of the capture directory with
+// the files, given by their paths relative to the root, keeping their
+// layout. The files which can't be read, like of the processes which
+// exited meanwhile, are skipped
+func (r *Recorder) ReplaceFiles(subdir, root string, files []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return errRecorderClosed
+	}
+
+	dir := filepath.Join(r.dir, filepath.Clean(subdir))
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	for _, file := range files {
+		data, err := ioutil.ReadFile(filepath.Join(root, filepath.Clean(file)))
+		if err != nil {
+			continue
+		}
+		path := filepath.Join(dir, filepath.Clean(file))
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(path, data, 0640); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Record saves the outputs and the exit code of the command, with
+// the next sequence number
+func (r *Recorder) Record(cmd string, args []string, exitCode int, stdout, stderr []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return errRecorderClosed
+	}
+
+	seq := len(r.manifest.Commands)
+	command := Command{Seq: seq, Args: args, ExitCode: exitCode}
+	if cmd != DefaultCmd {
+		command.Cmd = cmd
+	}
+	prefix := fmt.Sprintf("%03d-%s", seq, fileName(cmd, args))
+	if len(stdout) > 0 {
+		command.Stdout = prefix + ".stdout"
+		if err := ioutil.WriteFile(filepath.Join(r.dir, command.Stdout), stdout, 0640); err != nil {
+			return err
+		}
+	}
+	if len(stderr) > 0 {
+		command.Stderr = prefix + ".stderr"
+		if err := ioutil.WriteFile(filepath.Join(r.dir, command.Stderr), stderr, 0640); err != nil {
+			return err
+		}
+	}
+	r.manifest.Commands = append(r.manifest.Commands, command)
+	return r.writeManifest()
+}
+
+// writeManifest replaces the manifest, the capture stays
+// replayable even if the exporter is stopped meanwhile
+func (r *Recorder) writeManifest() error {
+	data, err := json.MarshalIndent(r.manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmpFile := filepath.Join(r.dir, ManifestFile+".tmp")
+	if err := ioutil.WriteFile(tmpFile, append(data, '\n'), 0640); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile, filepath.Join(r.dir, ManifestFile))
+}
+
+// fileName returns a readable file name for the outputs of the command
+func fileName(cmd string, args []string) string {
+	name := unsafeNameChars.ReplaceAllString(strings.Join(append([]string{cmd}, NormalizeArgs(args)...), "-"), "-")
+	name = strings.Trim(name, "-")
+	if len(name) > maxNameLength {
+		name = name[:maxNameLength]
+	}
+	return name
+}
@@ -0,0 +1,186 @@
+package capture
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func newTestRecorder(t *testing.T) (*Recorder, func()) {
+	baseDir, err := ioutil.TempDir("", "capture")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := NewRecorder(baseDir)
+	if err != nil {
+		_ = os.RemoveAll(baseDir)
+		t.Fatalf("NewRecorder failed: %s", err)
+	}
+	return r, func() {
+		_ = os.RemoveAll(baseDir)
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	r, cleanup := newTestRecorder(t)
+	defer cleanup()
+	records := []struct {
+		cmd      string
+		args     []string
+		exitCode int
+		stdout   string
+		stderr   string
+	}{
+		{cmd: "gluster", args: []string{"volume", "info", "--xml"}, stdout: "<old/>"},
+		{cmd: "lvm", args: []string{"vgs", "--reportformat=json"}, exitCode: 5, stderr: "no volume groups"},
+		// recorded again, with the next sequence number
+		{cmd: "gluster", args: []string{"volume", "info", "--xml", "--remote-host=server1"}, stdout: "<new/>"},
+	}
+	for _, rec := range records {
+		if err := r.Record(rec.cmd, rec.args, rec.exitCode, []byte(rec.stdout), []byte(rec.stderr)); err != nil {
+			t.Fatalf("Record failed: %s", err)
+		}
+	}
+
+	manifest, err := LoadManifest(r.Dir())
+	if err != nil {
+		t.Fatalf("LoadManifest failed: %s", err)
+	}
+	expected := []Command{
+		{
+			Seq:    0,
+			Args:   []string{"volume", "info", "--xml"},
+			Stdout: "000-gluster-volume-info-xml.stdout",
+		},
+		{
+			Seq:      1,
+			Cmd:      "lvm",
+			Args:     []string{"vgs", "--reportformat=json"},
+			ExitCode: 5,
+			Stderr:   "001-lvm-vgs.stderr",
+		},
+		{
+			Seq:    2,
+			Args:   []string{"volume", "info", "--xml", "--remote-host=server1"},
+			Stdout: "002-gluster-volume-info-xml.stdout",
+		},
+	}
+	if !reflect.DeepEqual(manifest.Commands, expected) {
+		t.Errorf("expected %+v, got %+v", expected, manifest.Commands)
+	}
+
+	recorded, ok := manifest.Find(DefaultCmd, []string{"volume", "info", "--xml"})
+	if !ok {
+		t.Fatalf("recorded command not found")
+	}
+	out, err := ioutil.ReadFile(filepath.Join(r.Dir(), recorded.Stdout))
+	if err != nil || string(out) != "<old/>" {
+		t.Errorf("expected the first output, got %q (%v)", out, err)
+	}
+}
+
+func TestRecorderClose(t *testing.T) {
+	r, cleanup := newTestRecorder(t)
+	defer cleanup()
+	if err := r.Record("gluster", []string{"pool", "list", "--xml"}, 0, []byte("<peers/>"), nil); err != nil {
+		t.Fatalf("Record failed: %s", err)
+	}
+	if err := r.Close(); err != nil {
+		t.Fatalf("Close failed: %s", err)
+	}
+	if _, err := os.Stat(r.Dir()); !os.IsNotExist(err) {
+		t.Errorf("expected the capture directory to be removed, got %v", err)
+	}
+	if err := r.Record("gluster", []string{"pool", "list", "--xml"}, 0, nil, nil); err != errRecorderClosed {
+		t.Errorf("expected the closed recorder to fail, got %v", err)
+	}
+
+	file, err := os.Open(r.Archive())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	gzr, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	tr := tar.NewReader(gzr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, hdr.Name)
+	}
+	sort.Strings(names)
+	name := filepath.Base(r.Dir())
+	expected := []string{name, name + "/000-gluster-pool-list-xml.stdout", name + "/" + ManifestFile}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected %v in the archive, got %v", expected, names)
+	}
+}
+
+func TestRecorderProxy(t *testing.T) {
+	glusterd2 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/v1/peers" {
+			http.NotFound(w, req)
+			return
+		}
+		_, _ = w.Write([]byte(`[{"id": "peer1"}]`))
+	}))
+	defer glusterd2.Close()
+
+	r, cleanup := newTestRecorder(t)
+	defer cleanup()
+	endpoint, err := r.Proxy(glusterd2.URL, http.DefaultTransport)
+	if err != nil {
+		t.Fatalf("Proxy failed: %s", err)
+	}
+	if again, _ := r.Proxy(glusterd2.URL, http.DefaultTransport); again != endpoint {
+		t.Errorf("expected the proxy %s to be reused, got %s", endpoint, again)
+	}
+
+	for _, path := range []string{"/v1/peers", "/v1/volumes?volname=vol1"} {
+		resp, err := http.Get(endpoint + path)
+		if err != nil {
+			t.Fatalf("%s: %s", path, err)
+		}
+		_ = resp.Body.Close()
+	}
+
+	manifest, err := LoadManifest(r.Dir())
+	if err != nil {
+		t.Fatalf("LoadManifest failed: %s", err)
+	}
+	expected := []Command{
+		{
+			Seq:      0,
+			Cmd:      Glusterd2Cmd,
+			Args:     []string{"GET", "/v1/peers"},
+			ExitCode: http.StatusOK,
+			Stdout:   "000-glusterd2-GET-v1-peers.stdout",
+		},
+		{
+			Seq:      1,
+			Cmd:      Glusterd2Cmd,
+			Args:     []string{"GET", "/v1/volumes?volname=vol1"},
+			ExitCode: http.StatusNotFound,
+			Stdout:   "001-glusterd2-GET-v1-volumes-volname-vol1.stdout",
+		},
+	}
+	if !reflect.DeepEqual(manifest.Commands, expected) {
+		t.Errorf("expected %+v, got %+v", expected, manifest.Commands)
+	}
+}
@@ -1,28 +1,21 @@
 package glusterutils
 
 import (
+	"context"
 	"time"
 
 	"github.com/gluster/gluster-prometheus/gluster-exporter/conf"
-	"github.com/gluster/glusterd2/pkg/restclient"
 )
 
-func initRESTClient(config *conf.GConfig) (*restclient.Client, error) {
-	client, err := restclient.New(
-		config.Glusterd2Endpoint,
-		config.Glusterd2User,
-		config.Glusterd2Secret,
-		config.Glusterd2Cacert,
-		config.Glusterd2Insecure,
-	)
-	if err != nil {
-		return nil, err
-	}
-	client.SetTimeout(time.Duration(config.Timeout) * time.Second)
-	return client, nil
+// gd2Error converts the error of a glusterd2 REST call into a
+// 'TimeoutError' if the call timed out, the timeout is the one applied
+// to the call
+func gd2Error(ctx context.Context, op string, timeout time.Duration, err error) error {
+	return checkTimeout(ctx, "glusterd2 "+op, timeout, err)
 }
 
-func setDefaultConfig(config *conf.GConfig) {
+// SetDefaultConfig sets the defaults of the gluster configuration
+func SetDefaultConfig(config *conf.GConfig) {
 	if config.Timeout == 0 {
 		config.Timeout = 30
 	}
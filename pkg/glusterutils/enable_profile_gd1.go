@@ -1,16 +1,18 @@
 package glusterutils
 
 import (
+	"context"
+
 	"github.com/gluster/gluster-prometheus/pkg/glusterutils/glusterconsts"
 	log "github.com/sirupsen/logrus"
 )
 
 // EnableVolumeProfiling enables profiling for a volume
-func (g *GD1) EnableVolumeProfiling(volume Volume) error {
+func (g *GD1) EnableVolumeProfiling(ctx context.Context, volume Volume) error {
 	value, exists := volume.Options[glusterconsts.CountFOPHitsGD1]
 	if !exists {
 		// Enable profiling for the volumes as its not set
-		_, err := g.execGluster("volume", "profile", volume.Name, "start")
+		_, err := g.execGluster(ctx, "volume", "profile", volume.Name, "start")
 		if err != nil {
 			return err
 		}
@@ -1,33 +1,33 @@
 package glusterutils
 
 import (
+	"context"
+
 	"github.com/gluster/gluster-prometheus/pkg/glusterutils/glusterconsts"
 	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/restclient"
 	log "github.com/sirupsen/logrus"
 )
 
 // EnableVolumeProfiling enables profiling for a volume
-func (g *GD2) EnableVolumeProfiling(volume Volume) error {
-	client, err := initRESTClient(g.config)
-	if err != nil {
-		return err
-	}
-
+func (g *GD2) EnableVolumeProfiling(ctx context.Context, volume Volume) error {
 	value, exists := volume.Options[glusterconsts.CountFOPHitsGD2]
 	if !exists {
 		// Enable profiling for the volumes as its not set
-		err := client.VolumeSet(
-			volume.Name,
-			api.VolOptionReq{
-				Options: map[string]string{
-					glusterconsts.CountFOPHitsGD2:       "on",
-					glusterconsts.LatencyMeasurementGD2: "on",
-				},
-				VolOptionFlags: api.VolOptionFlags{
-					AllowAdvanced: true,
+		err := g.pool.call(ctx, "VolumeSet", func(client *restclient.Client) error {
+			return client.VolumeSet(
+				volume.Name,
+				api.VolOptionReq{
+					Options: map[string]string{
+						glusterconsts.CountFOPHitsGD2:       "on",
+						glusterconsts.LatencyMeasurementGD2: "on",
+					},
+					VolOptionFlags: api.VolOptionFlags{
+						AllowAdvanced: true,
+					},
 				},
-			},
-		)
+			)
+		})
 		if err != nil {
 			return err
 		}
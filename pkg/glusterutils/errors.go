@@ -0,0 +1,57 @@
+package glusterutils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNotSupported is returned by the calls which are not
+// supported by the gluster management daemon in use
+var ErrNotSupported = errors.New("not supported by the gluster management daemon")
+
+// TimeoutError is returned when a gluster command or a glusterd2
+// REST call does not finish within the allowed time
+type TimeoutError struct {
+	Op      string
+	Timeout time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("%s timed out after %s", e.Op, e.Timeout)
+}
+
+// IsTimeout returns true if the error is a 'TimeoutError'
+func IsTimeout(err error) bool {
+	_, ok := err.(*TimeoutError)
+	return ok
+}
+
+// callTimeout returns the time allowed for a call, which is the
+// configured timeout or the time left till the context deadline
+func callTimeout(ctx context.Context, timeoutSecs int64) time.Duration {
+	timeout := time.Duration(timeoutSecs) * time.Second
+	if deadline, ok := ctx.Deadline(); ok {
+		if left := time.Until(deadline); left < timeout {
+			timeout = left
+		}
+	}
+	return timeout
+}
+
+// checkTimeout converts the error of a call into a 'TimeoutError' if the
+// call was aborted because its context expired, or the error itself
+// reports a timeout (like the net/http client errors)
+func checkTimeout(ctx context.Context, op string, timeout time.Duration, err error) error {
+	if err == nil {
+		return nil
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		return &TimeoutError{Op: op, Timeout: timeout}
+	}
+	if tErr, ok := err.(interface{ Timeout() bool }); ok && tErr.Timeout() {
+		return &TimeoutError{Op: op, Timeout: timeout}
+	}
+	return err
+}
@@ -2,6 +2,7 @@ package glusterutils
 
 import (
 	"bufio"
+	"context"
 	"errors"
 	"io"
 	"os"
@@ -15,18 +16,25 @@ import (
 	"github.com/gluster/gluster-prometheus/pkg/glusterutils/glusterconsts"
 )
 
+const (
+	// gd1PeerIDFile holds the local peer ID, in the glusterd workdir
+	gd1PeerIDFile = "glusterd.info"
+	// gd2PeerIDFile holds the local peer ID, in the glusterd2 workdir
+	gd2PeerIDFile = "uuid.toml"
+)
+
 var (
 	peerIDPattern = regexp.MustCompile("[0-9a-f]{8}-[0-9a-f]{4}-[1-5][0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}")
 )
 
 // IsLeader returns true or false based on whether the node is the leader of the cluster or not
-func (g *GD1) IsLeader() (bool, error) {
-	setDefaultConfig(g.config)
-	peerList, err := g.Peers()
+func (g *GD1) IsLeader(ctx context.Context) (bool, error) {
+	SetDefaultConfig(g.config)
+	peerList, err := g.Peers(ctx)
 	if err != nil {
 		return false, err
 	}
-	peerID, err := g.LocalPeerID()
+	peerID, err := g.LocalPeerID(ctx)
 	if err != nil {
 		return false, err
 	}
@@ -47,12 +55,12 @@ func (g *GD1) IsLeader() (bool, error) {
 }
 
 // IsLeader returns true or false based on whether the node is the leader of the cluster or not
-func (g *GD2) IsLeader() (bool, error) {
-	peerList, err := g.Peers()
+func (g *GD2) IsLeader(ctx context.Context) (bool, error) {
+	peerList, err := g.Peers(ctx)
 	if err != nil {
 		return false, err
 	}
-	peerID, err := g.LocalPeerID()
+	peerID, err := g.LocalPeerID(ctx)
 	if err != nil {
 		return false, err
 	}
@@ -74,10 +82,11 @@ func MakeGluster(expConf *conf.Config) (gi GInterface) {
 	if gConfig == nil {
 		return nil
 	}
-	setDefaultConfig(gConfig)
-	gi = &GD2{config: gConfig}
+	SetDefaultConfig(gConfig)
 	if gConfig.GlusterMgmt == "" || gConfig.GlusterMgmt == glusterconsts.MgmtGlusterd {
 		gi = &GD1{config: gConfig}
+	} else {
+		gi = &GD2{config: gConfig, pool: newGD2Pool(gConfig)}
 	}
 	cacheTTL := time.Duration(expConf.CacheTTL) * time.Second
 	cachedGI := NewGCacheWithTTL(gi, cacheTTL)
@@ -113,9 +122,9 @@ func readPeerID(fileStream io.ReadCloser, keywordID string) (string, error) {
 }
 
 // LocalPeerID returns local peer ID of glusterd
-func (g *GD1) LocalPeerID() (string, error) {
+func (g *GD1) LocalPeerID(ctx context.Context) (string, error) {
 	keywordID := "UUID"
-	peeridFile := g.config.GlusterdWorkdir + "/glusterd.info"
+	peeridFile := g.config.GlusterdWorkdir + "/" + gd1PeerIDFile
 	fileStream, err := os.Open(filepath.Clean(peeridFile))
 	if err != nil {
 		return "", err
@@ -124,9 +133,9 @@ func (g *GD1) LocalPeerID() (string, error) {
 }
 
 // LocalPeerID returns local peer ID of glusterd2
-func (g *GD2) LocalPeerID() (string, error) {
+func (g *GD2) LocalPeerID(ctx context.Context) (string, error) {
 	keywordID := "peer-id"
-	peeridFile := g.config.GlusterdWorkdir + "/uuid.toml"
+	peeridFile := g.config.GlusterdWorkdir + "/" + gd2PeerIDFile
 	fileStream, err := os.Open(filepath.Clean(peeridFile))
 	if err != nil {
 		return "", err
@@ -168,12 +177,27 @@ func GetGlusterVersion() (string, error) {
 
 // ExecuteCmd enables to execute system cmds and returns stdout, err
 func ExecuteCmd(cmd string) ([]byte, error) {
+	return ExecuteCmdContext(context.Background(), cmd)
+}
+
+// ExecuteCmdContext is like ExecuteCmd, the cmd is killed once the
+// context is done
+func ExecuteCmdContext(ctx context.Context, cmd string) ([]byte, error) {
 	cmdfields := strings.Fields(cmd)
-	cmdstr := cmdfields[0]
-	if fullcmd, err := exec.LookPath(cmdfields[0]); err == nil {
+	return ExecuteCmdArgsContext(ctx, cmdfields[0], cmdfields[1:]...)
+}
+
+// ExecuteCmdArgs executes the system cmd with the given args and returns stdout, err
+func ExecuteCmdArgs(name string, args ...string) ([]byte, error) {
+	return ExecuteCmdArgsContext(context.Background(), name, args...)
+}
+
+// ExecuteCmdArgsContext is like ExecuteCmdArgs, the cmd is killed once
+// the context is done
+func ExecuteCmdArgsContext(ctx context.Context, name string, args ...string) ([]byte, error) {
+	cmdstr := name
+	if fullcmd, err := exec.LookPath(name); err == nil {
 		cmdstr = fullcmd
 	}
-	args := cmdfields[1:]
-	out, err := exec.Command(cmdstr, args...).Output() // #nosec
-	return out, err
+	return runCmd(ctx, filepath.Base(name), exec.Command(cmdstr, args...)) // #nosec
 }
@@ -1,8 +1,11 @@
 package glusterutils
 
 import (
+	"context"
 	"encoding/xml"
+	"strings"
 
+	"github.com/gluster/gluster-prometheus/pkg/glusterutils/capture"
 	"github.com/gluster/gluster-prometheus/pkg/glusterutils/glusterconsts"
 
 	"fmt"
@@ -22,10 +25,26 @@ type healEntriesXML struct {
 	NumHealEntries string   `xml:"numberOfEntries"`
 }
 
+type healSummaryBricks struct {
+	XMLName     xml.Name                `xml:"cliOutput"`
+	Healentries []healSummaryEntriesXML `xml:"healInfo>bricks>brick"`
+}
+
+type healSummaryEntriesXML struct {
+	XMLName                xml.Name `xml:"brick"`
+	HostUUID               string   `xml:"hostUuid,attr"`
+	Brickname              string   `xml:"name"`
+	Connected              string   `xml:"status"`
+	TotalEntries           string   `xml:"totalNumberOfEntries"`
+	EntriesInHealPending   string   `xml:"numberOfEntriesInHealPending"`
+	EntriesInSplitBrain    string   `xml:"numberOfEntriesInSplitBrain"`
+	EntriesPossiblyHealing string   `xml:"numberOfEntriesPossiblyHealing"`
+}
+
 type gd1Brick struct {
 	Name      string `xml:"name"`
 	PeerID    string `xml:"hostUuid"`
-	IsArbiter int    `xml:"IsArbiter"`
+	IsArbiter int    `xml:"isArbiter"`
 }
 
 type gd1Option struct {
@@ -160,6 +179,118 @@ type gd1Process struct {
 	SizeFree      uint64           `xml:"sizeFree"`
 }
 
+type gd1Client struct {
+	Hostname   string `xml:"hostname"`
+	BytesRead  uint64 `xml:"bytesRead"`
+	BytesWrite uint64 `xml:"bytesWrite"`
+	OpVersion  int    `xml:"opVersion"`
+}
+
+type gd1BrickClients struct {
+	Hostname    string      `xml:"hostname"`
+	Path        string      `xml:"path"`
+	PeerID      string      `xml:"peerid"`
+	Status      int         `xml:"status"`
+	ClientCount int         `xml:"clientsStatus>clientCount"`
+	Clients     []gd1Client `xml:"clientsStatus>client"`
+}
+
+type gd1VolumeClients struct {
+	XMLName xml.Name          `xml:"cliOutput"`
+	Bricks  []gd1BrickClients `xml:"volStatus>volumes>volume>node"`
+}
+
+type gd1Mallinfo struct {
+	Arena    int64 `xml:"arena"`
+	Ordblks  int64 `xml:"ordblks"`
+	Smblks   int64 `xml:"smblks"`
+	Hblks    int64 `xml:"hblks"`
+	Hblkhd   int64 `xml:"hblkhd"`
+	Usmblks  int64 `xml:"usmblks"`
+	Fsmblks  int64 `xml:"fsmblks"`
+	Uordblks int64 `xml:"uordblks"`
+	Fordblks int64 `xml:"fordblks"`
+	Keepcost int64 `xml:"keepcost"`
+}
+
+type gd1Mempool struct {
+	Name        string `xml:"name"`
+	HotCount    int64  `xml:"hotCount"`
+	ColdCount   int64  `xml:"coldCount"`
+	PaddedSize  int64  `xml:"padddedSizeOf"` // sic, as written by the CLI
+	AllocCount  int64  `xml:"allocCount"`
+	MaxAlloc    int64  `xml:"maxAlloc"`
+	PoolMisses  int64  `xml:"poolMisses"`
+	MaxStdAlloc int64  `xml:"maxStdAlloc"`
+}
+
+type gd1BrickMemStatus struct {
+	Hostname string       `xml:"hostname"`
+	Path     string       `xml:"path"`
+	PeerID   string       `xml:"peerid"`
+	Status   int          `xml:"status"`
+	Mallinfo gd1Mallinfo  `xml:"memStatus>mallinfo"`
+	Mempools []gd1Mempool `xml:"memStatus>mempool>pool"`
+}
+
+type gd1VolumeMemStatus struct {
+	XMLName xml.Name            `xml:"cliOutput"`
+	Bricks  []gd1BrickMemStatus `xml:"volStatus>volumes>volume>node"`
+}
+
+type gd1InodeTable struct {
+	ActiveSize int64 `xml:"activeSize"`
+	LRUSize    int64 `xml:"lruSize"`
+	PurgeSize  int64 `xml:"purgeSize"`
+}
+
+type gd1BrickInodeStatus struct {
+	Hostname    string          `xml:"hostname"`
+	Path        string          `xml:"path"`
+	PeerID      string          `xml:"peerid"`
+	Status      int             `xml:"status"`
+	InodeTables []gd1InodeTable `xml:"inodeStatus>connections>connection>inodeTable"`
+}
+
+type gd1VolumeInodeStatus struct {
+	XMLName xml.Name              `xml:"cliOutput"`
+	Bricks  []gd1BrickInodeStatus `xml:"volStatus>volumes>volume>node"`
+}
+
+type gd1FdConnection struct {
+	Fds []struct{} `xml:"fdTable>fd"`
+}
+
+type gd1BrickFdStatus struct {
+	Hostname    string            `xml:"hostname"`
+	Path        string            `xml:"path"`
+	PeerID      string            `xml:"peerid"`
+	Status      int               `xml:"status"`
+	Connections []gd1FdConnection `xml:"fdStatus>connection"`
+}
+
+type gd1VolumeFdStatus struct {
+	XMLName xml.Name           `xml:"cliOutput"`
+	Bricks  []gd1BrickFdStatus `xml:"volStatus>volumes>volume>node"`
+}
+
+type gd1CallStack struct {
+	FrameCount int64 `xml:"count"`
+}
+
+type gd1BrickCallpoolStatus struct {
+	Hostname   string         `xml:"hostname"`
+	Path       string         `xml:"path"`
+	PeerID     string         `xml:"peerid"`
+	Status     int            `xml:"status"`
+	CallStacks []gd1CallStack `xml:"callpoolStatus>callStack"`
+}
+
+type gd1VolumeCallpoolStatus struct {
+	XMLName xml.Name                 `xml:"cliOutput"`
+	Bricks  []gd1BrickCallpoolStatus `xml:"volStatus>volumes>volume>node"`
+}
+
 type gd1VolumeStatusInfo struct {
 	Name          string       `xml:"volName"`
 	NodeCount     int          `xml:"nodeCount"`
@@ -171,6 +302,98 @@ type gd1VolumeStatus struct {
 	List    []gd1VolumeStatusInfo `xml:"volStatus>volumes>volume"`
 }
 
+type gd1GeoRepPair struct {
+	MasterNode               string `xml:"master_node"`
+	MasterBrick              string `xml:"master_brick"`
+	MasterNodeUUID           string `xml:"master_node_uuid"`
+	SlaveUser                string `xml:"slave_user"`
+	Slave                    string `xml:"slave"`
+	SlaveNode                string `xml:"slave_node"`
+	Status                   string `xml:"status"`
+	CrawlStatus              string `xml:"crawl_status"`
+	LastSynced               string `xml:"last_synced"`
+	Entry                    string `xml:"entry"`
+	Data                     string `xml:"data"`
+	Meta                     string `xml:"meta"`
+	Failures                 string `xml:"failures"`
+	CheckpointTime           string `xml:"checkpoint_time"`
+	CheckpointCompleted      string `xml:"checkpoint_completed"`
+	CheckpointCompletionTime string `xml:"checkpoint_completion_time"`
+}
+
+type gd1GeoRepSession struct {
+	SessionSlave string          `xml:"session_slave"`
+	Pairs        []gd1GeoRepPair `xml:"pair"`
+}
+
+type gd1GeoRepVolume struct {
+	Name     string             `xml:"name"`
+	Sessions []gd1GeoRepSession `xml:"sessions>session"`
+}
+
+type gd1GeoRepStatus struct {
+	XMLName xml.Name          `xml:"cliOutput"`
+	OpRet   int               `xml:"opRet"`
+	OpErr   string            `xml:"opErrstr"`
+	Volumes []gd1GeoRepVolume `xml:"geoRep>volume"`
+}
+
+type gd1TaskParams struct {
+	Bricks []string `xml:"brick"`
+}
+
+type gd1Task struct {
+	Type   string        `xml:"type"`
+	ID     string        `xml:"id"`
+	Params gd1TaskParams `xml:"params"`
+	Status int           `xml:"status"`
+}
+
+type gd1VolumeTasks struct {
+	XMLName xml.Name  `xml:"cliOutput"`
+	Tasks   []gd1Task `xml:"volStatus>volumes>volume>tasks>task"`
+}
+
+type gd1RebalanceNode struct {
+	NodeName  string  `xml:"nodeName"`
+	ID        string  `xml:"id"`
+	Files     int64   `xml:"files"`
+	Size      int64   `xml:"size"`
+	Lookups   int64   `xml:"lookups"`
+	Failures  int64   `xml:"failures"`
+	Skipped   int64   `xml:"skipped"`
+	Status    int     `xml:"status"`
+	StatusStr string  `xml:"statusStr"`
+	RunTime   float64 `xml:"runtime"`
+}
+
+type gd1RebalanceStatus struct {
+	XMLName     xml.Name           `xml:"cliOutput"`
+	Rebalance   []gd1RebalanceNode `xml:"volRebalance>node"`
+	RemoveBrick []gd1RebalanceNode `xml:"volRemoveBrick>node"`
+}
+
+type gd1QuotaLimit struct {
+	Path              string `xml:"path"`
+	HardLimit         string `xml:"hard_limit"`
+	SoftLimitPercent  string `xml:"soft_limit_percent"`
+	SoftLimitValue    string `xml:"soft_limit_value"`
+	UsedSpace         string `xml:"used_space"`
+	AvailSpace        string `xml:"avail_space"`
+	FileCount         string `xml:"file_count"`
+	DirCount          string `xml:"dir_count"`
+	Available         string `xml:"available"`
+	SoftLimitExceeded string `xml:"sl_exceeded"`
+	HardLimitExceeded string `xml:"hl_exceeded"`
+}
+
+type gd1QuotaList struct {
+	XMLName xml.Name        `xml:"cliOutput"`
+	OpRet   int             `xml:"opRet"`
+	OpErr   string          `xml:"opErrstr"`
+	Limits  []gd1QuotaLimit `xml:"volQuota>limit"`
+}
+
 func (t *gd1Transport) String() string {
 	// 0 - tcp
 	// 1 - rdma
@@ -185,35 +408,50 @@ func (t *gd1Transport) String() string {
 
 func getSubvolType(voltype string) string {
 	switch voltype {
-	case glusterconsts.VolumeTypeDistReplicate:
+	case glusterconsts.VolumeTypeDistReplicate, glusterconsts.VolumeTypeDistReplicateGD1:
 		return glusterconsts.SubvolTypeReplicate
-	case glusterconsts.VolumeTypeDistDisperse:
+	case glusterconsts.VolumeTypeDistDisperse, glusterconsts.VolumeTypeDistDisperseGD1:
 		return glusterconsts.SubvolTypeDisperse
 	default:
 		return voltype
 	}
 }
 
+// getSubvolBricksCount returns the no of bricks in each subvolume,
+// the disperse count is checked first since glusterd reports the
+// replica count of disperse volumes as 1
 func getSubvolBricksCount(replicaCount int, disperseCount int) int {
-	if replicaCount > 0 {
-		return replicaCount
-	}
-
 	if disperseCount > 0 {
 		return disperseCount
 	}
+
+	if replicaCount > 0 {
+		return replicaCount
+	}
 	return 1
 }
 
-// execGluster runs `gluster` with --xml --remote-host=<...> and the args provided
-func (g *GD1) execGluster(args ...string) ([]byte, error) {
+// execGluster runs `gluster` with --xml --remote-host=<...> and the args provided.
+// The command runs in its own process group, which is killed as a whole
+// when the configured timeout expires or the context is done
+func (g *GD1) execGluster(ctx context.Context, args ...string) ([]byte, error) {
 	// always request output in XML format
-	args = append(args, "--xml")
+	return g.execGlusterPlain(ctx, append(args, "--xml")...)
+}
+
+// execGlusterPlain runs the gluster command like 'execGluster', but
+// returns the plain output, for the few details missing in the XML output
+func (g *GD1) execGlusterPlain(ctx context.Context, args ...string) ([]byte, error) {
 	// grab remote host from config
 	if g.config.GlusterGlusterdSock != "" {
 		args = append(args, fmt.Sprintf("--glusterd-sock=%s", g.config.GlusterGlusterdSock))
 	} else if g.config.GlusterRemoteHost != "" {
 		args = append(args, fmt.Sprintf("--remote-host=%s", g.config.GlusterRemoteHost))
 	}
-	return exec.Command(g.config.GlusterCmd, args...).Output() // #nosec
+	timeout := callTimeout(ctx, g.config.Timeout)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	cmd := exec.Command(g.config.GlusterCmd, args...) // #nosec
+	out, err := runCmd(ctx, capture.DefaultCmd, cmd)
+	return out, checkTimeout(ctx, "gluster "+strings.Join(args, " "), timeout, err)
 }
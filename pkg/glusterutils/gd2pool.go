@@ -0,0 +1,232 @@
+package glusterutils
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/gluster/gluster-prometheus/gluster-exporter/conf"
+	"github.com/gluster/glusterd2/pkg/restclient"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// gd2BreakerThreshold is the number of consecutive failures of a
+	// glusterd2 endpoint which opens its circuit breaker
+	gd2BreakerThreshold = 3
+	// gd2BreakerCooldown is how long an endpoint is skipped once its
+	// circuit breaker is open, before a call is allowed to try it again
+	gd2BreakerCooldown = 30 * time.Second
+	// gd2IdleClients is the number of idle clients kept for each endpoint
+	gd2IdleClients = 8
+)
+
+// errGD2Unavailable is returned when the circuit breakers of all the
+// glusterd2 endpoints are open
+var errGD2Unavailable = errors.New("no glusterd2 endpoint available")
+
+// GD2EndpointStatus is the state of a glusterd2 endpoint
+type GD2EndpointStatus struct {
+	Endpoint string
+	// Up is false from a failed call to the endpoint
+	// till a call to the endpoint succeeds
+	Up bool
+}
+
+// gd2Endpoint is a glusterd2 endpoint, with its idle
+// clients and the state of its circuit breaker
+type gd2Endpoint struct {
+	url string
+	// clients are the idle clients, a client is used by a single call
+	// at a time, as its timeout is set for each call
+	clients   chan *restclient.Client
+	lock      sync.Mutex
+	failures  int
+	openUntil time.Time
+}
+
+// allow returns true if the endpoint can be called. Once the circuit
+// breaker is open, a single call is allowed after each cooldown
+func (e *gd2Endpoint) allow(now time.Time) bool {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if e.failures < gd2BreakerThreshold {
+		return true
+	}
+	if now.Before(e.openUntil) {
+		return false
+	}
+	e.openUntil = now.Add(gd2BreakerCooldown)
+	return true
+}
+
+func (e *gd2Endpoint) succeeded() {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.failures = 0
+}
+
+func (e *gd2Endpoint) failed(now time.Time) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.failures++
+	if e.failures >= gd2BreakerThreshold {
+		e.openUntil = now.Add(gd2BreakerCooldown)
+	}
+}
+
+func (e *gd2Endpoint) status() GD2EndpointStatus {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	return GD2EndpointStatus{Endpoint: e.url, Up: e.failures == 0}
+}
+
+// release keeps the client for the next calls, so that its
+// keep-alive connections to glusterd2 are reused
+func (e *gd2Endpoint) release(client *restclient.Client) {
+	select {
+	case e.clients <- client:
+	default:
+	}
+}
+
+// gd2Pool calls the configured glusterd2 endpoints in turn, and fails
+// over to the next endpoint when an endpoint can't be reached. The
+// clients are kept across the calls
+type gd2Pool struct {
+	config    *conf.GConfig
+	endpoints []*gd2Endpoint
+	lock      sync.Mutex
+	// next is the endpoint the next call starts with
+	next int
+}
+
+func newGD2Pool(config *conf.GConfig) *gd2Pool {
+	pool := &gd2Pool{config: config}
+	for _, endpoint := range config.Glusterd2Endpoints() {
+		pool.endpoints = append(pool.endpoints, &gd2Endpoint{
+			url:     endpoint,
+			clients: make(chan *restclient.Client, gd2IdleClients),
+		})
+	}
+	return pool
+}
+
+// isEndpointError returns true if the error is of the connection to
+// glusterd2, like a refused connection or a timeout, rather than an
+// error response of glusterd2
+func isEndpointError(err error) bool {
+	_, ok := err.(net.Error)
+	return ok
+}
+
+// try calls the endpoints in turn till one of them is reached, the
+// endpoints with an open circuit breaker are skipped. The error of
+// the last endpoint tried is returned if none is reached
+func (p *gd2Pool) try(ctx context.Context, fn func(*gd2Endpoint) error) error {
+	if len(p.endpoints) == 0 {
+		return errGD2Unavailable
+	}
+	p.lock.Lock()
+	start := p.next
+	p.next = (p.next + 1) % len(p.endpoints)
+	p.lock.Unlock()
+
+	lastErr := errGD2Unavailable
+	for idx := range p.endpoints {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		endpoint := p.endpoints[(start+idx)%len(p.endpoints)]
+		if !endpoint.allow(time.Now()) {
+			continue
+		}
+		err := fn(endpoint)
+		if err == nil || !isEndpointError(err) {
+			endpoint.succeeded()
+			return err
+		}
+		// the endpoint is not blamed for the call running out of time
+		if ctx.Err() != nil {
+			return err
+		}
+		endpoint.failed(time.Now())
+		log.WithError(err).WithField("endpoint", endpoint.url).Debug("glusterd2 endpoint failed, trying the next one")
+		lastErr = err
+	}
+	return lastErr
+}
+
+// client returns an idle client of the endpoint, or a new one, with the
+// given timeout, as the client doesn't take a context itself
+func (p *gd2Pool) client(endpoint *gd2Endpoint, timeout time.Duration) (*restclient.Client, error) {
+	var client *restclient.Client
+	select {
+	case client = <-endpoint.clients:
+	default:
+		url := endpoint.url
+		cacert := p.config.Glusterd2Cacert
+		insecure := p.config.Glusterd2Insecure
+		if recorder != nil {
+			// the recording proxy handles the TLS to glusterd2
+			proxyEndpoint, err := gd2RecordingEndpoint(p.config, url)
+			if err != nil {
+				return nil, err
+			}
+			url, cacert, insecure = proxyEndpoint, "", false
+		}
+		var err error
+		client, err = restclient.New(
+			url,
+			p.config.Glusterd2User,
+			p.config.Glusterd2Secret,
+			cacert,
+			insecure,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+	client.SetTimeout(timeout)
+	return client, nil
+}
+
+// call runs the glusterd2 REST call 'op' with a client of the first
+// endpoint reached. The timeout of each try is bounded by the context
+// deadline
+func (p *gd2Pool) call(ctx context.Context, op string, fn func(*restclient.Client) error) error {
+	var timeout time.Duration
+	err := p.try(ctx, func(endpoint *gd2Endpoint) error {
+		timeout = callTimeout(ctx, p.config.Timeout)
+		client, err := p.client(endpoint, timeout)
+		if err != nil {
+			return err
+		}
+		err = fn(client)
+		endpoint.release(client)
+		return err
+	})
+	return gd2Error(ctx, op, timeout, err)
+}
+
+func (p *gd2Pool) statuses() []GD2EndpointStatus {
+	statuses := make([]GD2EndpointStatus, len(p.endpoints))
+	for idx, endpoint := range p.endpoints {
+		statuses[idx] = endpoint.status()
+	}
+	return statuses
+}
+
+// GD2EndpointStatuses returns the state of the glusterd2 endpoints,
+// or nil if the gluster management daemon is not glusterd2
+func GD2EndpointStatuses(gi GInterface) []GD2EndpointStatus {
+	if gc, ok := gi.(*GCache); ok {
+		gi = gc.gd
+	}
+	if gd2, ok := gi.(*GD2); ok && gd2.pool != nil {
+		return gd2.pool.statuses()
+	}
+	return nil
+}
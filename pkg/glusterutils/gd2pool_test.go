@@ -0,0 +1,102 @@
+package glusterutils
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gluster/gluster-prometheus/gluster-exporter/conf"
+	"github.com/gluster/glusterd2/pkg/restclient"
+)
+
+func TestGD2PoolFailover(t *testing.T) {
+	pool := newGD2Pool(&conf.GConfig{Glusterd2Endpoint: "http://gd2-a:24007, http://gd2-b:24007"})
+	if len(pool.endpoints) != 2 {
+		t.Fatalf("expected 2 endpoints, got %d", len(pool.endpoints))
+	}
+	down, up := pool.endpoints[0], pool.endpoints[1]
+
+	healthy := false
+	calls := make(map[string]int)
+	call := func(endpoint *gd2Endpoint) error {
+		calls[endpoint.url]++
+		if endpoint == down && !healthy {
+			return &url.Error{Op: "Get", URL: endpoint.url, Err: errors.New("connection refused")}
+		}
+		return nil
+	}
+	for i := 0; i < 10; i++ {
+		if err := pool.try(context.Background(), call); err != nil {
+			t.Fatalf("expected the call to fail over, got %s", err)
+		}
+	}
+	// the calls alternate between the endpoints, till the
+	// circuit breaker of the failing endpoint opens
+	if calls[down.url] != gd2BreakerThreshold {
+		t.Errorf("expected the failing endpoint to be skipped after %d calls, got %d calls",
+			gd2BreakerThreshold, calls[down.url])
+	}
+	if calls[up.url] != 10 {
+		t.Errorf("expected all the calls to reach the other endpoint, got %d calls", calls[up.url])
+	}
+	expected := []GD2EndpointStatus{{Endpoint: down.url, Up: false}, {Endpoint: up.url, Up: true}}
+	if statuses := pool.statuses(); statuses[0] != expected[0] || statuses[1] != expected[1] {
+		t.Errorf("expected the statuses %v, got %v", expected, statuses)
+	}
+
+	// the endpoint is tried again once the cooldown is over
+	healthy = true
+	down.openUntil = time.Now().Add(-time.Second)
+	for i := 0; i < 2; i++ {
+		if err := pool.try(context.Background(), call); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if calls[down.url] != gd2BreakerThreshold+1 || !down.status().Up {
+		t.Errorf("expected the endpoint to be up again, got %d calls", calls[down.url])
+	}
+}
+
+func TestGD2PoolErrorResponse(t *testing.T) {
+	pool := newGD2Pool(&conf.GConfig{Glusterd2Endpoint: "http://gd2-a:24007,http://gd2-b:24007"})
+	errNotFound := errors.New("volume not found")
+	calls := 0
+	err := pool.try(context.Background(), func(endpoint *gd2Endpoint) error {
+		calls++
+		return errNotFound
+	})
+	// an error response of glusterd2 is not retried on the next endpoint
+	if err != errNotFound || calls != 1 {
+		t.Errorf("expected the error response after a single call, got %v after %d calls", err, calls)
+	}
+	for _, status := range pool.statuses() {
+		if !status.Up {
+			t.Errorf("expected %s to be up", status.Endpoint)
+		}
+	}
+}
+
+// netTimeoutError is a net/http client error reporting a timeout
+type netTimeoutError struct{}
+
+func (netTimeoutError) Error() string { return "Client.Timeout exceeded" }
+func (netTimeoutError) Timeout() bool { return true }
+
+func TestGD2PoolCallTimeout(t *testing.T) {
+	pool := newGD2Pool(&conf.GConfig{Glusterd2Endpoint: "http://gd2-a:24007", Timeout: 30})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	err := pool.call(ctx, "peers", func(client *restclient.Client) error {
+		return netTimeoutError{}
+	})
+	tErr, ok := err.(*TimeoutError)
+	if !ok {
+		t.Fatalf("expected a timeout error, got %v", err)
+	}
+	// the timeout applied is bounded by the context deadline
+	if tErr.Timeout <= 0 || tErr.Timeout > time.Second {
+		t.Errorf("expected the timeout of the context, got %s", tErr.Timeout)
+	}
+}
@@ -0,0 +1,120 @@
+package glusterutils
+
+import (
+	"context"
+	"encoding/xml"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// geoRepTimeLayout is the layout of the timestamps in the
+	// geo-replication status, 'N/A' if not available
+	geoRepTimeLayout = "2006-01-02 15:04:05"
+	// geoRepNoSessions is the error reported by glusterd
+	// when there is no geo-replication session
+	geoRepNoSessions = "No active geo-replication sessions"
+)
+
+// parseGeoRepTime returns the unix time of a geo-replication
+// status timestamp, 0 if not available
+func parseGeoRepTime(value string, loc *time.Location) int64 {
+	t, err := time.ParseInLocation(geoRepTimeLayout, strings.TrimSpace(value), loc)
+	if err != nil {
+		return 0
+	}
+	return t.Unix()
+}
+
+// parseGeoRepCount returns the value of a geo-replication
+// status counter, -1 if not available
+func parseGeoRepCount(value string) int64 {
+	count, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil {
+		return -1
+	}
+	return count
+}
+
+// parseGeoRepSlave splits the slave url, '[ssh://][<user>@]<host>::<volume>'
+// or '<master volume id>:ssh://<host>::<volume>:<slave volume id>' as
+// reported in the session
+func parseGeoRepSlave(slave string) (user, host, volume string) {
+	if idx := strings.Index(slave, "ssh://"); idx >= 0 {
+		slave = slave[idx+len("ssh://"):]
+	}
+	hostVol := strings.SplitN(slave, "::", 2)
+	host = hostVol[0]
+	if idx := strings.Index(host, "@"); idx >= 0 {
+		user, host = host[:idx], host[idx+1:]
+	}
+	if len(hostVol) == 2 {
+		volume = strings.SplitN(hostVol[1], ":", 2)[0]
+	}
+	return user, host, volume
+}
+
+// GeoRepStatus returns the status of the geo-replication sessions (GD1)
+func (g *GD1) GeoRepStatus(ctx context.Context) ([]GeoRepSession, error) {
+	out, err := g.execGluster(ctx, "volume", "geo-replication", "status", "detail")
+	if err != nil {
+		// glusterd fails if there is no session
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			if strings.Contains(string(out), geoRepNoSessions) || strings.Contains(string(exitErr.Stderr), geoRepNoSessions) {
+				return []GeoRepSession{}, nil
+			}
+		}
+		return nil, err
+	}
+
+	var status gd1GeoRepStatus
+	err = xml.Unmarshal(out, &status)
+	if err != nil {
+		return nil, err
+	}
+	if status.OpRet != 0 && strings.Contains(status.OpErr, geoRepNoSessions) {
+		return []GeoRepSession{}, nil
+	}
+
+	var sessions []GeoRepSession
+	for _, vol := range status.Volumes {
+		for _, gd1session := range vol.Sessions {
+			session := GeoRepSession{MasterVolume: vol.Name}
+			slave := gd1session.SessionSlave
+			if len(gd1session.Pairs) > 0 {
+				slave = gd1session.Pairs[0].Slave
+				session.SlaveUser = gd1session.Pairs[0].SlaveUser
+			}
+			slaveUser, slaveHost, slaveVolume := parseGeoRepSlave(slave)
+			if session.SlaveUser == "" {
+				session.SlaveUser = slaveUser
+			}
+			session.SlaveHost = slaveHost
+			session.SlaveVolume = slaveVolume
+
+			session.Workers = make([]GeoRepWorker, len(gd1session.Pairs))
+			for pidx, pair := range gd1session.Pairs {
+				session.Workers[pidx] = GeoRepWorker{
+					MasterNode:               pair.MasterNode,
+					MasterPeerID:             pair.MasterNodeUUID,
+					MasterBrick:              pair.MasterBrick,
+					SlaveNode:                pair.SlaveNode,
+					Status:                   pair.Status,
+					CrawlStatus:              pair.CrawlStatus,
+					LastSynced:               parseGeoRepTime(pair.LastSynced, time.Local),
+					CheckpointTime:           parseGeoRepTime(pair.CheckpointTime, time.Local),
+					CheckpointCompleted:      pair.CheckpointCompleted == "Yes",
+					CheckpointCompletionTime: parseGeoRepTime(pair.CheckpointCompletionTime, time.Local),
+					PendingEntryOps:          parseGeoRepCount(pair.Entry),
+					PendingDataOps:           parseGeoRepCount(pair.Data),
+					PendingMetaOps:           parseGeoRepCount(pair.Meta),
+					Failures:                 parseGeoRepCount(pair.Failures),
+				}
+			}
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions, nil
+}
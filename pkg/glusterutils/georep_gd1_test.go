@@ -0,0 +1,120 @@
+package glusterutils
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGD1GeoRepStatus(t *testing.T) {
+	localTime := func(value string) int64 {
+		ts, err := time.ParseInLocation(geoRepTimeLayout, value, time.Local)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ts.Unix()
+	}
+	// counters and timestamps are 'N/A' for the workers which are not active
+	inactive := func(host, peerID, slaveNode, status string) GeoRepWorker {
+		return GeoRepWorker{MasterNode: host, MasterPeerID: peerID, MasterBrick: "/bricks/rep3",
+			SlaveNode: slaveNode, Status: status, CrawlStatus: "N/A",
+			PendingEntryOps: -1, PendingDataOps: -1, PendingMetaOps: -1, Failures: -1}
+	}
+	tests := []struct {
+		scenario string
+		expected []GeoRepSession
+	}{
+		{
+			scenario: "georep",
+			expected: []GeoRepSession{
+				{
+					MasterVolume: "rep3",
+					SlaveUser:    "root",
+					SlaveHost:    "drhost1",
+					SlaveVolume:  "rep3-dr",
+					Workers: []GeoRepWorker{
+						{
+							MasterNode:               "server1",
+							MasterPeerID:             peerID1,
+							MasterBrick:              "/bricks/rep3",
+							SlaveNode:                "drhost1",
+							Status:                   "Active",
+							CrawlStatus:              "Changelog Crawl",
+							LastSynced:               localTime("2026-10-17 10:30:35"),
+							CheckpointTime:           localTime("2026-10-17 09:00:00"),
+							CheckpointCompleted:      true,
+							CheckpointCompletionTime: localTime("2026-10-17 09:12:41"),
+							PendingEntryOps:          3,
+							PendingDataOps:           12,
+							PendingMetaOps:           1,
+							Failures:                 0,
+						},
+						inactive("server2", peerID2, "drhost2", "Passive"),
+						inactive("server3", peerID3, "N/A", "Faulty"),
+					},
+				},
+				{
+					MasterVolume: "rep3",
+					SlaveUser:    "geoaccount",
+					SlaveHost:    "backup1",
+					SlaveVolume:  "rep3-backup",
+					Workers: []GeoRepWorker{
+						{
+							MasterNode:   "server1",
+							MasterPeerID: peerID1,
+							MasterBrick:  "/bricks/rep3",
+							SlaveNode:    "backup1",
+							Status:       "Initializing...",
+							CrawlStatus:  "Hybrid Crawl",
+							Failures:     2,
+						},
+					},
+				},
+			},
+		},
+		{
+			scenario: "georep-no-sessions",
+			expected: []GeoRepSession{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.scenario, func(t *testing.T) {
+			g, cleanup := newFakeGD1(tt.scenario)
+			defer cleanup()
+			sessions, err := g.GeoRepStatus(context.Background())
+			if err != nil {
+				t.Fatalf("GeoRepStatus failed: %s", err)
+			}
+			if !reflect.DeepEqual(sessions, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, sessions)
+			}
+		})
+	}
+}
+
+func TestParseGeoRepSlave(t *testing.T) {
+	tests := []struct {
+		slave  string
+		user   string
+		host   string
+		volume string
+	}{
+		{slave: "ssh://drhost1::rep3-dr", host: "drhost1", volume: "rep3-dr"},
+		{slave: "ssh://geoaccount@backup1::rep3-backup", user: "geoaccount", host: "backup1", volume: "rep3-backup"},
+		{slave: "backup1::rep3-backup", host: "backup1", volume: "rep3-backup"},
+		{
+			slave:  "6b1f1e3a-1c2d-4e5f-8a9b-0c1d2e3f4a5b:ssh://drhost1::rep3-dr:1a2b3c4d-5e6f-4a7b-8c9d-0e1f2a3b4c5d",
+			host:   "drhost1",
+			volume: "rep3-dr",
+		},
+	}
+	for _, tt := range tests {
+		user, host, volume := parseGeoRepSlave(tt.slave)
+		if user != tt.user || host != tt.host || volume != tt.volume {
+			t.Errorf("%s: expected (%q, %q, %q), got (%q, %q, %q)", tt.slave,
+				tt.user, tt.host, tt.volume, user, host, volume)
+		}
+	}
+}
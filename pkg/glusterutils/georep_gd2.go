@@ -0,0 +1,65 @@
+package glusterutils
+
+import (
+	"context"
+	"time"
+
+	"github.com/gluster/glusterd2/pkg/restclient"
+	georepapi "github.com/gluster/glusterd2/plugins/georeplication/api"
+)
+
+// GeoRepStatus returns the status of the geo-replication sessions (GD2)
+func (g *GD2) GeoRepStatus(ctx context.Context) ([]GeoRepSession, error) {
+	// The list of sessions has no worker details, those are
+	// fetched individually for each session
+	var sessionlist []georepapi.GeorepSession
+	err := g.pool.call(ctx, "GeorepStatus", func(client *restclient.Client) (err error) {
+		sessionlist, err = client.GeorepStatus("", "")
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	sessions := make([]GeoRepSession, len(sessionlist))
+	for sidx, listed := range sessionlist {
+		var details []georepapi.GeorepSession
+		err := g.pool.call(ctx, "GeorepStatus", func(client *restclient.Client) (err error) {
+			details, err = client.GeorepStatus(listed.MasterID.String(), listed.RemoteID.String())
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		session := GeoRepSession{
+			MasterVolume: listed.MasterVol,
+			SlaveUser:    listed.RemoteUser,
+			SlaveVolume:  listed.RemoteVol,
+		}
+		if len(listed.RemoteHosts) > 0 {
+			session.SlaveHost = listed.RemoteHosts[0].Hostname
+		}
+		for _, detail := range details {
+			for _, worker := range detail.Workers {
+				// glusterd2 reports the UTC timestamps too
+				session.Workers = append(session.Workers, GeoRepWorker{
+					MasterNode:               worker.MasterPeerHostname,
+					MasterPeerID:             worker.MasterPeerID,
+					MasterBrick:              worker.MasterBrickPath,
+					SlaveNode:                worker.RemoteNode,
+					Status:                   worker.Status,
+					CrawlStatus:              worker.CrawlStatus,
+					LastSynced:               parseGeoRepTime(worker.LastSyncedTimeUTC, time.UTC),
+					CheckpointTime:           parseGeoRepTime(worker.CheckpointTimeUTC, time.UTC),
+					CheckpointCompleted:      worker.CheckpointCompleted == "Yes",
+					CheckpointCompletionTime: parseGeoRepTime(worker.CheckpointCompletedTimeUTC, time.UTC),
+					PendingEntryOps:          parseGeoRepCount(worker.EntryOps),
+					PendingDataOps:           parseGeoRepCount(worker.DataOps),
+					PendingMetaOps:           parseGeoRepCount(worker.MetaOps),
+					Failures:                 parseGeoRepCount(worker.FailedOps),
+				})
+			}
+		}
+		sessions[sidx] = session
+	}
+	return sessions, nil
+}
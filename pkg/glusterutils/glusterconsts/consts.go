@@ -5,6 +5,12 @@ const (
 	VolumeTypeDistReplicate = "Distributed Replicate"
 	// VolumeTypeDistDisperse represents Gluster distributed disperse volume
 	VolumeTypeDistDisperse = "Distributed Disperse"
+	// VolumeTypeDistReplicateGD1 represents distributed replicate volume
+	// as reported by the glusterd CLI
+	VolumeTypeDistReplicateGD1 = "Distributed-Replicate"
+	// VolumeTypeDistDisperseGD1 represents distributed disperse volume
+	// as reported by the glusterd CLI
+	VolumeTypeDistDisperseGD1 = "Distributed-Disperse"
 	// VolumeTypeReplicate represents Gluster replicate volume
 	VolumeTypeReplicate = "Replicate"
 	// VolumeTypeDisperse represents Gluster disperse volume
@@ -47,6 +53,61 @@ const (
 	// LatencyMeasurementGD2 represents volume option for latency measurement
 	LatencyMeasurementGD2 = "debug/io-stats.latency-measurement"
 
+	// QuotaGD1 represents volume option to enable the usage quota
+	QuotaGD1 = "features.quota"
+	// QuotaGD2 represents volume option to enable the usage quota, of
+	// the marker translator as set by 'features.quota' in glusterd
+	QuotaGD2 = "features/marker.quota"
+	// InodeQuotaGD1 represents volume option to enable the object quota
+	InodeQuotaGD1 = "features.inode-quota"
+
+	// BitrotGD1 represents volume option to enable bitrot detection
+	BitrotGD1 = "features.bitrot"
+	// BitrotGD2 represents volume option to enable bitrot detection
+	BitrotGD2 = "features/bit-rot.bitrot"
+
+	// SelfHealDaemonGD1 represents volume option to enable the self-heal daemon
+	SelfHealDaemonGD1 = "cluster.self-heal-daemon"
+	// SelfHealDaemonGD2 represents volume option to enable the self-heal daemon
+	SelfHealDaemonGD2 = "cluster/replicate.self-heal-daemon"
+	// NFSDisableGD1 represents volume option to disable the gluster NFS server
+	NFSDisableGD1 = "nfs.disable"
+	// USSGD1 represents volume option to enable the snapshot daemon
+	USSGD1 = "features.uss"
+	// StatedumpPathGD1 represents volume option for the statedump directory
+	StatedumpPathGD1 = "server.statedump-path"
+	// DefaultStatedumpPath is the statedump directory, when not configured
+	DefaultStatedumpPath = "/var/run/gluster"
+
+	// ProcessRoleGlusterd represents the glusterd process
+	ProcessRoleGlusterd = "glusterd"
+	// ProcessRoleGlusterd2 represents the glusterd2 process
+	ProcessRoleGlusterd2 = "glusterd2"
+	// ProcessRoleBrick represents a brick process
+	ProcessRoleBrick = "brick"
+	// ProcessRoleSelfHeal represents the self-heal daemon
+	ProcessRoleSelfHeal = "shd"
+	// ProcessRoleQuotad represents the quota daemon
+	ProcessRoleQuotad = "quotad"
+	// ProcessRoleBitd represents the bitrot daemon
+	ProcessRoleBitd = "bitd"
+	// ProcessRoleScrubd represents the bitrot scrubber daemon
+	ProcessRoleScrubd = "scrubd"
+	// ProcessRoleNFS represents the gluster NFS server
+	ProcessRoleNFS = "nfs"
+	// ProcessRoleSnapd represents the snapshot daemon of a volume
+	ProcessRoleSnapd = "snapd"
+	// ProcessRoleRebalance represents the rebalance process of a volume
+	ProcessRoleRebalance = "rebalance"
+	// ProcessRoleGsyncd represents a geo-replication process
+	ProcessRoleGsyncd = "gsyncd"
+	// ProcessRoleEventsd represents the gluster events daemon
+	ProcessRoleEventsd = "glustereventsd"
+	// ProcessRoleFuse represents a FUSE client mount
+	ProcessRoleFuse = "fuse"
+	// ProcessRoleOther represents the other glusterfs processes
+	ProcessRoleOther = "other"
+
 	// DefaultGlusterClusterID provides the default clusnter ID
 	DefaultGlusterClusterID = "default"
 )
@@ -0,0 +1,117 @@
+// Package glustertest replays recorded gluster CLI outputs and glusterd2
+// REST responses, so that the backends can be tested without a running
+// gluster cluster.
+//
+// A fixture is a capture directory (see the 'capture' package), recorded
+// with the `--record-dir` option of the exporter or written by hand. The
+// test binary acts as the fake `gluster` binary, see 'RunIfFakeGluster'.
+package glustertest
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gluster/gluster-prometheus/pkg/glusterutils/capture"
+)
+
+// EnvFixtureDir environment variable makes the test binary act as
+// the `gluster` binary, replaying the fixture in the given directory
+const EnvFixtureDir = "GLUSTERTEST_FIXTURE_DIR"
+
+func copyFile(dir, name string, w io.Writer) error {
+	if name == "" {
+		return nil
+	}
+	data, err := ioutil.ReadFile(filepath.Join(filepath.Clean(dir), name))
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// Replay writes the recorded outputs of the command to stdout and
+// stderr, and returns its exit code
+func Replay(dir string, cmd string, args []string, stdout, stderr io.Writer) int {
+	manifest, err := capture.LoadManifest(dir)
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "glustertest: %s\n", err)
+		return 1
+	}
+	recorded, ok := manifest.Find(cmd, args)
+	if !ok {
+		_, _ = fmt.Fprintf(stderr, "glustertest: no fixture for: %s %s\n", cmd, strings.Join(args, " "))
+		return 1
+	}
+	if err := copyFile(dir, recorded.Stdout, stdout); err != nil {
+		_, _ = fmt.Fprintf(stderr, "glustertest: %s\n", err)
+		return 1
+	}
+	if err := copyFile(dir, recorded.Stderr, stderr); err != nil {
+		_, _ = fmt.Fprintf(stderr, "glustertest: %s\n", err)
+		return 1
+	}
+	return recorded.ExitCode
+}
+
+// RunIfFakeGluster makes the test binary act as the `gluster` binary when
+// it is run by the code under test. It should be called from TestMain
+// before running the tests
+func RunIfFakeGluster() {
+	dir := os.Getenv(EnvFixtureDir)
+	if dir == "" {
+		return
+	}
+	os.Exit(Replay(dir, capture.DefaultCmd, os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// FakeGluster returns the path of the fake `gluster` binary, replaying the
+// given fixture directory, and a func restoring the environment. The
+// environment is changed for the whole test binary, so the tests using
+// it should not run in parallel
+func FakeGluster(dir string) (string, func()) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		absDir = dir
+	}
+	prevDir, wasSet := os.LookupEnv(EnvFixtureDir)
+	_ = os.Setenv(EnvFixtureDir, absDir)
+	return os.Args[0], func() {
+		if wasSet {
+			_ = os.Setenv(EnvFixtureDir, prevDir)
+		} else {
+			_ = os.Unsetenv(EnvFixtureDir)
+		}
+	}
+}
+
+// FakeGlusterd2 starts a fake glusterd2 replaying the REST responses
+// recorded in the given fixture directory, and returns its endpoint and
+// a func stopping the server
+func FakeGlusterd2(t testing.TB, dir string) (string, func()) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		manifest, err := capture.LoadManifest(dir)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		recorded, ok := manifest.Find(capture.Glusterd2Cmd, []string{r.Method, r.URL.RequestURI()})
+		if !ok {
+			http.Error(w, "glustertest: no fixture for: "+r.Method+" "+r.URL.RequestURI(), http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(recorded.ExitCode)
+		if err := copyFile(dir, recorded.Stdout, w); err != nil {
+			t.Errorf("glustertest: %s", err)
+		}
+	}))
+	return server.URL, server.Close
+}
@@ -0,0 +1,88 @@
+package glustertest
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gluster/gluster-prometheus/pkg/glusterutils/capture"
+)
+
+func TestReplay(t *testing.T) {
+	dir := "../testdata/replicate"
+	tests := []struct {
+		args     []string
+		exitCode int
+		stdout   string
+	}{
+		{
+			args:   []string{"pool", "list", "--xml"},
+			stdout: "<peerStatus>",
+		},
+		{
+			args:   []string{"volume", "status", "rep3", "detail", "--xml", "--remote-host=server1"},
+			stdout: "<volName>rep3</volName>",
+		},
+		{
+			args:     []string{"volume", "status", "unknown", "detail", "--xml"},
+			exitCode: 1,
+		},
+	}
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		exitCode := Replay(dir, capture.DefaultCmd, tt.args, &stdout, &stderr)
+		if exitCode != tt.exitCode {
+			t.Errorf("%v: expected exit code %d, got %d (%s)", tt.args, tt.exitCode, exitCode, stderr.String())
+		}
+		if !strings.Contains(stdout.String(), tt.stdout) {
+			t.Errorf("%v: expected %q in the output", tt.args, tt.stdout)
+		}
+	}
+}
+
+func TestFakeGlusterd2(t *testing.T) {
+	dir, err := ioutil.TempDir("", "glustertest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	manifest := `{"commands": [{"cmd": "glusterd2", "args": ["GET", "/v1/peers"], "exit-code": 200, "stdout": "peers.json"}]}`
+	if err := ioutil.WriteFile(filepath.Join(dir, capture.ManifestFile), []byte(manifest), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "peers.json"), []byte(`[{"id": "peer1"}]`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	endpoint, stop := FakeGlusterd2(t, dir)
+	defer stop()
+
+	tests := []struct {
+		path       string
+		statusCode int
+		body       string
+	}{
+		{path: "/v1/peers", statusCode: http.StatusOK, body: `[{"id": "peer1"}]`},
+		{path: "/v1/volumes", statusCode: http.StatusNotFound, body: "no fixture"},
+	}
+	for _, tt := range tests {
+		resp, err := http.Get(endpoint + tt.path)
+		if err != nil {
+			t.Fatalf("%s: %s", tt.path, err)
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			t.Fatalf("%s: %s", tt.path, err)
+		}
+		if resp.StatusCode != tt.statusCode {
+			t.Errorf("%s: expected status %d, got %d", tt.path, tt.statusCode, resp.StatusCode)
+		}
+		if !strings.Contains(string(body), tt.body) {
+			t.Errorf("%s: expected %q in the response, got %q", tt.path, tt.body, body)
+		}
+	}
+}
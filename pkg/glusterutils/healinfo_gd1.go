@@ -1,15 +1,16 @@
 package glusterutils
 
 import (
+	"context"
 	"encoding/xml"
 	"fmt"
 	"strconv"
 	"strings"
 )
 
-func (g *GD1) getHealDetails(cmd string) ([]HealEntry, error) {
+func (g *GD1) getHealDetails(ctx context.Context, cmd string) ([]HealEntry, error) {
 	args := strings.Fields(cmd)
-	out, err := g.execGluster(args...)
+	out, err := g.execGluster(ctx, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -20,28 +21,33 @@ func (g *GD1) getHealDetails(cmd string) ([]HealEntry, error) {
 	}
 	heals := make([]HealEntry, len(healop.Healentries))
 	for hidx, entry := range healop.Healentries {
+		hostPath := strings.SplitN(entry.Brickname, ":", 2)
+		if len(hostPath) != 2 {
+			return nil, fmt.Errorf("invalid brick name: %s", entry.Brickname)
+		}
+		heal := HealEntry{PeerID: entry.HostUUID, Hostname: hostPath[0],
+			Brick:          hostPath[1],
+			Connected:      entry.Connected,
+			NumHealEntries: -1}
+		// number of entries is '-' for the bricks which are not connected
 		if entry.Connected == "Connected" {
 			entries, err := strconv.ParseInt(entry.NumHealEntries, 10, 64)
 			if err != nil {
 				return nil, err
 			}
-			hostPath := strings.Split(entry.Brickname, ":")
-			heal := HealEntry{PeerID: entry.HostUUID, Hostname: hostPath[0],
-				Brick:          hostPath[1],
-				Connected:      entry.Connected,
-				NumHealEntries: entries}
-			heals[hidx] = heal
+			heal.NumHealEntries = entries
 		}
+		heals[hidx] = heal
 	}
 
 	return heals, nil
 }
 
 // HealInfo gets gluster vol heal info (GD1)
-func (g GD1) HealInfo(vol string) ([]HealEntry, error) {
+func (g GD1) HealInfo(ctx context.Context, vol string) ([]HealEntry, error) {
 	// Get the overall heal count
 	cmd := fmt.Sprintf("vol heal %s info --nolog", vol)
-	heals, err := g.getHealDetails(cmd)
+	heals, err := g.getHealDetails(ctx, cmd)
 	if err != nil {
 		return nil, err
 	}
@@ -50,12 +56,57 @@ func (g GD1) HealInfo(vol string) ([]HealEntry, error) {
 }
 
 // SplitBrainHealInfo gets gluster vol heal info (GD1)
-func (g GD1) SplitBrainHealInfo(vol string) ([]HealEntry, error) {
+func (g GD1) SplitBrainHealInfo(ctx context.Context, vol string) ([]HealEntry, error) {
 	cmd := fmt.Sprintf("vol heal %s info split-brain --nolog", vol)
-	splitBrainHeals, err := g.getHealDetails(cmd)
+	splitBrainHeals, err := g.getHealDetails(ctx, cmd)
 	if err != nil {
 		return nil, err
 	}
 
 	return splitBrainHeals, nil
 }
+
+// HealInfoSummary gets gluster vol heal info summary (GD1). The summary
+// has the counts of the entries in each state, gathered in a single crawl
+func (g GD1) HealInfoSummary(ctx context.Context, vol string) ([]HealSummary, error) {
+	out, err := g.execGluster(ctx, "vol", "heal", vol, "info", "summary", "--nolog")
+	if err != nil {
+		return nil, err
+	}
+	var healop healSummaryBricks
+	err = xml.Unmarshal(out, &healop)
+	if err != nil {
+		return nil, err
+	}
+	summaries := make([]HealSummary, len(healop.Healentries))
+	for hidx, entry := range healop.Healentries {
+		hostPath := strings.SplitN(entry.Brickname, ":", 2)
+		if len(hostPath) != 2 {
+			return nil, fmt.Errorf("invalid brick name: %s", entry.Brickname)
+		}
+		summary := HealSummary{PeerID: entry.HostUUID, Hostname: hostPath[0],
+			Brick:           hostPath[1],
+			Connected:       entry.Connected,
+			Total:           -1,
+			Pending:         -1,
+			SplitBrain:      -1,
+			PossiblyHealing: -1}
+		// the counts are '-' for the bricks which are not connected
+		if entry.Connected == "Connected" {
+			if summary.Total, err = strconv.ParseInt(entry.TotalEntries, 10, 64); err != nil {
+				return nil, err
+			}
+			if summary.Pending, err = strconv.ParseInt(entry.EntriesInHealPending, 10, 64); err != nil {
+				return nil, err
+			}
+			if summary.SplitBrain, err = strconv.ParseInt(entry.EntriesInSplitBrain, 10, 64); err != nil {
+				return nil, err
+			}
+			if summary.PossiblyHealing, err = strconv.ParseInt(entry.EntriesPossiblyHealing, 10, 64); err != nil {
+				return nil, err
+			}
+		}
+		summaries[hidx] = summary
+	}
+	return summaries, nil
+}
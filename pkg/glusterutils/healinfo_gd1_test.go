@@ -0,0 +1,112 @@
+package glusterutils
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestGD1HealInfo(t *testing.T) {
+	disconnected := "Transport endpoint is not connected"
+	tests := []struct {
+		scenario   string
+		volume     string
+		splitBrain bool
+		expected   []HealEntry
+	}{
+		{
+			scenario: "replicate",
+			volume:   "rep3",
+			expected: []HealEntry{
+				{PeerID: peerID1, Hostname: "server1", Brick: "/bricks/rep3", Connected: "Connected", NumHealEntries: 2},
+				{PeerID: peerID2, Hostname: "server2", Brick: "/bricks/rep3", Connected: "Connected", NumHealEntries: 0},
+				{PeerID: "-", Hostname: "server3", Brick: "/bricks/rep3", Connected: disconnected, NumHealEntries: -1},
+			},
+		},
+		{
+			scenario:   "replicate",
+			volume:     "rep3",
+			splitBrain: true,
+			expected: []HealEntry{
+				{PeerID: peerID1, Hostname: "server1", Brick: "/bricks/rep3", Connected: "Connected", NumHealEntries: 1},
+				{PeerID: peerID2, Hostname: "server2", Brick: "/bricks/rep3", Connected: "Connected", NumHealEntries: 1},
+				{PeerID: "-", Hostname: "server3", Brick: "/bricks/rep3", Connected: disconnected, NumHealEntries: -1},
+			},
+		},
+		{
+			scenario: "arbiter",
+			volume:   "arb",
+			expected: []HealEntry{
+				{PeerID: peerID1, Hostname: "server1", Brick: "/bricks/arb1", Connected: "Connected", NumHealEntries: 0},
+				{PeerID: peerID2, Hostname: "server2", Brick: "/bricks/arb1", Connected: "Connected", NumHealEntries: 0},
+				{PeerID: peerID3, Hostname: "server3", Brick: "/bricks/arb1-arbiter", Connected: "Connected", NumHealEntries: 0},
+				{PeerID: peerID1, Hostname: "server1", Brick: "/bricks/arb2", Connected: "Connected", NumHealEntries: 3},
+				{PeerID: peerID2, Hostname: "server2", Brick: "/bricks/arb2", Connected: "Connected", NumHealEntries: 1},
+				{PeerID: "-", Hostname: "server3", Brick: "/bricks/arb2-arbiter", Connected: disconnected, NumHealEntries: -1},
+			},
+		},
+		{
+			scenario: "disperse",
+			volume:   "ec",
+			expected: []HealEntry{
+				{PeerID: peerID1, Hostname: "server1", Brick: "/bricks/ec", Connected: "Connected", NumHealEntries: 2},
+				{PeerID: peerID2, Hostname: "server2", Brick: "/bricks/ec", Connected: "Connected", NumHealEntries: 0},
+				{PeerID: peerID3, Hostname: "server3", Brick: "/bricks/ec", Connected: "Connected", NumHealEntries: 1},
+			},
+		},
+		{
+			scenario: "distributed-disperse",
+			volume:   "dist-ec",
+			expected: []HealEntry{
+				{PeerID: peerID1, Hostname: "server1", Brick: "/bricks/dec1", Connected: "Connected", NumHealEntries: 0},
+				{PeerID: peerID2, Hostname: "server2", Brick: "/bricks/dec1", Connected: "Connected", NumHealEntries: 0},
+				{PeerID: peerID3, Hostname: "server3", Brick: "/bricks/dec1", Connected: "Connected", NumHealEntries: 0},
+				{PeerID: peerID1, Hostname: "server1", Brick: "/bricks/dec2", Connected: "Connected", NumHealEntries: 4},
+				{PeerID: peerID2, Hostname: "server2", Brick: "/bricks/dec2", Connected: "Connected", NumHealEntries: 4},
+				{PeerID: "-", Hostname: "server3", Brick: "/bricks/dec2", Connected: disconnected, NumHealEntries: -1},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		name := tt.scenario
+		if tt.splitBrain {
+			name += "/split-brain"
+		}
+		t.Run(name, func(t *testing.T) {
+			g, cleanup := newFakeGD1(tt.scenario)
+			defer cleanup()
+			healInfo := g.HealInfo
+			if tt.splitBrain {
+				healInfo = g.SplitBrainHealInfo
+			}
+			heals, err := healInfo(context.Background(), tt.volume)
+			if err != nil {
+				t.Fatalf("heal info failed: %s", err)
+			}
+			if !reflect.DeepEqual(heals, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, heals)
+			}
+		})
+	}
+}
+
+func TestGD1HealInfoSummary(t *testing.T) {
+	expected := []HealSummary{
+		{PeerID: peerID1, Hostname: "server1", Brick: "/bricks/rep3", Connected: "Connected",
+			Total: 2, Pending: 1, SplitBrain: 1, PossiblyHealing: 0},
+		{PeerID: peerID2, Hostname: "server2", Brick: "/bricks/rep3", Connected: "Connected",
+			Total: 1, Pending: 0, SplitBrain: 1, PossiblyHealing: 0},
+		{PeerID: "-", Hostname: "server3", Brick: "/bricks/rep3", Connected: "Transport endpoint is not connected",
+			Total: -1, Pending: -1, SplitBrain: -1, PossiblyHealing: -1},
+	}
+	g, cleanup := newFakeGD1("replicate")
+	defer cleanup()
+	summaries, err := g.HealInfoSummary(context.Background(), "rep3")
+	if err != nil {
+		t.Fatalf("HealInfoSummary failed: %s", err)
+	}
+	if !reflect.DeepEqual(summaries, expected) {
+		t.Errorf("expected %+v, got %+v", expected, summaries)
+	}
+}
@@ -1,14 +1,20 @@
 package glusterutils
 
-import "strings"
+import (
+	"context"
+	"strings"
+
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/restclient"
+)
 
 // HealInfo gets heal info from glusterd2 using rest api
-func (g GD2) HealInfo(vol string) ([]HealEntry, error) {
-	client, err := initRESTClient(g.config)
-	if err != nil {
-		return nil, err
-	}
-	healinfo, herr := client.SelfHealInfo(vol)
+func (g GD2) HealInfo(ctx context.Context, vol string) ([]HealEntry, error) {
+	var healinfo []api.BrickHealInfo
+	herr := g.pool.call(ctx, "SelfHealInfo", func(client *restclient.Client) (err error) {
+		healinfo, err = client.SelfHealInfo(vol)
+		return err
+	})
 	if herr != nil {
 		return nil, herr
 	}
@@ -25,12 +31,12 @@ func (g GD2) HealInfo(vol string) ([]HealEntry, error) {
 }
 
 // SplitBrainHealInfo gets heal info from glusterd2 using rest api
-func (g GD2) SplitBrainHealInfo(vol string) ([]HealEntry, error) {
-	client, err := initRESTClient(g.config)
-	if err != nil {
-		return nil, err
-	}
-	healinfo, herr := client.SelfHealInfo(vol, "split-brain-info")
+func (g GD2) SplitBrainHealInfo(ctx context.Context, vol string) ([]HealEntry, error) {
+	var healinfo []api.BrickHealInfo
+	herr := g.pool.call(ctx, "SelfHealInfo", func(client *restclient.Client) (err error) {
+		healinfo, err = client.SelfHealInfo(vol, "split-brain-info")
+		return err
+	})
 	if herr != nil {
 		return nil, herr
 	}
@@ -45,3 +51,33 @@ func (g GD2) SplitBrainHealInfo(vol string) ([]HealEntry, error) {
 	return brickheal, herr
 
 }
+
+// HealInfoSummary gets heal info summary from glusterd2 using rest api
+func (g GD2) HealInfoSummary(ctx context.Context, vol string) ([]HealSummary, error) {
+	var healinfo []api.BrickHealInfo
+	herr := g.pool.call(ctx, "SelfHealInfo", func(client *restclient.Client) (err error) {
+		healinfo, err = client.SelfHealInfo(vol, "info-summary")
+		return err
+	})
+	if herr != nil {
+		return nil, herr
+	}
+	// the counts are not set for the bricks which are not connected
+	count := func(value *int64) int64 {
+		if value == nil {
+			return -1
+		}
+		return *value
+	}
+	summaries := make([]HealSummary, len(healinfo))
+	for hidx, heal := range healinfo {
+		hostPath := strings.Split(heal.Name, ":")
+		summaries[hidx] = HealSummary{PeerID: heal.HostID, Hostname: hostPath[0],
+			Brick: hostPath[1], Connected: heal.Status,
+			Total:           count(heal.TotalEntries),
+			Pending:         count(heal.EntriesInHealPending),
+			SplitBrain:      count(heal.EntriesInSplitBrain),
+			PossiblyHealing: count(heal.EntriesPossiblyHealing)}
+	}
+	return summaries, nil
+}
@@ -0,0 +1,38 @@
+package glusterutils
+
+import (
+	"os"
+	"testing"
+
+	"github.com/gluster/gluster-prometheus/gluster-exporter/conf"
+	"github.com/gluster/gluster-prometheus/pkg/glusterutils/glustertest"
+)
+
+const (
+	peerID1 = "8f0ec4a2-2c44-4a8e-9a5b-1f4e5a9b0c01"
+	peerID2 = "3b6d2c1e-7f8a-4b9c-8d0e-2a3b4c5d6e02"
+	peerID3 = "5c7e3d2f-8a9b-4cad-9e1f-3b4c5d6e7f03"
+)
+
+func TestMain(m *testing.M) {
+	// the test binary is also the fake `gluster` binary
+	glustertest.RunIfFakeGluster()
+	os.Exit(m.Run())
+}
+
+// newFakeGD1 returns a GD1 backed by the fixture in 'testdata/<scenario>',
+// and the func to defer restoring the environment
+func newFakeGD1(scenario string) (*GD1, func()) {
+	return newFakeGD1FromDir("testdata/" + scenario)
+}
+
+// newFakeGD1FromDir returns a GD1 backed by the fixture in the directory,
+// and the func to defer restoring the environment
+func newFakeGD1FromDir(dir string) (*GD1, func()) {
+	glusterCmd, cleanup := glustertest.FakeGluster(dir)
+	return &GD1{config: &conf.GConfig{
+		GlusterCmd:      glusterCmd,
+		GlusterdWorkdir: dir,
+		Timeout:         10,
+	}}, cleanup
+}
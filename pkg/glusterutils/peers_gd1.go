@@ -1,6 +1,7 @@
 package glusterutils
 
 import (
+	"context"
 	"encoding/xml"
 )
 
@@ -18,10 +19,10 @@ type peersGlusterd1 struct {
 }
 
 // Peers returns the list of peers ( for GlusterD1 )
-func (g *GD1) Peers() ([]Peer, error) {
+func (g *GD1) Peers(ctx context.Context) ([]Peer, error) {
 	var gd1Peers peersGlusterd1
 	var peersgd1 []Peer
-	out, err := g.execGluster("pool", "list")
+	out, err := g.execGluster(ctx, "pool", "list")
 	if err != nil {
 		return peersgd1, err
 	}
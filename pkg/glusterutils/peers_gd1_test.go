@@ -0,0 +1,45 @@
+package glusterutils
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestGD1Peers(t *testing.T) {
+	expected := []Peer{
+		{ID: peerID2, PeerAddresses: []string{"server2"}, Online: true, Gd1State: 3},
+		{ID: peerID3, PeerAddresses: []string{"server3"}, Online: false, Gd1State: 3},
+		{ID: peerID1, PeerAddresses: []string{"localhost"}, Online: true, Gd1State: 0},
+	}
+
+	g, cleanup := newFakeGD1("replicate")
+	defer cleanup()
+	peers, err := g.Peers(context.Background())
+	if err != nil {
+		t.Fatalf("Peers failed: %s", err)
+	}
+	if !reflect.DeepEqual(peers, expected) {
+		t.Errorf("expected %+v, got %+v", expected, peers)
+	}
+}
+
+func TestGD1IsLeader(t *testing.T) {
+	g, cleanup := newFakeGD1("replicate")
+	defer cleanup()
+	peerID, err := g.LocalPeerID(context.Background())
+	if err != nil {
+		t.Fatalf("LocalPeerID failed: %s", err)
+	}
+	if peerID != peerID1 {
+		t.Errorf("expected local peer %s, got %s", peerID1, peerID)
+	}
+	// the local peer has the greatest ID among the online peers
+	leader, err := g.IsLeader(context.Background())
+	if err != nil {
+		t.Fatalf("IsLeader failed: %s", err)
+	}
+	if !leader {
+		t.Errorf("expected the local peer to be the leader")
+	}
+}
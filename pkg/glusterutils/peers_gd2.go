@@ -1,7 +1,10 @@
 package glusterutils
 
 import (
+	"context"
+
 	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/restclient"
 )
 
 var (
@@ -9,13 +12,12 @@ var (
 )
 
 // Peers returns the list of peers ( for GlusterD2 )
-func (g *GD2) Peers() ([]Peer, error) {
+func (g *GD2) Peers(ctx context.Context) ([]Peer, error) {
 	var peersgd2 []Peer
-	client, err := initRESTClient(g.config)
-	if err != nil {
-		return nil, err
-	}
-	peers, err = client.Peers()
+	err := g.pool.call(ctx, "Peers", func(client *restclient.Client) (err error) {
+		peers, err = client.Peers()
+		return err
+	})
 	if err != nil {
 		return peersgd2, err
 	}
@@ -0,0 +1,139 @@
+package glusterutils
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/gluster/gluster-prometheus/pkg/glusterutils/glusterconsts"
+)
+
+// GlusterProcess describes a gluster process, classified by its
+// command line. Volume and BrickPath are empty if not applicable
+// to the role, like for glusterd
+type GlusterProcess struct {
+	Name      string
+	Role      string
+	Volume    string
+	BrickPath string
+}
+
+// daemonRoles are the roles of the glusterfs daemons, by their
+// volfile id, which is also the directory of their pid file under
+// '/var/run/gluster'
+var daemonRoles = map[string]string{
+	"glustershd": glusterconsts.ProcessRoleSelfHeal,
+	"quotad":     glusterconsts.ProcessRoleQuotad,
+	"bitd":       glusterconsts.ProcessRoleBitd,
+	"scrub":      glusterconsts.ProcessRoleScrubd,
+	"nfs":        glusterconsts.ProcessRoleNFS,
+}
+
+// volumeDaemonRoles are the roles of the glusterfs daemons run for a
+// volume, by the prefix of their '<prefix>/<volume>' volfile id
+var volumeDaemonRoles = map[string]string{
+	"shd":       glusterconsts.ProcessRoleSelfHeal,
+	"snapd":     glusterconsts.ProcessRoleSnapd,
+	"rebalance": glusterconsts.ProcessRoleRebalance,
+}
+
+// ArgValue returns the value of the option, given either
+// as '--opt value' or as '--opt=value'
+func ArgValue(args []string, opt string) string {
+	for idx, arg := range args {
+		if arg == opt && idx+1 < len(args) {
+			return args[idx+1]
+		}
+		if strings.HasPrefix(arg, opt+"=") {
+			return strings.TrimPrefix(arg, opt+"=")
+		}
+	}
+	return ""
+}
+
+// ClassifyProcess returns the gluster process run by the command line,
+// false if it is not a gluster process. The processes are matched by
+// their arguments instead of their command name, since all the
+// glusterfs daemons and the python daemons share their command names
+func ClassifyProcess(args []string) (GlusterProcess, bool) {
+	if len(args) == 0 {
+		return GlusterProcess{}, false
+	}
+	name := filepath.Base(args[0])
+	switch name {
+	case "glusterd":
+		return GlusterProcess{Name: name, Role: glusterconsts.ProcessRoleGlusterd}, true
+	case "glusterd2":
+		return GlusterProcess{Name: name, Role: glusterconsts.ProcessRoleGlusterd2}, true
+	case "glusterfs", "glusterfsd":
+		return classifyGlusterfs(name, args), true
+	}
+
+	// the python daemons run as '<python> <script> <args>'
+	if strings.HasPrefix(name, "python") && len(args) > 1 {
+		args = args[1:]
+	}
+	switch strings.TrimSuffix(filepath.Base(args[0]), ".py") {
+	case "gsyncd":
+		return classifyGsyncd(args), true
+	case "glustereventsd":
+		return GlusterProcess{Name: "glustereventsd", Role: glusterconsts.ProcessRoleEventsd}, true
+	}
+	return GlusterProcess{}, false
+}
+
+// classifyGlusterfs returns the role of a glusterfs process, all the
+// bricks, the daemons and the FUSE mounts run the same program
+func classifyGlusterfs(name string, args []string) GlusterProcess {
+	p := GlusterProcess{Name: name, Role: glusterconsts.ProcessRoleOther}
+	volfileID := ArgValue(args, "--volfile-id")
+
+	if brickPath := ArgValue(args, "--brick-name"); brickPath != "" {
+		// the brick volfile id is '<volume>.<host>.<brick path>'
+		p.Role = glusterconsts.ProcessRoleBrick
+		p.Volume = strings.Split(volfileID, ".")[0]
+		p.BrickPath = brickPath
+		return p
+	}
+
+	if parts := strings.SplitN(volfileID, "/", 2); len(parts) == 2 {
+		if parts[0] == "gluster" {
+			if role, ok := daemonRoles[parts[1]]; ok {
+				p.Role = role
+				return p
+			}
+		} else if role, ok := volumeDaemonRoles[parts[0]]; ok {
+			p.Role = role
+			p.Volume = parts[1]
+			return p
+		}
+	}
+
+	// the daemons started by older glusterd versions are known
+	// only by their pid file, '/var/run/gluster/<daemon>/<daemon>.pid'
+	if pidFile := ArgValue(args, "-p"); pidFile != "" {
+		if role, ok := daemonRoles[filepath.Base(filepath.Dir(pidFile))]; ok {
+			p.Role = role
+			return p
+		}
+	}
+
+	// the FUSE mounts are the remaining processes with a volume, which
+	// is given with a leading '/' by mount.glusterfs
+	if volfileID != "" {
+		p.Role = glusterconsts.ProcessRoleFuse
+		p.Volume = strings.TrimPrefix(volfileID, "/")
+	}
+	return p
+}
+
+// classifyGsyncd returns the geo-replication process, the monitor and
+// the workers run as 'gsyncd <command> <master volume> <slave>', the
+// workers with the path of their brick
+func classifyGsyncd(args []string) GlusterProcess {
+	p := GlusterProcess{Name: "gsyncd", Role: glusterconsts.ProcessRoleGsyncd}
+	if len(args) > 2 && !strings.HasPrefix(args[1], "-") && !strings.HasPrefix(args[2], "-") {
+		p.Volume = args[2]
+	}
+	p.BrickPath = ArgValue(args, "--local-path")
+	return p
+}
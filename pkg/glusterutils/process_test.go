@@ -0,0 +1,77 @@
+package glusterutils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gluster/gluster-prometheus/pkg/glusterutils/glusterconsts"
+)
+
+func TestClassifyProcess(t *testing.T) {
+	tests := []struct {
+		cmdline  string
+		expected GlusterProcess
+	}{
+		{
+			cmdline:  "/usr/sbin/glusterd -p /var/run/glusterd.pid --log-level INFO",
+			expected: GlusterProcess{Name: "glusterd", Role: glusterconsts.ProcessRoleGlusterd},
+		},
+		{
+			cmdline: "/usr/sbin/glusterfsd -s server1 --volfile-id rep3.server1.bricks-rep3 " +
+				"-p /var/run/gluster/vols/rep3/server1-bricks-rep3.pid --brick-name /bricks/rep3",
+			expected: GlusterProcess{Name: "glusterfsd", Role: glusterconsts.ProcessRoleBrick,
+				Volume: "rep3", BrickPath: "/bricks/rep3"},
+		},
+		{
+			cmdline: "/usr/sbin/glusterfs -s localhost --volfile-id gluster/glustershd " +
+				"-p /var/run/gluster/glustershd/glustershd.pid -l /var/log/glusterfs/glustershd.log",
+			expected: GlusterProcess{Name: "glusterfs", Role: glusterconsts.ProcessRoleSelfHeal},
+		},
+		{
+			// the self-heal daemon of a volume, gluster 7 and later
+			cmdline: "/usr/sbin/glusterfs -s localhost --volfile-id shd/rep3 " +
+				"-p /var/run/gluster/shd/rep3/rep3-shd.pid",
+			expected: GlusterProcess{Name: "glusterfs", Role: glusterconsts.ProcessRoleSelfHeal, Volume: "rep3"},
+		},
+		{
+			// known only by the pid file
+			cmdline:  "/usr/sbin/glusterfs -s localhost -p /var/run/gluster/quotad/quotad.pid",
+			expected: GlusterProcess{Name: "glusterfs", Role: glusterconsts.ProcessRoleQuotad},
+		},
+		{
+			cmdline:  "/usr/sbin/glusterfs -s localhost --volfile-id gluster/scrub -p /var/run/gluster/scrub/scrub.pid",
+			expected: GlusterProcess{Name: "glusterfs", Role: glusterconsts.ProcessRoleScrubd},
+		},
+		{
+			cmdline: "/usr/sbin/glusterfsd -s localhost --volfile-id snapd/rep3 " +
+				"-p /var/run/gluster/vols/rep3/rep3-snapd.pid",
+			expected: GlusterProcess{Name: "glusterfsd", Role: glusterconsts.ProcessRoleSnapd, Volume: "rep3"},
+		},
+		{
+			cmdline:  "/usr/sbin/glusterfs --process-name fuse --volfile-server=server1 --volfile-id=/rep3 /mnt/rep3",
+			expected: GlusterProcess{Name: "glusterfs", Role: glusterconsts.ProcessRoleFuse, Volume: "rep3"},
+		},
+		{
+			cmdline: "/usr/bin/python3 /usr/libexec/glusterfs/python/syncdaemon/gsyncd.py worker rep3 " +
+				"geoaccount@slave1::rep3-slave --feedback-fd 15 --local-path /bricks/rep3 --local-node server1",
+			expected: GlusterProcess{Name: "gsyncd", Role: glusterconsts.ProcessRoleGsyncd,
+				Volume: "rep3", BrickPath: "/bricks/rep3"},
+		},
+		{
+			cmdline:  "/usr/bin/python3 /usr/sbin/glustereventsd --pid-file /var/run/glustereventsd.pid",
+			expected: GlusterProcess{Name: "glustereventsd", Role: glusterconsts.ProcessRoleEventsd},
+		},
+	}
+	for _, tt := range tests {
+		p, ok := ClassifyProcess(strings.Fields(tt.cmdline))
+		if !ok || p != tt.expected {
+			t.Errorf("%q: expected %+v, got %+v (%v)", tt.cmdline, tt.expected, p, ok)
+		}
+	}
+
+	for _, cmdline := range []string{"/usr/bin/python3 /usr/bin/yum", "/usr/sbin/sshd -D"} {
+		if p, ok := ClassifyProcess(strings.Fields(cmdline)); ok {
+			t.Errorf("%q: expected not a gluster process, got %+v", cmdline, p)
+		}
+	}
+}
@@ -1,13 +1,14 @@
 package glusterutils
 
 import (
+	"context"
 	"encoding/xml"
 )
 
 // VolumeProfileInfo returns profile info details for the volume
-func (g *GD1) VolumeProfileInfo(vol string) ([]ProfileInfo, error) {
+func (g *GD1) VolumeProfileInfo(ctx context.Context, vol string) ([]ProfileInfo, error) {
 	// Run Gluster volume profile <volname> info
-	out, err := g.execGluster("volume", "profile", vol, "info")
+	out, err := g.execGluster(ctx, "volume", "profile", vol, "info")
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,45 @@
+package glusterutils
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestGD1VolumeProfileInfo(t *testing.T) {
+	expected := []ProfileInfo{
+		{
+			BrickName:   "server1:/bricks/rep3",
+			Duration:    3600,
+			TotalReads:  49152,
+			TotalWrites: 163840,
+			FopStats: []FopStat{
+				{Name: "WRITE", Hits: 40, AvgLatency: 103.25, MinLatency: 41, MaxLatency: 512},
+				{Name: "LOOKUP", Hits: 7, AvgLatency: 220.57, MinLatency: 95, MaxLatency: 401},
+			},
+			DurationInt:    10,
+			TotalReadsInt:  0,
+			TotalWritesInt: 16384,
+			FopStatsInt: []FopStat{
+				{Name: "WRITE", Hits: 4, AvgLatency: 98, MinLatency: 60, MaxLatency: 140},
+			},
+		},
+		{
+			BrickName:      "server2:/bricks/rep3",
+			Duration:       3600,
+			TotalWrites:    163840,
+			DurationInt:    10,
+			TotalWritesInt: 0,
+		},
+	}
+
+	g, cleanup := newFakeGD1("replicate")
+	defer cleanup()
+	profile, err := g.VolumeProfileInfo(context.Background(), "rep3")
+	if err != nil {
+		t.Fatalf("VolumeProfileInfo failed: %s", err)
+	}
+	if !reflect.DeepEqual(profile, expected) {
+		t.Errorf("expected %+v, got %+v", expected, profile)
+	}
+}
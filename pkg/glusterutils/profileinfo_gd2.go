@@ -1,16 +1,20 @@
 package glusterutils
 
 import (
+	"context"
 	"strconv"
+
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/restclient"
 )
 
 // VolumeProfileInfo returns profile info details for the volume
-func (g *GD2) VolumeProfileInfo(vol string) ([]ProfileInfo, error) {
-	client, err := initRESTClient(g.config)
-	if err != nil {
-		return nil, err
-	}
-	details, err := client.VolumeProfileInfo(vol, "info-cumulative")
+func (g *GD2) VolumeProfileInfo(ctx context.Context, vol string) ([]ProfileInfo, error) {
+	var details []api.BrickProfileInfo
+	err := g.pool.call(ctx, "VolumeProfileInfo", func(client *restclient.Client) (err error) {
+		details, err = client.VolumeProfileInfo(vol, "info-cumulative")
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,107 @@
+package glusterutils
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// quotaInodeDisabled is the error reported by glusterd when listing
+// the object quota limits of a volume without the inode quota enabled
+const quotaInodeDisabled = "Inode Quota is disabled"
+
+// parseQuotaValue returns the value of a quota limit or usage,
+// -1 if not available
+func parseQuotaValue(value string) int64 {
+	parsed, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil {
+		return -1
+	}
+	return parsed
+}
+
+// parseQuotaPercent returns the soft limit percentage, like '80%',
+// -1 if not available
+func parseQuotaPercent(value string) float64 {
+	parsed, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(value), "%"), 64)
+	if err != nil {
+		return -1
+	}
+	return parsed
+}
+
+// quotaList runs the quota list command, 'list' or 'list-objects'
+func (g *GD1) quotaList(ctx context.Context, vol string, listCmd string) (gd1QuotaList, error) {
+	var list gd1QuotaList
+	out, err := g.execGluster(ctx, "volume", "quota", vol, listCmd)
+	if err != nil {
+		// the reason of the failure is in the XML output
+		if _, ok := err.(*exec.ExitError); !ok || xml.Unmarshal(out, &list) != nil {
+			return list, err
+		}
+		return list, nil
+	}
+	err = xml.Unmarshal(out, &list)
+	return list, err
+}
+
+// QuotaList returns the usage of the directories with a quota
+// limit set, both the usage and the object limits (GD1)
+func (g *GD1) QuotaList(ctx context.Context, vol string) ([]QuotaLimit, error) {
+	usage, err := g.quotaList(ctx, vol, "list")
+	if err != nil {
+		return nil, err
+	}
+	if usage.OpRet != 0 {
+		return nil, errors.New(usage.OpErr)
+	}
+	var limits []QuotaLimit
+	for _, limit := range usage.Limits {
+		limits = append(limits, QuotaLimit{
+			Path:              limit.Path,
+			Type:              QuotaTypeUsage,
+			HardLimit:         parseQuotaValue(limit.HardLimit),
+			SoftLimitPercent:  parseQuotaPercent(limit.SoftLimitPercent),
+			SoftLimit:         parseQuotaValue(limit.SoftLimitValue),
+			Used:              parseQuotaValue(limit.UsedSpace),
+			Available:         parseQuotaValue(limit.AvailSpace),
+			SoftLimitExceeded: limit.SoftLimitExceeded == "Yes",
+			HardLimitExceeded: limit.HardLimitExceeded == "Yes",
+		})
+	}
+
+	objects, err := g.quotaList(ctx, vol, "list-objects")
+	if err != nil {
+		return nil, err
+	}
+	if objects.OpRet != 0 {
+		// the inode quota can be disabled on its own
+		if strings.Contains(objects.OpErr, quotaInodeDisabled) {
+			return limits, nil
+		}
+		return nil, errors.New(objects.OpErr)
+	}
+	for _, limit := range objects.Limits {
+		used := parseQuotaValue(limit.FileCount)
+		if dirs := parseQuotaValue(limit.DirCount); used >= 0 && dirs >= 0 {
+			used += dirs
+		} else {
+			used = -1
+		}
+		limits = append(limits, QuotaLimit{
+			Path:              limit.Path,
+			Type:              QuotaTypeObjects,
+			HardLimit:         parseQuotaValue(limit.HardLimit),
+			SoftLimitPercent:  parseQuotaPercent(limit.SoftLimitPercent),
+			SoftLimit:         parseQuotaValue(limit.SoftLimitValue),
+			Used:              used,
+			Available:         parseQuotaValue(limit.Available),
+			SoftLimitExceeded: limit.SoftLimitExceeded == "Yes",
+			HardLimitExceeded: limit.HardLimitExceeded == "Yes",
+		})
+	}
+	return limits, nil
+}
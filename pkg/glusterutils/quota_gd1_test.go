@@ -0,0 +1,92 @@
+package glusterutils
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestGD1QuotaList(t *testing.T) {
+	tests := []struct {
+		vol      string
+		expected []QuotaLimit
+	}{
+		{
+			vol: "tenants",
+			expected: []QuotaLimit{
+				{
+					Path:              "/tenant-a",
+					Type:              QuotaTypeUsage,
+					HardLimit:         10737418240,
+					SoftLimitPercent:  80,
+					SoftLimit:         8589934592,
+					Used:              9126805504,
+					Available:         1610612736,
+					SoftLimitExceeded: true,
+				},
+				{
+					Path:              "/tenant-b",
+					Type:              QuotaTypeUsage,
+					HardLimit:         1073741824,
+					SoftLimitPercent:  90,
+					SoftLimit:         966367641,
+					Used:              1073741824,
+					Available:         0,
+					SoftLimitExceeded: true,
+					HardLimitExceeded: true,
+				},
+				{
+					// the limit of a directory which is not accessible
+					Path:             "/removed",
+					Type:             QuotaTypeUsage,
+					HardLimit:        -1,
+					SoftLimitPercent: -1,
+					SoftLimit:        -1,
+					Used:             -1,
+					Available:        -1,
+				},
+				{
+					Path:             "/tenant-a",
+					Type:             QuotaTypeObjects,
+					HardLimit:        100000,
+					SoftLimitPercent: 80,
+					SoftLimit:        80000,
+					Used:             41230 + 1204,
+					Available:        57566,
+				},
+			},
+		},
+		{
+			// the inode quota can be disabled on its own
+			vol: "noinode",
+			expected: []QuotaLimit{
+				{
+					Path:             "/projects",
+					Type:             QuotaTypeUsage,
+					HardLimit:        5368709120,
+					SoftLimitPercent: 80,
+					SoftLimit:        4294967296,
+					Used:             1073741824,
+					Available:        4294967296,
+				},
+			},
+		},
+	}
+
+	gd1, cleanup := newFakeGD1("quota")
+	defer cleanup()
+	for _, tt := range tests {
+		limits, err := gd1.QuotaList(context.Background(), tt.vol)
+		if err != nil {
+			t.Fatalf("%s: QuotaList failed: %s", tt.vol, err)
+		}
+		if !reflect.DeepEqual(limits, tt.expected) {
+			t.Errorf("%s: expected %+v, got %+v", tt.vol, tt.expected, limits)
+		}
+	}
+
+	_, err := gd1.QuotaList(context.Background(), "disabled")
+	if err == nil || err.Error() != "Quota is disabled, please enable quota" {
+		t.Errorf("expected the quota disabled error, got %v", err)
+	}
+}
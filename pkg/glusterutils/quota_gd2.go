@@ -0,0 +1,11 @@
+package glusterutils
+
+import (
+	"context"
+)
+
+// QuotaList returns the usage of the directories with a quota limit
+// set (GD2). The glusterd2 REST client has no quota API yet
+func (g *GD2) QuotaList(ctx context.Context, vol string) ([]QuotaLimit, error) {
+	return nil, ErrNotSupported
+}
@@ -0,0 +1,113 @@
+package glusterutils
+
+import (
+	"context"
+	"encoding/xml"
+	"regexp"
+	"strconv"
+)
+
+const (
+	// gd1TaskRebalance and gd1TaskRemoveBrick are the task types
+	// listed in the volume status
+	gd1TaskRebalance   = "Rebalance"
+	gd1TaskRemoveBrick = "Remove brick"
+	// gd1TaskInProgress is the status of a running task
+	gd1TaskInProgress = 1
+)
+
+// rebalanceTimeLeftRE matches the estimate in the plain rebalance and
+// remove-brick status, hours:minutes:seconds
+var rebalanceTimeLeftRE = regexp.MustCompile(`Estimated time left for .* to complete\s*:\s*(\d+):(\d+):(\d+)`)
+
+// parseRebalanceTimeLeft returns the estimated time to complete in
+// seconds from the plain status output, -1 if not available
+func parseRebalanceTimeLeft(out []byte) int64 {
+	match := rebalanceTimeLeftRE.FindSubmatch(out)
+	if match == nil {
+		return -1
+	}
+	var secs int64
+	for _, part := range match[1:] {
+		// the parts are only digits
+		value, _ := strconv.ParseInt(string(part), 10, 64) // #nosec
+		secs = secs*60 + value
+	}
+	return secs
+}
+
+// RebalanceStatus returns the status of the rebalance and the
+// remove-brick tasks of the volume (GD1)
+func (g *GD1) RebalanceStatus(ctx context.Context, vol string) ([]RebalanceStatus, error) {
+	out, err := g.execGluster(ctx, "volume", "status", vol, "tasks")
+	if err != nil {
+		return nil, err
+	}
+	var tasks gd1VolumeTasks
+	err = xml.Unmarshal(out, &tasks)
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []RebalanceStatus
+	for _, task := range tasks.Tasks {
+		status := RebalanceStatus{
+			Volume:   vol,
+			TaskID:   task.ID,
+			TimeLeft: -1,
+		}
+		var args []string
+		switch task.Type {
+		case gd1TaskRebalance:
+			status.TaskType = RebalanceTaskRebalance
+			args = []string{"volume", "rebalance", vol, "status"}
+		case gd1TaskRemoveBrick:
+			status.TaskType = RebalanceTaskRemoveBrick
+			status.Bricks = task.Params.Bricks
+			args = append([]string{"volume", "remove-brick", vol}, task.Params.Bricks...)
+			args = append(args, "status")
+		default:
+			continue
+		}
+
+		out, err = g.execGluster(ctx, args...)
+		if err != nil {
+			return nil, err
+		}
+		var gd1status gd1RebalanceStatus
+		err = xml.Unmarshal(out, &gd1status)
+		if err != nil {
+			return nil, err
+		}
+		nodes := gd1status.Rebalance
+		if status.TaskType == RebalanceTaskRemoveBrick {
+			nodes = gd1status.RemoveBrick
+		}
+		for _, node := range nodes {
+			status.Nodes = append(status.Nodes, RebalanceNodeStatus{
+				Hostname:        node.NodeName,
+				PeerID:          node.ID,
+				RebalancedFiles: node.Files,
+				RebalancedSize:  node.Size,
+				ScannedFiles:    node.Lookups,
+				Failures:        node.Failures,
+				Skipped:         node.Skipped,
+				RunTime:         node.RunTime,
+				Status:          node.Status,
+				StatusStr:       node.StatusStr,
+			})
+		}
+
+		// The estimate is missing in the XML output, and
+		// only available while the task is in progress
+		if task.Status == gd1TaskInProgress {
+			out, err = g.execGlusterPlain(ctx, args...)
+			if err != nil {
+				return nil, err
+			}
+			status.TimeLeft = parseRebalanceTimeLeft(out)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
@@ -0,0 +1,106 @@
+package glusterutils
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestGD1RebalanceStatus(t *testing.T) {
+	tests := []struct {
+		vol      string
+		expected []RebalanceStatus
+	}{
+		{
+			vol: "dist",
+			expected: []RebalanceStatus{
+				{
+					Volume:   "dist",
+					TaskType: RebalanceTaskRebalance,
+					TaskID:   "0d8a9c52-6a3e-4f0b-9a57-5e1e8b2d4a10",
+					Nodes: []RebalanceNodeStatus{
+						{
+							Hostname:        "localhost",
+							PeerID:          peerID1,
+							RebalancedFiles: 120,
+							RebalancedSize:  1048576,
+							ScannedFiles:    450,
+							Skipped:         2,
+							RunTime:         705,
+							Status:          1,
+							StatusStr:       "in progress",
+						},
+						{
+							Hostname:        "server2",
+							PeerID:          peerID2,
+							RebalancedFiles: 98,
+							RebalancedSize:  786432,
+							ScannedFiles:    401,
+							Failures:        1,
+							RunTime:         704,
+							Status:          1,
+							StatusStr:       "in progress",
+						},
+					},
+					TimeLeft: 25*60 + 10,
+				},
+			},
+		},
+		{
+			// the estimate is only fetched for the tasks in progress
+			vol: "shrink",
+			expected: []RebalanceStatus{
+				{
+					Volume:   "shrink",
+					TaskType: RebalanceTaskRemoveBrick,
+					TaskID:   "7e2f4b61-3c9d-4e8a-b1f0-6a5d4c3b2a19",
+					Bricks:   []string{"server2:/bricks/shrink2"},
+					Nodes: []RebalanceNodeStatus{
+						{
+							Hostname:        "server2",
+							PeerID:          peerID2,
+							RebalancedFiles: 310,
+							RebalancedSize:  52428800,
+							ScannedFiles:    310,
+							RunTime:         92,
+							Status:          3,
+							StatusStr:       "completed",
+						},
+					},
+					TimeLeft: -1,
+				},
+			},
+		},
+		{
+			vol: "idle",
+		},
+	}
+
+	gd1, cleanup := newFakeGD1("rebalance")
+	defer cleanup()
+	for _, tt := range tests {
+		statuses, err := gd1.RebalanceStatus(context.Background(), tt.vol)
+		if err != nil {
+			t.Fatalf("%s: RebalanceStatus failed: %s", tt.vol, err)
+		}
+		if !reflect.DeepEqual(statuses, tt.expected) {
+			t.Errorf("%s: expected %+v, got %+v", tt.vol, tt.expected, statuses)
+		}
+	}
+}
+
+func TestParseRebalanceTimeLeft(t *testing.T) {
+	tests := []struct {
+		out      string
+		expected int64
+	}{
+		{out: "Estimated time left for rebalance to complete :        0:25:10\n", expected: 1510},
+		{out: "Estimated time left for rebalance to complete :      124:00:01\n", expected: 124*3600 + 1},
+		{out: "The estimated time for rebalance to complete will be unavailable for the first 10 minutes.\n", expected: -1},
+	}
+	for _, tt := range tests {
+		if timeLeft := parseRebalanceTimeLeft([]byte(tt.out)); timeLeft != tt.expected {
+			t.Errorf("%q: expected %d, got %d", tt.out, tt.expected, timeLeft)
+		}
+	}
+}
@@ -0,0 +1,11 @@
+package glusterutils
+
+import (
+	"context"
+)
+
+// RebalanceStatus returns the status of the rebalance tasks of the
+// volume (GD2). The glusterd2 REST client has no rebalance API yet
+func (g *GD2) RebalanceStatus(ctx context.Context, vol string) ([]RebalanceStatus, error) {
+	return nil, ErrNotSupported
+}
@@ -0,0 +1,125 @@
+package glusterutils
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"syscall"
+
+	"github.com/gluster/gluster-prometheus/gluster-exporter/conf"
+	"github.com/gluster/gluster-prometheus/pkg/glusterutils/capture"
+	"github.com/gluster/gluster-prometheus/pkg/glusterutils/glusterconsts"
+	log "github.com/sirupsen/logrus"
+)
+
+// recorder saves the outputs of the commands and the glusterd2
+// REST responses, it is nil unless the record mode is enabled
+var recorder *capture.Recorder
+
+// EnableRecording enables the record mode, the outputs of the gluster
+// and lvm commands, the procfs files of the gluster processes and the
+// glusterd2 responses are saved by the given recorder. The local peer ID
+// file is saved too, so that the capture can be replayed as a workdir.
+// It should be called before the collectors are started
+func EnableRecording(r *capture.Recorder, config *conf.GConfig) error {
+	peerIDFile := gd1PeerIDFile
+	if config.GlusterMgmt == glusterconsts.MgmtGlusterd2 {
+		peerIDFile = gd2PeerIDFile
+	}
+	if err := r.AddFile(filepath.Join(config.GlusterdWorkdir, peerIDFile)); err != nil {
+		return err
+	}
+	recorder = r
+	return nil
+}
+
+// procFiles are the files of a process read by 'pkg/proc'
+var procFiles = []string{"cmdline", "stat", "status", "io"}
+
+// RecordProcesses saves the procfs files read for the processes, in
+// the 'proc' subdirectory of the capture with the layout of the
+// procfs, replacing the processes recorded before. It does nothing
+// unless the record mode is enabled
+func RecordProcesses(procRoot string, pids []int) {
+	if recorder == nil {
+		return
+	}
+	files := []string{"uptime", "meminfo"}
+	for _, pid := range pids {
+		for _, name := range procFiles {
+			files = append(files, filepath.Join(strconv.Itoa(pid), name))
+		}
+	}
+	if err := recorder.ReplaceFiles("proc", procRoot, files); err != nil {
+		log.WithError(err).Warn("failed to record the processes")
+	}
+}
+
+// runCmd runs the command and returns its standard output. The command
+// runs in its own process group, which is killed as a whole once the
+// context is done, so that no child of the command is left running. The
+// command and its outputs are recorded under the given name in the
+// record mode
+func runCmd(ctx context.Context, name string, cmd *exec.Cmd) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	exited := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			// negative pid signals the whole process group
+			_ = syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+		case <-exited:
+		}
+	}()
+	err := cmd.Wait()
+	close(exited)
+	out := stdout.Bytes()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		// as done by 'Output'
+		exitErr.Stderr = stderr.Bytes()
+	}
+	if recorder != nil {
+		recErr := recorder.Record(name, cmd.Args[1:],
+			cmd.ProcessState.ExitCode(), out, stderr.Bytes())
+		if recErr != nil {
+			log.WithError(recErr).WithField("cmd", cmd.Args).Warn("failed to record command")
+		}
+	}
+	return out, err
+}
+
+// gd2RecordingEndpoint returns the endpoint of the recording proxy to the
+// glusterd2 endpoint, the proxy talks to glusterd2 with the configured
+// TLS settings
+func gd2RecordingEndpoint(config *conf.GConfig, endpoint string) (string, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: config.Glusterd2Insecure} // #nosec
+	if config.Glusterd2Cacert != "" {
+		caCert, err := ioutil.ReadFile(filepath.Clean(config.Glusterd2Cacert))
+		if err != nil {
+			return "", err
+		}
+		certPool := x509.NewCertPool()
+		if !certPool.AppendCertsFromPEM(caCert) {
+			return "", errors.New("failed to parse glusterd2 CA certificate")
+		}
+		tlsConfig.RootCAs = certPool
+	}
+	transport := &http.Transport{
+		Proxy:           http.ProxyFromEnvironment,
+		TLSClientConfig: tlsConfig,
+	}
+	return recorder.Proxy(endpoint, transport)
+}
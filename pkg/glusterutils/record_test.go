@@ -0,0 +1,126 @@
+package glusterutils
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gluster/gluster-prometheus/pkg/glusterutils/capture"
+	"github.com/gluster/gluster-prometheus/pkg/proc"
+)
+
+// TestRecording records the outputs of the fake gluster,
+// and checks that the capture replays the same results
+func TestRecording(t *testing.T) {
+	baseDir, err := ioutil.TempDir("", "record")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(baseDir)
+	r, err := capture.NewRecorder(baseDir)
+	if err != nil {
+		t.Fatalf("NewRecorder failed: %s", err)
+	}
+	g, cleanup := newFakeGD1("arbiter")
+	defer cleanup()
+	if err := EnableRecording(r, g.config); err != nil {
+		t.Fatalf("EnableRecording failed: %s", err)
+	}
+	defer func() {
+		recorder = nil
+	}()
+
+	vols, err := g.VolumeInfo(context.Background())
+	if err != nil {
+		t.Fatalf("VolumeInfo failed: %s", err)
+	}
+	heals, err := g.HealInfo(context.Background(), "arb")
+	if err != nil {
+		t.Fatalf("HealInfo failed: %s", err)
+	}
+	recorder = nil
+
+	replay, replayCleanup := newFakeGD1FromDir(r.Dir())
+	defer replayCleanup()
+	replayedVols, err := replay.VolumeInfo(context.Background())
+	if err != nil {
+		t.Fatalf("replayed VolumeInfo failed: %s", err)
+	}
+	if !reflect.DeepEqual(replayedVols, vols) {
+		t.Errorf("expected %+v, got %+v", vols, replayedVols)
+	}
+	replayedHeals, err := replay.HealInfo(context.Background(), "arb")
+	if err != nil {
+		t.Fatalf("replayed HealInfo failed: %s", err)
+	}
+	if !reflect.DeepEqual(replayedHeals, heals) {
+		t.Errorf("expected %+v, got %+v", heals, replayedHeals)
+	}
+	peerID, err := replay.LocalPeerID(context.Background())
+	if err != nil || peerID != peerID1 {
+		t.Errorf("expected the recorded local peer %s, got %s (%v)", peerID1, peerID, err)
+	}
+}
+
+// TestRecordProcesses records the procfs files of a process, and
+// checks that the capture reads as the same process
+func TestRecordProcesses(t *testing.T) {
+	baseDir, err := ioutil.TempDir("", "record")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(baseDir)
+	r, err := capture.NewRecorder(baseDir)
+	if err != nil {
+		t.Fatalf("NewRecorder failed: %s", err)
+	}
+	recorder = r
+	defer func() {
+		recorder = nil
+	}()
+
+	procRoot := filepath.Join("..", "proc", "testdata", "proc")
+	// the process 1 does not exist, and is skipped
+	RecordProcesses(procRoot, []int{1, 1523})
+	RecordProcesses(procRoot, []int{2144})
+
+	replay := proc.NewFS(filepath.Join(r.Dir(), "proc"))
+	if _, err := replay.Process(1523); err == nil {
+		t.Errorf("expected the processes of the previous record to be replaced")
+	}
+	expected, err := proc.NewFS(procRoot).Process(2144)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayed, err := replay.Process(2144)
+	if err != nil {
+		t.Fatalf("replayed Process failed: %s", err)
+	}
+	// the open fds are not recorded
+	expected.OpenFds = -1
+	if !reflect.DeepEqual(replayed, expected) {
+		t.Errorf("expected %+v, got %+v", expected, replayed)
+	}
+	if _, err := replay.Uptime(); err != nil {
+		t.Errorf("expected the uptime to be recorded: %s", err)
+	}
+}
+
+func TestRunCmdKillsProcessGroup(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	// the child of the shell keeps the output open till it is killed too
+	_, err := runCmd(ctx, "sh", exec.Command("sh", "-c", "sleep 5; echo done"))
+	if err == nil {
+		t.Error("expected the killed command to fail")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("expected the command to be killed on timeout, took %s", elapsed)
+	}
+}
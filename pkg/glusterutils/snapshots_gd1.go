@@ -1,13 +1,14 @@
 package glusterutils
 
 import (
+	"context"
 	"encoding/xml"
 )
 
 // Snapshots returns snaphosts list for the cluster
-func (g *GD1) Snapshots() ([]Snapshot, error) {
+func (g *GD1) Snapshots(ctx context.Context) ([]Snapshot, error) {
 	// Run Gluster snapshot list
-	out, err := g.execGluster("snapshot", "info")
+	out, err := g.execGluster(ctx, "snapshot", "info")
 	if err != nil {
 		return nil, err
 	}
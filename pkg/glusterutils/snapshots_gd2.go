@@ -1,16 +1,19 @@
 package glusterutils
 
 import (
+	"context"
+
 	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/restclient"
 )
 
 // Snapshots returns snaphosts list for the cluster
-func (g *GD2) Snapshots() ([]Snapshot, error) {
-	client, err := initRESTClient(g.config)
-	if err != nil {
-		return nil, err
-	}
-	snapListResp, err := client.SnapshotList("")
+func (g *GD2) Snapshots(ctx context.Context) ([]Snapshot, error) {
+	var snapListResp api.SnapListResp
+	err := g.pool.call(ctx, "SnapshotList", func(client *restclient.Client) (err error) {
+		snapListResp, err = client.SnapshotList("")
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
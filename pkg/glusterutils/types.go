@@ -1,6 +1,8 @@
 package glusterutils
 
 import (
+	"context"
+
 	"github.com/gluster/gluster-prometheus/gluster-exporter/conf"
 )
 
@@ -59,7 +61,8 @@ type VolumeStatus struct {
 	Nodes []BrickStatus
 }
 
-// HealEntry describe gluster heal info for each brick
+// HealEntry describe gluster heal info for each brick,
+// NumHealEntries is -1 if the brick is not connected
 type HealEntry struct {
 	PeerID         string
 	Hostname       string
@@ -68,6 +71,19 @@ type HealEntry struct {
 	NumHealEntries int64
 }
 
+// HealSummary represents the heal info summary of a brick. The
+// counts are -1 for the bricks which are not connected
+type HealSummary struct {
+	PeerID          string
+	Hostname        string
+	Brick           string
+	Connected       string
+	Total           int64
+	Pending         int64
+	SplitBrain      int64
+	PossiblyHealing int64
+}
+
 // Snapshot represents a Volume snapshot
 type Snapshot struct {
 	Name       string
@@ -90,19 +106,244 @@ type BrickStatus struct {
 	Gd1InodesTotal int64 // only valid with GD1, -1 with GD2
 }
 
-// GInterface should be implemented in GD1 and GD2 structs
+// GeoRepSession represents a geo-replication session of a master volume
+type GeoRepSession struct {
+	MasterVolume string
+	SlaveUser    string
+	SlaveHost    string
+	SlaveVolume  string
+	Workers      []GeoRepWorker
+}
+
+// GeoRepWorker describes the geo-replication worker of a master brick.
+// The timestamps are unix times, 0 if not available, and the
+// counters are -1 if not available
+type GeoRepWorker struct {
+	MasterNode               string
+	MasterPeerID             string
+	MasterBrick              string
+	SlaveNode                string
+	Status                   string
+	CrawlStatus              string
+	LastSynced               int64
+	CheckpointTime           int64
+	CheckpointCompleted      bool
+	CheckpointCompletionTime int64
+	PendingEntryOps          int64
+	PendingDataOps           int64
+	PendingMetaOps           int64
+	Failures                 int64
+}
+
+// Rebalance task types
+const (
+	RebalanceTaskRebalance   = "rebalance"
+	RebalanceTaskRemoveBrick = "remove-brick"
+)
+
+// RebalanceStatus describes a rebalance or a remove-brick task of a
+// volume. The remove-brick tasks migrate the data off the removed Bricks
+type RebalanceStatus struct {
+	Volume   string
+	TaskType string
+	TaskID   string
+	Bricks   []string
+	Nodes    []RebalanceNodeStatus
+	// TimeLeft is the estimated time to complete the task in
+	// seconds, -1 if not available
+	TimeLeft int64
+}
+
+// RebalanceNodeStatus describes the progress of a rebalance
+// task on a node. The Status codes are the ones of glusterd:
+// 0-not started, 1-in progress, 2-stopped, 3-completed, 4-failed,
+// 5-fix-layout in progress, 6-fix-layout stopped, 7-fix-layout completed,
+// 8-fix-layout failed
+type RebalanceNodeStatus struct {
+	Hostname        string
+	PeerID          string
+	RebalancedFiles int64
+	RebalancedSize  int64
+	ScannedFiles    int64
+	Failures        int64
+	Skipped         int64
+	RunTime         float64
+	Status          int
+	StatusStr       string
+}
+
+// Quota limit types
+const (
+	QuotaTypeUsage   = "usage"
+	QuotaTypeObjects = "objects"
+)
+
+// QuotaLimit describes the usage of a directory with a quota limit set.
+// The usage quota limits are in bytes, the object quota limits are in
+// no of files and directories. The limits and usage are -1 if not
+// available, like when the directory is not accessible
+type QuotaLimit struct {
+	Path              string
+	Type              string
+	HardLimit         int64
+	SoftLimitPercent  float64
+	SoftLimit         int64
+	Used              int64
+	Available         int64
+	SoftLimitExceeded bool
+	HardLimitExceeded bool
+}
+
+// BitrotScrubStatus describes the scrubber status of a bitrot
+// enabled volume. Throttle and Frequency are the scrubber options
+type BitrotScrubStatus struct {
+	Volume    string
+	State     string
+	Throttle  string
+	Frequency string
+	Nodes     []BitrotScrubNodeStatus
+}
+
+// BitrotScrubNodeStatus describes the progress of the scrubber on a
+// node. LastScrubTime is a unix time, 0 if no scrub completed yet,
+// and LastScrubDuration is in seconds. CorruptedObjects lists the
+// GFIDs of the objects the scrubber found corrupted
+type BitrotScrubNodeStatus struct {
+	Hostname          string
+	ScrubbedFiles     int64
+	SkippedFiles      int64
+	LastScrubTime     int64
+	LastScrubDuration int64
+	ErrorCount        int64
+	CorruptedObjects  []string
+}
+
+// BrickClients describes the clients connected to a brick of the
+// volume. The clients are listed only if the brick is online
+type BrickClients struct {
+	Hostname    string
+	PeerID      string
+	Path        string
+	Volume      string
+	Status      int
+	ClientCount int
+	Clients     []BrickClient
+}
+
+// BrickClient describes a connection of a client to a brick. Hostname
+// is the address of the client with the port of the connection, a
+// client has a connection with each brick, sometimes more than one
+type BrickClient struct {
+	Hostname   string
+	BytesRead  uint64
+	BytesWrite uint64
+	OpVersion  int
+}
+
+// BrickMemStatus describes the memory usage of a brick process, from
+// its mallinfo and its memory pools. The details are listed only if
+// the brick is online
+type BrickMemStatus struct {
+	Hostname string
+	PeerID   string
+	Path     string
+	Volume   string
+	Status   int
+	Mallinfo Mallinfo
+	Mempools []Mempool
+}
+
+// Mallinfo describes the heap of a brick process, as reported by
+// mallinfo(3). The sizes are in bytes
+type Mallinfo struct {
+	Arena    int64
+	Ordblks  int64
+	Smblks   int64
+	Hblks    int64
+	Hblkhd   int64
+	Usmblks  int64
+	Fsmblks  int64
+	Uordblks int64
+	Fordblks int64
+	Keepcost int64
+}
+
+// Mempool describes a memory pool of a brick process. HotCount is the
+// no of objects in use and ColdCount the no of free objects in the pool
+type Mempool struct {
+	Name        string
+	HotCount    int64
+	ColdCount   int64
+	PaddedSize  int64
+	AllocCount  int64
+	MaxAlloc    int64
+	PoolMisses  int64
+	MaxStdAlloc int64
+}
+
+// BrickInodeStatus describes the inode tables of a brick process,
+// summed across its connections
+type BrickInodeStatus struct {
+	Hostname   string
+	PeerID     string
+	Path       string
+	Volume     string
+	Status     int
+	ActiveSize int64
+	LRUSize    int64
+	PurgeSize  int64
+}
+
+// BrickFdStatus describes the open fds of a brick process, OpenFds
+// is the no of fds open across all the connections
+type BrickFdStatus struct {
+	Hostname    string
+	PeerID      string
+	Path        string
+	Volume      string
+	Status      int
+	Connections int
+	OpenFds     int64
+}
+
+// BrickCallpoolStatus describes the pending calls of a brick process,
+// CallStacks is the no of pending requests and CallFrames the no of
+// frames of those requests
+type BrickCallpoolStatus struct {
+	Hostname   string
+	PeerID     string
+	Path       string
+	Volume     string
+	Status     int
+	CallStacks int
+	CallFrames int64
+}
+
+// GInterface should be implemented in GD1 and GD2 structs.
+// Every call is bounded by the configured timeout, and aborted
+// earlier if the given context is done
 type GInterface interface {
-	Peers() ([]Peer, error)
-	LocalPeerID() (string, error)
-	IsLeader() (bool, error)
-	HealInfo(vol string) ([]HealEntry, error)
-	SplitBrainHealInfo(vol string) ([]HealEntry, error)
-	VolumeInfo() ([]Volume, error)
-	Snapshots() ([]Snapshot, error)
-	VolumeProfileInfo(vol string) ([]ProfileInfo, error)
-	VolumeBrickStatus(vol string) ([]BrickStatus, error)
-	EnableVolumeProfiling(volinfo Volume) error
-	VolumeStatus() ([]VolumeStatus, error)
+	Peers(ctx context.Context) ([]Peer, error)
+	LocalPeerID(ctx context.Context) (string, error)
+	IsLeader(ctx context.Context) (bool, error)
+	HealInfo(ctx context.Context, vol string) ([]HealEntry, error)
+	SplitBrainHealInfo(ctx context.Context, vol string) ([]HealEntry, error)
+	HealInfoSummary(ctx context.Context, vol string) ([]HealSummary, error)
+	VolumeInfo(ctx context.Context) ([]Volume, error)
+	Snapshots(ctx context.Context) ([]Snapshot, error)
+	VolumeProfileInfo(ctx context.Context, vol string) ([]ProfileInfo, error)
+	VolumeBrickStatus(ctx context.Context, vol string) ([]BrickStatus, error)
+	EnableVolumeProfiling(ctx context.Context, volinfo Volume) error
+	VolumeStatus(ctx context.Context) ([]VolumeStatus, error)
+	GeoRepStatus(ctx context.Context) ([]GeoRepSession, error)
+	RebalanceStatus(ctx context.Context, vol string) ([]RebalanceStatus, error)
+	QuotaList(ctx context.Context, vol string) ([]QuotaLimit, error)
+	BitrotScrubStatus(ctx context.Context, vol string) (BitrotScrubStatus, error)
+	VolumeClients(ctx context.Context, vol string) ([]BrickClients, error)
+	VolumeMemStatus(ctx context.Context, vol string) ([]BrickMemStatus, error)
+	VolumeInodeStatus(ctx context.Context, vol string) ([]BrickInodeStatus, error)
+	VolumeFdStatus(ctx context.Context, vol string) ([]BrickFdStatus, error)
+	VolumeCallpoolStatus(ctx context.Context, vol string) ([]BrickCallpoolStatus, error)
 }
 
 // FopStat defines file ops related details
@@ -135,4 +376,5 @@ type GD1 struct {
 // GD2 is struct to interact with Glusterd2 using REST API
 type GD2 struct {
 	config *conf.GConfig
+	pool   *gd2Pool
 }
@@ -0,0 +1,41 @@
+package glusterutils
+
+import (
+	"context"
+	"encoding/xml"
+)
+
+// VolumeClients returns the clients connected to each brick of the
+// volume (glusterd)
+func (g *GD1) VolumeClients(ctx context.Context, vol string) ([]BrickClients, error) {
+	// Run gluster volume status {vol} clients --xml
+	out, err := g.execGluster(ctx, "volume", "status", vol, "clients")
+	if err != nil {
+		return nil, err
+	}
+	var volClients gd1VolumeClients
+	if err = xml.Unmarshal(out, &volClients); err != nil {
+		return nil, err
+	}
+
+	bricks := make([]BrickClients, len(volClients.Bricks))
+	for idx, brick := range volClients.Bricks {
+		bricks[idx] = BrickClients{
+			Hostname:    brick.Hostname,
+			PeerID:      brick.PeerID,
+			Path:        brick.Path,
+			Volume:      vol,
+			Status:      brick.Status,
+			ClientCount: brick.ClientCount,
+		}
+		for _, client := range brick.Clients {
+			bricks[idx].Clients = append(bricks[idx].Clients, BrickClient{
+				Hostname:   client.Hostname,
+				BytesRead:  client.BytesRead,
+				BytesWrite: client.BytesWrite,
+				OpVersion:  client.OpVersion,
+			})
+		}
+	}
+	return bricks, nil
+}
@@ -0,0 +1,53 @@
+package glusterutils
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestGD1VolumeClients(t *testing.T) {
+	expected := []BrickClients{
+		{
+			Hostname:    "server1",
+			PeerID:      peerID1,
+			Path:        "/bricks/rep3",
+			Volume:      "rep3",
+			Status:      1,
+			ClientCount: 3,
+			Clients: []BrickClient{
+				{Hostname: "10.70.42.11:49151", BytesRead: 15632, BytesWrite: 14120, OpVersion: 50400},
+				{Hostname: "10.70.43.101:1020", BytesRead: 2097512, BytesWrite: 104857904, OpVersion: 50400},
+				{Hostname: "10.70.43.101:1017", BytesRead: 1048832, BytesWrite: 1072, OpVersion: 50400},
+			},
+		},
+		{
+			Hostname:    "server2",
+			PeerID:      peerID2,
+			Path:        "/bricks/rep3",
+			Volume:      "rep3",
+			Status:      1,
+			ClientCount: 1,
+			Clients: []BrickClient{
+				{Hostname: "10.70.43.101:1019", BytesRead: 2097440, BytesWrite: 104857832, OpVersion: 50400},
+			},
+		},
+		{
+			// the clients of an offline brick are not listed
+			Hostname: "server3",
+			PeerID:   peerID3,
+			Path:     "/bricks/rep3",
+			Volume:   "rep3",
+		},
+	}
+
+	g, cleanup := newFakeGD1("clients")
+	defer cleanup()
+	bricks, err := g.VolumeClients(context.Background(), "rep3")
+	if err != nil {
+		t.Fatalf("VolumeClients failed: %s", err)
+	}
+	if !reflect.DeepEqual(bricks, expected) {
+		t.Errorf("expected %+v, got %+v", expected, bricks)
+	}
+}
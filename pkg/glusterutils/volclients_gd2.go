@@ -0,0 +1,11 @@
+package glusterutils
+
+import (
+	"context"
+)
+
+// VolumeClients returns the clients connected to each brick of the
+// volume (GD2). The glusterd2 brick status has no client details yet
+func (g *GD2) VolumeClients(ctx context.Context, vol string) ([]BrickClients, error) {
+	return nil, ErrNotSupported
+}
@@ -1,6 +1,7 @@
 package glusterutils
 
 import (
+	"context"
 	"encoding/xml"
 	"fmt"
 	"strings"
@@ -9,9 +10,9 @@ import (
 )
 
 // VolumeInfo returns gluster vol info (glusterd)
-func (g *GD1) VolumeInfo() ([]Volume, error) {
+func (g *GD1) VolumeInfo(ctx context.Context) ([]Volume, error) {
 	// Run Gluster volume info
-	out, err := g.execGluster("volume", "info")
+	out, err := g.execGluster(ctx, "volume", "info")
 	if err != nil {
 		return nil, err
 	}
@@ -53,14 +54,15 @@ func (g *GD1) VolumeInfo() ([]Volume, error) {
 			outvol.SubVolumes[sidx].DisperseRedundancyCount = vol.DisperseRedundancyCount
 			outvol.SubVolumes[sidx].Name = fmt.Sprintf("%s-%s-%d", vol.Name, strings.ToLower(subvolType), sidx)
 			for bidx := 0; bidx < subvolBricksCount; bidx++ {
+				gd1brick := vol.Bricks[sidx*subvolBricksCount+bidx]
 				brickType := glusterconsts.BrickTypeDefault
-				if vol.Bricks[sidx+bidx].IsArbiter == 1 {
+				if gd1brick.IsArbiter == 1 {
 					brickType = glusterconsts.BrickTypeArbiter
 				}
-				brickParts := strings.Split(vol.Bricks[sidx*subvolBricksCount+bidx].Name, ":")
+				brickParts := strings.Split(gd1brick.Name, ":")
 				brick := Brick{
 					Host:       brickParts[0],
-					PeerID:     vol.Bricks[sidx*subvolBricksCount+bidx].PeerID,
+					PeerID:     gd1brick.PeerID,
 					Type:       brickType,
 					Path:       brickParts[1],
 					VolumeID:   vol.ID,
@@ -0,0 +1,151 @@
+package glusterutils
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/gluster/gluster-prometheus/pkg/glusterutils/glusterconsts"
+)
+
+func TestGD1VolumeInfo(t *testing.T) {
+	tests := []struct {
+		scenario     string
+		name         string
+		volType      string
+		subvolType   string
+		distCount    int
+		replicaCount int
+		disperseData int
+		redundancy   int
+		// bricks of each subvolume, as 'host:path'
+		subvols [][]string
+		// arbiter bricks, as 'host:path'
+		arbiters []string
+	}{
+		{
+			scenario:     "replicate",
+			name:         "rep3",
+			volType:      glusterconsts.VolumeTypeReplicate,
+			subvolType:   glusterconsts.SubvolTypeReplicate,
+			distCount:    3,
+			replicaCount: 3,
+			subvols: [][]string{
+				{"server1:/bricks/rep3", "server2:/bricks/rep3", "server3:/bricks/rep3"},
+			},
+		},
+		{
+			scenario:     "arbiter",
+			name:         "arb",
+			volType:      glusterconsts.VolumeTypeDistReplicateGD1,
+			subvolType:   glusterconsts.SubvolTypeReplicate,
+			distCount:    2,
+			replicaCount: 3,
+			subvols: [][]string{
+				{"server1:/bricks/arb1", "server2:/bricks/arb1", "server3:/bricks/arb1-arbiter"},
+				{"server1:/bricks/arb2", "server2:/bricks/arb2", "server3:/bricks/arb2-arbiter"},
+			},
+			arbiters: []string{"server3:/bricks/arb1-arbiter", "server3:/bricks/arb2-arbiter"},
+		},
+		{
+			scenario:     "disperse",
+			name:         "ec",
+			volType:      glusterconsts.VolumeTypeDisperse,
+			subvolType:   glusterconsts.SubvolTypeDisperse,
+			distCount:    1,
+			replicaCount: 1,
+			disperseData: 2,
+			redundancy:   1,
+			subvols: [][]string{
+				{"server1:/bricks/ec", "server2:/bricks/ec", "server3:/bricks/ec"},
+			},
+		},
+		{
+			scenario:     "distributed-disperse",
+			name:         "dist-ec",
+			volType:      glusterconsts.VolumeTypeDistDisperseGD1,
+			subvolType:   glusterconsts.SubvolTypeDisperse,
+			distCount:    2,
+			replicaCount: 1,
+			disperseData: 2,
+			redundancy:   1,
+			subvols: [][]string{
+				{"server1:/bricks/dec1", "server2:/bricks/dec1", "server3:/bricks/dec1"},
+				{"server1:/bricks/dec2", "server2:/bricks/dec2", "server3:/bricks/dec2"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.scenario, func(t *testing.T) {
+			g, cleanup := newFakeGD1(tt.scenario)
+			defer cleanup()
+			vols, err := g.VolumeInfo(context.Background())
+			if err != nil {
+				t.Fatalf("VolumeInfo failed: %s", err)
+			}
+			if len(vols) != 1 {
+				t.Fatalf("expected 1 volume, got %d", len(vols))
+			}
+			vol := vols[0]
+			if vol.Name != tt.name || vol.Type != tt.volType || vol.State != glusterconsts.VolumeStateStarted {
+				t.Errorf("unexpected volume %s, type %s, state %s", vol.Name, vol.Type, vol.State)
+			}
+			if vol.Transport != "tcp" {
+				t.Errorf("expected tcp transport, got %s", vol.Transport)
+			}
+			if vol.DistributeCount != tt.distCount || vol.ReplicaCount != tt.replicaCount {
+				t.Errorf("unexpected distribute count %d, replica count %d", vol.DistributeCount, vol.ReplicaCount)
+			}
+			if vol.DisperseDataCount != tt.disperseData || vol.DisperseRedundancyCount != tt.redundancy {
+				t.Errorf("unexpected disperse data count %d, redundancy count %d",
+					vol.DisperseDataCount, vol.DisperseRedundancyCount)
+			}
+			if vol.Options["nfs.disable"] != "on" {
+				t.Errorf("expected option nfs.disable=on, got %q", vol.Options["nfs.disable"])
+			}
+
+			var subvols [][]string
+			var arbiters []string
+			for _, subvol := range vol.SubVolumes {
+				if subvol.Type != tt.subvolType {
+					t.Errorf("subvolume %s: expected type %s, got %s", subvol.Name, tt.subvolType, subvol.Type)
+				}
+				var bricks []string
+				for _, brick := range subvol.Bricks {
+					if brick.VolumeName != tt.name || brick.PeerID == "" {
+						t.Errorf("brick %s:%s: unexpected volume %s or peer %q",
+							brick.Host, brick.Path, brick.VolumeName, brick.PeerID)
+					}
+					bricks = append(bricks, brick.Host+":"+brick.Path)
+					if brick.Type == glusterconsts.BrickTypeArbiter {
+						arbiters = append(arbiters, brick.Host+":"+brick.Path)
+					}
+				}
+				subvols = append(subvols, bricks)
+			}
+			if !reflect.DeepEqual(subvols, tt.subvols) {
+				t.Errorf("expected subvolumes %v, got %v", tt.subvols, subvols)
+			}
+			if !reflect.DeepEqual(arbiters, tt.arbiters) {
+				t.Errorf("expected arbiters %v, got %v", tt.arbiters, arbiters)
+			}
+		})
+	}
+}
+
+func TestGetSubvolType(t *testing.T) {
+	tests := map[string]string{
+		glusterconsts.VolumeTypeDistReplicate:    glusterconsts.SubvolTypeReplicate,
+		glusterconsts.VolumeTypeDistReplicateGD1: glusterconsts.SubvolTypeReplicate,
+		glusterconsts.VolumeTypeDistDisperse:     glusterconsts.SubvolTypeDisperse,
+		glusterconsts.VolumeTypeDistDisperseGD1:  glusterconsts.SubvolTypeDisperse,
+		glusterconsts.VolumeTypeReplicate:        glusterconsts.SubvolTypeReplicate,
+		glusterconsts.VolumeTypeDistribute:       glusterconsts.SubvolTypeDistribute,
+	}
+	for voltype, expected := range tests {
+		if got := getSubvolType(voltype); got != expected {
+			t.Errorf("getSubvolType(%q): expected %q, got %q", voltype, expected, got)
+		}
+	}
+}
@@ -1,7 +1,10 @@
 package glusterutils
 
 import (
+	"context"
+
 	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/restclient"
 )
 
 // temporary hack till glusterd2 supports SubvolType.String()
@@ -12,12 +15,12 @@ var subvolTypeValueToName = map[api.SubvolType]string{
 }
 
 // VolumeInfo returns gluster vol info (glusterd2)
-func (g *GD2) VolumeInfo() ([]Volume, error) {
-	client, err := initRESTClient(g.config)
-	if err != nil {
-		return nil, err
-	}
-	vols, err := client.Volumes("")
+func (g *GD2) VolumeInfo(ctx context.Context) ([]Volume, error) {
+	var vols api.VolumeListResp
+	err := g.pool.call(ctx, "Volumes", func(client *restclient.Client) (err error) {
+		vols, err = client.Volumes("")
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
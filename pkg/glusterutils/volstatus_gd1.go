@@ -1,14 +1,15 @@
 package glusterutils
 
 import (
+	"context"
 	"encoding/xml"
 	"strconv"
 )
 
 // VolumeStatus returns gluster vol status (glusterd)
-func (g *GD1) VolumeStatus() ([]VolumeStatus, error) {
+func (g *GD1) VolumeStatus(ctx context.Context) ([]VolumeStatus, error) {
 	// Run Gluster volume status all detail --xml --mode=script
-	out, err := g.execGluster("volume", "status", "all", "detail")
+	out, err := g.execGluster(ctx, "volume", "status", "all", "detail")
 	if err != nil {
 		return nil, err
 	}
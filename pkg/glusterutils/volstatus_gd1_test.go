@@ -0,0 +1,116 @@
+package glusterutils
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestGD1VolumeStatus(t *testing.T) {
+	online := func(host, peerID, path string, port, pid int) BrickStatus {
+		return BrickStatus{Hostname: host, PeerID: peerID, Status: 1, Port: port, PID: pid, Path: path}
+	}
+	// 'N/A' ports are reported as -1, the disconnected
+	// bricks have no pid and no capacity details
+	offline := func(host, peerID, path string) BrickStatus {
+		return BrickStatus{Hostname: host, PeerID: peerID, Status: 0, Port: -1, PID: -1, Path: path}
+	}
+	tests := []struct {
+		scenario string
+		volume   string
+		expected []BrickStatus
+	}{
+		{
+			scenario: "replicate",
+			volume:   "rep3",
+			expected: []BrickStatus{
+				online("server1", peerID1, "/bricks/rep3", 49152, 2144),
+				online("server2", peerID2, "/bricks/rep3", -1, 3012),
+				offline("server3", peerID3, "/bricks/rep3"),
+			},
+		},
+		{
+			scenario: "arbiter",
+			volume:   "arb",
+			expected: []BrickStatus{
+				online("server1", peerID1, "/bricks/arb1", 49153, 2210),
+				online("server2", peerID2, "/bricks/arb1", 49153, 3120),
+				online("server3", peerID3, "/bricks/arb1-arbiter", 49153, 4011),
+				online("server1", peerID1, "/bricks/arb2", 49154, 2231),
+				online("server2", peerID2, "/bricks/arb2", -1, 3141),
+				offline("server3", peerID3, "/bricks/arb2-arbiter"),
+			},
+		},
+		{
+			scenario: "disperse",
+			volume:   "ec",
+			expected: []BrickStatus{
+				online("server1", peerID1, "/bricks/ec", 49155, 2302),
+				online("server2", peerID2, "/bricks/ec", 49155, 3210),
+				online("server3", peerID3, "/bricks/ec", 49155, 4107),
+			},
+		},
+		{
+			scenario: "distributed-disperse",
+			volume:   "dist-ec",
+			expected: []BrickStatus{
+				online("server1", peerID1, "/bricks/dec1", 49156, 2401),
+				online("server2", peerID2, "/bricks/dec1", 49156, 3301),
+				online("server3", peerID3, "/bricks/dec1", 49156, 4201),
+				online("server1", peerID1, "/bricks/dec2", 49157, 2402),
+				online("server2", peerID2, "/bricks/dec2", 49157, 3302),
+				offline("server3", peerID3, "/bricks/dec2"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.scenario, func(t *testing.T) {
+			g, cleanup := newFakeGD1(tt.scenario)
+			defer cleanup()
+
+			vols, err := g.VolumeStatus(context.Background())
+			if err != nil {
+				t.Fatalf("VolumeStatus failed: %s", err)
+			}
+			if len(vols) != 1 || vols[0].Name != tt.volume {
+				t.Fatalf("expected the volume %s, got %+v", tt.volume, vols)
+			}
+			if len(vols[0].Nodes) != len(tt.expected) {
+				t.Fatalf("expected %d bricks, got %d", len(tt.expected), len(vols[0].Nodes))
+			}
+			for idx, node := range vols[0].Nodes {
+				expected := tt.expected[idx]
+				expected.Volume = tt.volume
+				if expected.Status == 1 {
+					expected.Capacity = node.Capacity
+					expected.Free = node.Free
+					expected.Gd1InodesTotal = node.Gd1InodesTotal
+					expected.Gd1InodesFree = node.Gd1InodesFree
+					if node.Capacity == 0 || node.Free > node.Capacity ||
+						node.Gd1InodesTotal == 0 || node.Gd1InodesFree > node.Gd1InodesTotal {
+						t.Errorf("brick %s:%s: invalid capacity details %+v", node.Hostname, node.Path, node)
+					}
+				}
+				if !reflect.DeepEqual(node, expected) {
+					t.Errorf("expected %+v, got %+v", expected, node)
+				}
+			}
+
+			// the brick status skips the self-heal daemon
+			bricks, err := g.VolumeBrickStatus(context.Background(), tt.volume)
+			if err != nil {
+				t.Fatalf("VolumeBrickStatus failed: %s", err)
+			}
+			var expectedBricks []BrickStatus
+			for _, brick := range tt.expected {
+				brick.Port = 0
+				brick.Volume = tt.volume
+				expectedBricks = append(expectedBricks, brick)
+			}
+			if !reflect.DeepEqual(bricks, expectedBricks) {
+				t.Errorf("expected %+v, got %+v", expectedBricks, bricks)
+			}
+		})
+	}
+}
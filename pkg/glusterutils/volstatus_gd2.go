@@ -1,13 +1,20 @@
 package glusterutils
 
+import (
+	"context"
+
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/restclient"
+)
+
 // VolumeStatus returns gluster vol status (glusterd2)
-func (g *GD2) VolumeStatus() ([]VolumeStatus, error) {
-	client, err := initRESTClient(g.config)
-	if err != nil {
-		return nil, err
-	}
+func (g *GD2) VolumeStatus(ctx context.Context) ([]VolumeStatus, error) {
 	// We have to fetch the list of volumes first...
-	volumelist, err := client.Volumes("")
+	var volumelist api.VolumeListResp
+	err := g.pool.call(ctx, "Volumes", func(client *restclient.Client) (err error) {
+		volumelist, err = client.Volumes("")
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -16,7 +23,11 @@ func (g *GD2) VolumeStatus() ([]VolumeStatus, error) {
 		// ...and the detailed brick statuses individually for each
 		// volume, because the GD2 REST API does not have a "give me
 		// detailed status information for all volumes" endpoint.
-		brickstatusinfo, err := client.BricksStatus(vol.Name)
+		var brickstatusinfo api.BricksStatusResp
+		err := g.pool.call(ctx, "BricksStatus", func(client *restclient.Client) (err error) {
+			brickstatusinfo, err = client.BricksStatus(vol.Name)
+			return err
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -26,16 +26,19 @@ func setLogOutput(w io.Writer) {
 	stdlog.SetOutput(log.StandardLogger().Writer())
 }
 
-// Init initializes the logger
+// Init initializes the logger. It can be called again, like on the
+// reload of the configuration, the previously opened log file is closed
+// once the new one is in use, so that a log file rotated by logrotate
+// is reopened
 func Init(logdir string, logfile string, loglevel string) error {
-	// Close the previously opened log file
-	if LogWriter != nil {
-		err := LogWriter.Close()
-		if err != nil {
-			return err
+	// Close the previously opened log file, after switching the output
+	prevWriter := LogWriter
+	LogWriter = nil
+	defer func() {
+		if prevWriter != nil {
+			_ = prevWriter.Close()
 		}
-		LogWriter = nil
-	}
+	}()
 
 	level, err := log.ParseLevel(strings.ToLower(loglevel))
 	if err != nil {
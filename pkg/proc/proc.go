@@ -0,0 +1,245 @@
+// Package proc reads the details of the processes from procfs, like
+// their command line, cpu and memory usage, io and open fds
+package proc
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultRoot is the mount point of procfs
+const DefaultRoot = "/proc"
+
+// userHZ is the unit of the cpu times in '/proc/<pid>/stat', USER_HZ
+// is 100 on all the platforms supported by Linux
+const userHZ = 100
+
+// Process describes a process. The cpu times are in seconds, and the
+// memory sizes and the io counters are in bytes. ReadBytes, WriteBytes
+// and OpenFds are -1 if not available, like when the exporter is not
+// allowed to read the io and the fds of the process
+type Process struct {
+	PID                     int
+	Args                    []string
+	CPUSeconds              float64
+	StartTime               float64 // seconds since the boot
+	VirtualMemory           uint64
+	ResidentMemory          uint64
+	Threads                 int64
+	VoluntaryCtxSwitches    uint64
+	NonVoluntaryCtxSwitches uint64
+	ReadBytes               int64
+	WriteBytes              int64
+	OpenFds                 int64
+}
+
+// FS reads the processes from the procfs mounted at the root
+type FS struct {
+	root string
+}
+
+// NewFS returns a FS reading the procfs mounted at the root
+func NewFS(root string) FS {
+	return FS{root: root}
+}
+
+func (fs FS) path(elem ...string) string {
+	return filepath.Join(append([]string{fs.root}, elem...)...)
+}
+
+// PIDs returns the pids of all the running processes, in order
+func (fs FS) PIDs() ([]int, error) {
+	dir, err := os.Open(fs.root)
+	if err != nil {
+		return nil, err
+	}
+	names, err := dir.Readdirnames(-1)
+	_ = dir.Close()
+	if err != nil {
+		return nil, err
+	}
+	var pids []int
+	for _, name := range names {
+		pid, err := strconv.Atoi(name)
+		if err != nil {
+			continue
+		}
+		pids = append(pids, pid)
+	}
+	sort.Ints(pids)
+	return pids, nil
+}
+
+// CmdLine returns the arguments of the process, empty for the
+// kernel threads and the zombie processes
+func (fs FS) CmdLine(pid int) ([]string, error) {
+	out, err := ioutil.ReadFile(fs.path(strconv.Itoa(pid), "cmdline"))
+	if err != nil {
+		return nil, err
+	}
+	out = bytes.TrimRight(out, "\x00")
+	if len(out) == 0 {
+		return nil, nil
+	}
+	return strings.Split(string(out), "\x00"), nil
+}
+
+// Find returns the processes whose arguments match, the processes
+// exiting while they are read are skipped
+func (fs FS) Find(match func(args []string) bool) ([]Process, error) {
+	pids, err := fs.PIDs()
+	if err != nil {
+		return nil, err
+	}
+	var procs []Process
+	for _, pid := range pids {
+		args, err := fs.CmdLine(pid)
+		if err != nil || len(args) == 0 || !match(args) {
+			continue
+		}
+		p, err := fs.Process(pid)
+		if err != nil {
+			continue
+		}
+		p.Args = args
+		procs = append(procs, p)
+	}
+	return procs, nil
+}
+
+// Process returns the details of the process, without its arguments
+func (fs FS) Process(pid int) (Process, error) {
+	p := Process{PID: pid, ReadBytes: -1, WriteBytes: -1, OpenFds: -1}
+	if err := fs.readStat(&p); err != nil {
+		return p, err
+	}
+	if err := fs.readStatus(&p); err != nil {
+		return p, err
+	}
+	// the io and the fds are readable only by the owner of the
+	// process, or with CAP_SYS_PTRACE
+	if readBytes, writeBytes, err := fs.readIO(pid); err == nil {
+		p.ReadBytes, p.WriteBytes = readBytes, writeBytes
+	}
+	if fds, err := ioutil.ReadDir(fs.path(strconv.Itoa(pid), "fd")); err == nil {
+		p.OpenFds = int64(len(fds))
+	}
+	return p, nil
+}
+
+// readStat reads the cpu times, the start time and the memory sizes
+// of the process from '/proc/<pid>/stat'
+func (fs FS) readStat(p *Process) error {
+	out, err := ioutil.ReadFile(fs.path(strconv.Itoa(p.PID), "stat"))
+	if err != nil {
+		return err
+	}
+	// the command name is in parentheses and can contain spaces and
+	// parentheses, the fields are after its last closing parenthesis
+	end := bytes.LastIndexByte(out, ')')
+	if end < 0 {
+		return fmt.Errorf("invalid stat of the process %d", p.PID)
+	}
+	// fields[0] is the state, the 3rd field of the stat
+	fields := strings.Fields(string(out[end+1:]))
+	if len(fields) < 22 {
+		return fmt.Errorf("invalid stat of the process %d", p.PID)
+	}
+	// values of the fields, numbered as in proc(5): utime,
+	// stime, starttime, vsize and rss
+	values := make(map[int]uint64)
+	for _, field := range []int{14, 15, 22, 23, 24} {
+		if values[field], err = strconv.ParseUint(fields[field-3], 10, 64); err != nil {
+			return fmt.Errorf("invalid stat of the process %d: %s", p.PID, err)
+		}
+	}
+	p.CPUSeconds = float64(values[14]+values[15]) / userHZ
+	p.StartTime = float64(values[22]) / userHZ
+	p.VirtualMemory = values[23]
+	// rss is in pages
+	p.ResidentMemory = values[24] * uint64(os.Getpagesize())
+	return nil
+}
+
+// readStatus reads the threads and the context switches of the
+// process from '/proc/<pid>/status'
+func (fs FS) readStatus(p *Process) error {
+	values, err := readKeyValues(fs.path(strconv.Itoa(p.PID), "status"))
+	if err != nil {
+		return err
+	}
+	p.Threads, _ = strconv.ParseInt(values["Threads"], 10, 64)
+	p.VoluntaryCtxSwitches, _ = strconv.ParseUint(values["voluntary_ctxt_switches"], 10, 64)
+	p.NonVoluntaryCtxSwitches, _ = strconv.ParseUint(values["nonvoluntary_ctxt_switches"], 10, 64)
+	return nil
+}
+
+// readIO returns the bytes read and written by the process from and to
+// the storage, from '/proc/<pid>/io'
+func (fs FS) readIO(pid int) (int64, int64, error) {
+	values, err := readKeyValues(fs.path(strconv.Itoa(pid), "io"))
+	if err != nil {
+		return -1, -1, err
+	}
+	readBytes, err := strconv.ParseInt(values["read_bytes"], 10, 64)
+	if err != nil {
+		return -1, -1, err
+	}
+	writeBytes, err := strconv.ParseInt(values["write_bytes"], 10, 64)
+	if err != nil {
+		return -1, -1, err
+	}
+	return readBytes, writeBytes, nil
+}
+
+// Uptime returns the seconds since the boot
+func (fs FS) Uptime() (float64, error) {
+	out, err := ioutil.ReadFile(fs.path("uptime"))
+	if err != nil {
+		return 0, err
+	}
+	fields := strings.Fields(string(out))
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("invalid uptime %q", out)
+	}
+	return strconv.ParseFloat(fields[0], 64)
+}
+
+// MemTotal returns the physical memory of the machine in bytes
+func (fs FS) MemTotal() (uint64, error) {
+	values, err := readKeyValues(fs.path("meminfo"))
+	if err != nil {
+		return 0, err
+	}
+	// the sizes are in kB, like 'MemTotal:       16303428 kB'
+	kb, err := strconv.ParseUint(strings.TrimSuffix(values["MemTotal"], " kB"), 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return kb * 1024, nil
+}
+
+// readKeyValues reads a file of '<key>: <value>' lines
+func readKeyValues(path string) (map[string]string, error) {
+	out, err := ioutil.ReadFile(path) // #nosec
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		parts := strings.SplitN(scanner.Text(), ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		values[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+	return values, scanner.Err()
+}
@@ -0,0 +1,71 @@
+package proc
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFind(t *testing.T) {
+	fs := NewFS("testdata/proc")
+	procs, err := fs.Find(func(args []string) bool {
+		name := filepath.Base(args[0])
+		return name == "glusterd" || name == "glusterfsd"
+	})
+	if err != nil {
+		t.Fatalf("Find failed: %s", err)
+	}
+
+	pageSize := uint64(os.Getpagesize())
+	expected := []Process{
+		{
+			// the command name of the stat can contain spaces and
+			// parentheses, the io and the fds are not readable
+			PID:                     1523,
+			Args:                    []string{"/usr/sbin/glusterd", "-p", "/var/run/glusterd.pid", "--log-level", "INFO"},
+			CPUSeconds:              23.5,
+			StartTime:               11.01,
+			VirtualMemory:           612417536,
+			ResidentMemory:          3012 * pageSize,
+			Threads:                 9,
+			VoluntaryCtxSwitches:    52101,
+			NonVoluntaryCtxSwitches: 77,
+			ReadBytes:               -1,
+			WriteBytes:              -1,
+			OpenFds:                 -1,
+		},
+		{
+			PID: 2144,
+			Args: []string{"/usr/sbin/glusterfsd", "-s", "server1", "--volfile-id", "rep3.server1.bricks-rep3",
+				"-p", "/var/run/gluster/vols/rep3/server1-bricks-rep3.pid", "--brick-name", "/bricks/rep3",
+				"-l", "/var/log/glusterfs/bricks/bricks-rep3.log", "--xlator-option",
+				"*-posix.glusterd-uuid=8f0ec4a2-2c44-4a8e-9a5b-1f4e5a9b0c01", "--brick-port", "49152"},
+			CPUSeconds:              1157.32,
+			StartTime:               12033.11,
+			VirtualMemory:           1363423232,
+			ResidentMemory:          5421 * pageSize,
+			Threads:                 19,
+			VoluntaryCtxSwitches:    1250312,
+			NonVoluntaryCtxSwitches: 3021,
+			ReadBytes:               10485760000,
+			WriteBytes:              21474836480,
+			OpenFds:                 5,
+		},
+	}
+	if !reflect.DeepEqual(procs, expected) {
+		t.Errorf("expected %+v, got %+v", expected, procs)
+	}
+}
+
+func TestSystemInfo(t *testing.T) {
+	fs := NewFS("testdata/proc")
+	uptime, err := fs.Uptime()
+	if err != nil || uptime != 352011.63 {
+		t.Errorf("expected the uptime 352011.63, got %v (%v)", uptime, err)
+	}
+	memTotal, err := fs.MemTotal()
+	if err != nil || memTotal != 8008676*1024 {
+		t.Errorf("expected the total memory %d, got %d (%v)", 8008676*1024, memTotal, err)
+	}
+}
@@ -0,0 +1,230 @@
+// Package statedump parses the statedumps of the gluster processes, written
+// on SIGUSR1 or by 'gluster volume statedump', like the memory accounting
+// of the translators and the mempools
+package statedump
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// recordSeparator separates the records of a section, like
+	// the pools of the 'mempool' section
+	recordSeparator = "-----=-----"
+	// endMarker is the last line of a complete statedump
+	endMarker = "DUMP-END-TIME:"
+	// mempoolSection is the section listing the mempools
+	mempoolSection = "mempool"
+)
+
+// memUsageRE matches the memory accounting sections of the translators,
+// '[<xlator type>.<xlator name> - usage-type <type> memusage]'
+var memUsageRE = regexp.MustCompile(`^(.+) - usage-type (\S+) memusage$`)
+
+// Section is a section of the statedump, a '[<name>]' header followed
+// by 'key=value' lines. The records of a section, separated by
+// '-----=-----', are returned as sections with the same name
+type Section struct {
+	Name   string
+	Fields map[string]string
+}
+
+// Dump is a parsed statedump
+type Dump struct {
+	Sections []Section
+	// Complete is false if the statedump is still being written,
+	// or the process died while writing it
+	Complete bool
+}
+
+// MemUsage is the memory accounted by a translator for a memory type
+type MemUsage struct {
+	XlatorType   string
+	Xlator       string
+	Type         string
+	Size         uint64
+	NumAllocs    uint64
+	MaxSize      uint64
+	MaxNumAllocs uint64
+	TotalAllocs  uint64
+}
+
+// Mempool describes a mempool. The older gluster versions report the hot
+// and the cold objects of the pool, the recent versions only the active
+// objects, reported as HotCount
+type Mempool struct {
+	Name       string
+	HotCount   uint64
+	ColdCount  uint64
+	PaddedSize uint64
+	AllocCount uint64
+	MaxAlloc   uint64
+	Misses     uint64
+}
+
+// Parse parses the statedump
+func Parse(r io.Reader) (*Dump, error) {
+	dump := &Dump{}
+	var section *Section
+	addSection := func(name string) {
+		dump.Sections = append(dump.Sections, Section{Name: name, Fields: make(map[string]string)})
+		section = &dump.Sections[len(dump.Sections)-1]
+	}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, endMarker):
+			dump.Complete = true
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			addSection(strings.TrimSpace(line[1 : len(line)-1]))
+		case line == recordSeparator:
+			if section == nil {
+				continue
+			}
+			// the first record follows the header directly
+			if len(section.Fields) > 0 {
+				addSection(section.Name)
+			}
+		case section != nil:
+			parts := strings.SplitN(line, "=", 2)
+			if len(parts) != 2 {
+				continue
+			}
+			section.Fields[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return dump, nil
+}
+
+// ParseFile parses the statedump file
+func ParseFile(path string) (*Dump, error) {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	return Parse(f)
+}
+
+func parseUint(fields map[string]string, keys ...string) uint64 {
+	for _, key := range keys {
+		if value, ok := fields[key]; ok {
+			num, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				return 0
+			}
+			return num
+		}
+	}
+	return 0
+}
+
+// MemUsage returns the memory accounted by the translators, the memory
+// accounted by the process outside the translators has the 'global'
+// XlatorType. The memory accounting is only dumped if enabled
+func (d *Dump) MemUsage() []MemUsage {
+	var usage []MemUsage
+	for _, section := range d.Sections {
+		matches := memUsageRE.FindStringSubmatch(section.Name)
+		if matches == nil {
+			continue
+		}
+		// '<xlator type>.<xlator name>', the type has no '.'
+		xlatorType, xlator := "", matches[1]
+		if parts := strings.SplitN(matches[1], ".", 2); len(parts) == 2 {
+			xlatorType, xlator = parts[0], parts[1]
+		}
+		usage = append(usage, MemUsage{
+			XlatorType:   xlatorType,
+			Xlator:       xlator,
+			Type:         matches[2],
+			Size:         parseUint(section.Fields, "size"),
+			NumAllocs:    parseUint(section.Fields, "num_allocs"),
+			MaxSize:      parseUint(section.Fields, "max_size"),
+			MaxNumAllocs: parseUint(section.Fields, "max_num_allocs"),
+			TotalAllocs:  parseUint(section.Fields, "total_allocs"),
+		})
+	}
+	return usage
+}
+
+// Mempools returns the mempools of the process
+func (d *Dump) Mempools() []Mempool {
+	var pools []Mempool
+	for _, section := range d.Sections {
+		if section.Name != mempoolSection {
+			continue
+		}
+		name, ok := section.Fields["pool-name"]
+		if !ok {
+			continue
+		}
+		pools = append(pools, Mempool{
+			Name:       name,
+			HotCount:   parseUint(section.Fields, "hot-count", "active-count"),
+			ColdCount:  parseUint(section.Fields, "cold-count"),
+			PaddedSize: parseUint(section.Fields, "padded_sizeof", "padded-sizeof"),
+			AllocCount: parseUint(section.Fields, "alloc-count"),
+			MaxAlloc:   parseUint(section.Fields, "max-alloc"),
+			Misses:     parseUint(section.Fields, "pool-misses"),
+		})
+	}
+	return pools
+}
+
+// BrickPrefix returns the prefix of the statedump files of the brick process,
+// '<brick path with '/' replaced by '-'>.<pid>.dump.<timestamp>'
+func BrickPrefix(brickPath string, pid int) string {
+	name := strings.Replace(strings.TrimPrefix(brickPath, "/"), "/", "-", -1)
+	return fmt.Sprintf("%s.%d.dump.", name, pid)
+}
+
+// Latest returns the path of the newest statedump in the directory
+// with the prefix, by the timestamp suffix of the file name, and
+// os.ErrNotExist if there is none
+func Latest(dir string, prefix string) (string, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	type dumpFile struct {
+		path      string
+		timestamp int64
+	}
+	var files []dumpFile
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasPrefix(entry.Name(), prefix) {
+			continue
+		}
+		timestamp, err := strconv.ParseInt(strings.TrimPrefix(entry.Name(), prefix), 10, 64)
+		if err != nil {
+			continue
+		}
+		files = append(files, dumpFile{path: filepath.Join(dir, entry.Name()), timestamp: timestamp})
+	}
+	if len(files) == 0 {
+		return "", os.ErrNotExist
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].timestamp > files[j].timestamp
+	})
+	return files[0].path, nil
+}
@@ -0,0 +1,77 @@
+package statedump
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseFile(t *testing.T) {
+	dump, err := ParseFile("testdata/dumps/bricks-rep3.4321.dump.1791782067")
+	if err != nil {
+		t.Fatalf("ParseFile failed: %s", err)
+	}
+	if !dump.Complete {
+		t.Errorf("expected a complete statedump")
+	}
+
+	expectedUsage := []MemUsage{
+		{XlatorType: "global", Xlator: "glusterfs", Type: "gf_common_mt_dnscache6",
+			Size: 16, NumAllocs: 1, MaxSize: 16, MaxNumAllocs: 1, TotalAllocs: 1},
+		{XlatorType: "global", Xlator: "glusterfs", Type: "gf_common_mt_inode_ctx",
+			Size: 61440, NumAllocs: 60, MaxSize: 122880, MaxNumAllocs: 120, TotalAllocs: 5230},
+		{XlatorType: "storage/posix", Xlator: "rep3-posix", Type: "gf_posix_mt_char",
+			Size: 2048, NumAllocs: 8, MaxSize: 4096, MaxNumAllocs: 16, TotalAllocs: 720},
+		{XlatorType: "storage/posix", Xlator: "rep3-posix", Type: "gf_posix_mt_trash_path",
+			Size: 36, NumAllocs: 1, MaxSize: 36, MaxNumAllocs: 1, TotalAllocs: 1},
+		{XlatorType: "protocol/server", Xlator: "rep3-server", Type: "gf_server_mt_state_t",
+			Size: 10240, NumAllocs: 10, MaxSize: 204800, MaxNumAllocs: 200, TotalAllocs: 189312},
+	}
+	if usage := dump.MemUsage(); !reflect.DeepEqual(usage, expectedUsage) {
+		t.Errorf("expected %+v, got %+v", expectedUsage, usage)
+	}
+
+	expectedPools := []Mempool{
+		{Name: "rep3-server:fd_t", HotCount: 12, ColdCount: 1012, PaddedSize: 108,
+			AllocCount: 4521, MaxAlloc: 37},
+		{Name: "rep3-server:dentry_t", HotCount: 3066, ColdCount: 13318, PaddedSize: 84,
+			AllocCount: 19211, MaxAlloc: 3066, Misses: 2},
+	}
+	if pools := dump.Mempools(); !reflect.DeepEqual(pools, expectedPools) {
+		t.Errorf("expected %+v, got %+v", expectedPools, pools)
+	}
+}
+
+func TestParseFileIncomplete(t *testing.T) {
+	// the recent gluster versions only report the active objects
+	dump, err := ParseFile("testdata/dumps/bricks-rep3.4321.dump.1791781967")
+	if err != nil {
+		t.Fatalf("ParseFile failed: %s", err)
+	}
+	if dump.Complete {
+		t.Errorf("expected an incomplete statedump")
+	}
+	expected := []Mempool{{Name: "glusterfs:dict_t", HotCount: 82, PaddedSize: 256}}
+	if pools := dump.Mempools(); !reflect.DeepEqual(pools, expected) {
+		t.Errorf("expected %+v, got %+v", expected, pools)
+	}
+}
+
+func TestLatest(t *testing.T) {
+	prefix := BrickPrefix("/bricks/rep3", 4321)
+	if prefix != "bricks-rep3.4321.dump." {
+		t.Errorf("unexpected prefix %q", prefix)
+	}
+	path, err := Latest("testdata/dumps", prefix)
+	if err != nil {
+		t.Fatalf("Latest failed: %s", err)
+	}
+	if expected := filepath.Join("testdata/dumps", "bricks-rep3.4321.dump.1791782067"); path != expected {
+		t.Errorf("expected %s, got %s", expected, path)
+	}
+
+	if _, err := Latest("testdata/dumps", BrickPrefix("/bricks/rep3", 999)); !os.IsNotExist(err) {
+		t.Errorf("expected not exist error, got %v", err)
+	}
+}
@@ -0,0 +1,277 @@
+// Package webconfig secures the http server of the exporter with TLS and
+// basic authentication, configured by a 'web-config.yml' file in the
+// format of the Prometheus exporter-toolkit
+package webconfig
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/crypto/bcrypt"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// maxAuthCacheSize is the max no of the verified credentials cached, the
+// bcrypt hashes are slow to compare on purpose, and a scrape every few
+// seconds would cost a comparison each
+const maxAuthCacheSize = 100
+
+var (
+	tlsVersions = map[string]uint16{
+		"TLS10": tls.VersionTLS10,
+		"TLS11": tls.VersionTLS11,
+		"TLS12": tls.VersionTLS12,
+		"TLS13": tls.VersionTLS13,
+	}
+
+	clientAuthTypes = map[string]tls.ClientAuthType{
+		"":                           tls.NoClientCert,
+		"NoClientCert":               tls.NoClientCert,
+		"RequestClientCert":          tls.RequestClientCert,
+		"RequireAnyClientCert":       tls.RequireAnyClientCert,
+		"VerifyClientCertIfGiven":    tls.VerifyClientCertIfGiven,
+		"RequireAndVerifyClientCert": tls.RequireAndVerifyClientCert,
+	}
+
+	// cipherSuites are the cipher suites which can be configured, the
+	// TLS 1.3 cipher suites are not configurable
+	cipherSuites = map[string]uint16{
+		"TLS_RSA_WITH_AES_128_CBC_SHA":                  tls.TLS_RSA_WITH_AES_128_CBC_SHA,
+		"TLS_RSA_WITH_AES_256_CBC_SHA":                  tls.TLS_RSA_WITH_AES_256_CBC_SHA,
+		"TLS_RSA_WITH_AES_128_GCM_SHA256":               tls.TLS_RSA_WITH_AES_128_GCM_SHA256,
+		"TLS_RSA_WITH_AES_256_GCM_SHA384":               tls.TLS_RSA_WITH_AES_256_GCM_SHA384,
+		"TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA":          tls.TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA,
+		"TLS_ECDHE_ECDSA_WITH_AES_256_CBC_SHA":          tls.TLS_ECDHE_ECDSA_WITH_AES_256_CBC_SHA,
+		"TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA":            tls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA,
+		"TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA":            tls.TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA,
+		"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256":         tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+		"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384":         tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+		"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256":       tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+		"TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384":       tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+		"TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256":   tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305,
+		"TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256": tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305,
+	}
+
+	// dummyHash is compared for the unknown users, so that the time of
+	// the response does not tell if the user exists
+	dummyHash = []byte("$2a$10$2En/OhUnE5NRA49.oQQSCeI.8vZBFESy2J9T444iaUI7aftvvZXDG")
+)
+
+// TLSConfig is the 'tls_server_config' of the web configuration
+type TLSConfig struct {
+	CertFile                 string   `yaml:"cert_file"`
+	KeyFile                  string   `yaml:"key_file"`
+	ClientAuth               string   `yaml:"client_auth_type"`
+	ClientCAFile             string   `yaml:"client_ca_file"`
+	MinVersion               string   `yaml:"min_version"`
+	MaxVersion               string   `yaml:"max_version"`
+	CipherSuites             []string `yaml:"cipher_suites"`
+	PreferServerCipherSuites *bool    `yaml:"prefer_server_cipher_suites"`
+}
+
+// HTTPConfig is the 'http_server_config' of the web configuration
+type HTTPConfig struct {
+	HTTP2   *bool             `yaml:"http2"`
+	Headers map[string]string `yaml:"headers"`
+}
+
+// Config is the web configuration
+type Config struct {
+	TLSConfig  TLSConfig         `yaml:"tls_server_config"`
+	HTTPConfig HTTPConfig        `yaml:"http_server_config"`
+	Users      map[string]string `yaml:"basic_auth_users"`
+
+	authCache     map[[sha256.Size]byte]bool
+	authCacheLock sync.Mutex
+}
+
+// Load loads the web configuration file, the relative paths of the
+// files in the configuration are relative to its directory
+func Load(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	config := &Config{}
+	if err := yaml.UnmarshalStrict(data, config); err != nil {
+		return nil, err
+	}
+	dir := filepath.Dir(path)
+	for _, file := range []*string{&config.TLSConfig.CertFile, &config.TLSConfig.KeyFile, &config.TLSConfig.ClientCAFile} {
+		if *file != "" && !filepath.IsAbs(*file) {
+			*file = filepath.Join(dir, *file)
+		}
+	}
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+func (c *Config) validate() error {
+	tlsConfig := c.TLSConfig
+	if (tlsConfig.CertFile == "") != (tlsConfig.KeyFile == "") {
+		return errors.New("both cert_file and key_file are needed for TLS")
+	}
+	if !c.TLSEnabled() && (tlsConfig.ClientCAFile != "" || tlsConfig.ClientAuth != "") {
+		return errors.New("client authentication needs TLS, cert_file and key_file are missing")
+	}
+	clientAuth, ok := clientAuthTypes[tlsConfig.ClientAuth]
+	if !ok {
+		return fmt.Errorf("invalid client_auth_type %q", tlsConfig.ClientAuth)
+	}
+	if tlsConfig.ClientCAFile != "" && clientAuth == tls.NoClientCert {
+		return errors.New("client_ca_file is set, but client_auth_type is not")
+	}
+	if tlsConfig.ClientCAFile == "" &&
+		(clientAuth == tls.VerifyClientCertIfGiven || clientAuth == tls.RequireAndVerifyClientCert) {
+		return fmt.Errorf("client_auth_type %q needs client_ca_file", tlsConfig.ClientAuth)
+	}
+	for _, version := range []string{tlsConfig.MinVersion, tlsConfig.MaxVersion} {
+		if _, ok := tlsVersions[version]; version != "" && !ok {
+			return fmt.Errorf("invalid TLS version %q", version)
+		}
+	}
+	for _, cipher := range tlsConfig.CipherSuites {
+		if _, ok := cipherSuites[cipher]; !ok {
+			return fmt.Errorf("invalid cipher suite %q", cipher)
+		}
+	}
+	for user, hash := range c.Users {
+		if _, err := bcrypt.Cost([]byte(hash)); err != nil {
+			return fmt.Errorf("invalid bcrypt hash of the user %q: %s", user, err)
+		}
+	}
+	return nil
+}
+
+// TLSEnabled returns true if the server needs to serve TLS
+func (c *Config) TLSEnabled() bool {
+	return c.TLSConfig.CertFile != ""
+}
+
+// ServerTLSConfig returns the TLS configuration of the server, nil
+// if TLS is not enabled
+func (c *Config) ServerTLSConfig() (*tls.Config, error) {
+	if !c.TLSEnabled() {
+		return nil, nil
+	}
+	cert, err := tls.LoadX509KeyPair(c.TLSConfig.CertFile, c.TLSConfig.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	// the default minimum version of the exporter-toolkit
+	minVersion := uint16(tls.VersionTLS12)
+	if c.TLSConfig.MinVersion != "" {
+		minVersion = tlsVersions[c.TLSConfig.MinVersion]
+	}
+	config := &tls.Config{
+		Certificates:             []tls.Certificate{cert},
+		ClientAuth:               clientAuthTypes[c.TLSConfig.ClientAuth],
+		MinVersion:               minVersion,
+		MaxVersion:               tlsVersions[c.TLSConfig.MaxVersion],
+		PreferServerCipherSuites: true,
+	}
+	if c.TLSConfig.PreferServerCipherSuites != nil {
+		config.PreferServerCipherSuites = *c.TLSConfig.PreferServerCipherSuites
+	}
+	for _, cipher := range c.TLSConfig.CipherSuites {
+		config.CipherSuites = append(config.CipherSuites, cipherSuites[cipher])
+	}
+	if c.TLSConfig.ClientCAFile != "" {
+		data, err := ioutil.ReadFile(c.TLSConfig.ClientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no certificates found in %s", c.TLSConfig.ClientCAFile)
+		}
+		config.ClientCAs = pool
+	}
+	return config, nil
+}
+
+// NewServer returns the server listening on the address, with
+// the TLS configuration of the server if TLS is enabled
+func (c *Config) NewServer(addr string, handler http.Handler) (*http.Server, error) {
+	tlsConfig, err := c.ServerTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	server := &http.Server{
+		Addr:      addr,
+		Handler:   handler,
+		TLSConfig: tlsConfig,
+	}
+	if c.HTTPConfig.HTTP2 != nil && !*c.HTTPConfig.HTTP2 {
+		// a non-nil empty map disables HTTP/2
+		server.TLSNextProto = make(map[string]func(*http.Server, *tls.Conn, http.Handler))
+	}
+	return server, nil
+}
+
+// ListenAndServe serves the server, with TLS if enabled
+func ListenAndServe(server *http.Server) error {
+	if server.TLSConfig != nil {
+		// the certificates are already in the TLS configuration
+		return server.ListenAndServeTLS("", "")
+	}
+	return server.ListenAndServe()
+}
+
+// authenticated returns true if the password matches the bcrypt hash of
+// the user, the verified credentials are cached
+func (c *Config) authenticated(user, password string) bool {
+	hash, ok := c.Users[user]
+	key := sha256.Sum256([]byte(user + "\x00" + password + "\x00" + hash))
+
+	c.authCacheLock.Lock()
+	cached := c.authCache[key]
+	c.authCacheLock.Unlock()
+	if cached && ok {
+		return true
+	}
+
+	if !ok {
+		_ = bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+		return false
+	}
+	if bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) != nil {
+		return false
+	}
+	c.authCacheLock.Lock()
+	defer c.authCacheLock.Unlock()
+	if c.authCache == nil || len(c.authCache) >= maxAuthCacheSize {
+		c.authCache = make(map[[sha256.Size]byte]bool)
+	}
+	c.authCache[key] = true
+	return true
+}
+
+// Handler returns the handler adding the configured headers to the
+// responses, and asking for the basic authentication if users are
+// configured
+func (c *Config) Handler(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for name, value := range c.HTTPConfig.Headers {
+			w.Header().Set(name, value)
+		}
+		if len(c.Users) > 0 {
+			user, password, ok := r.BasicAuth()
+			if !ok || !c.authenticated(user, password) {
+				w.Header().Set("WWW-Authenticate", `Basic realm="gluster-exporter"`)
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
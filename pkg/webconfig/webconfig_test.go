@@ -0,0 +1,123 @@
+package webconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadInvalid(t *testing.T) {
+	for _, name := range []string{
+		"invalid-key.yml",
+		"no-key.yml",
+		"no-client-ca.yml",
+		"invalid-version.yml",
+		"invalid-hash.yml",
+		"missing.yml",
+	} {
+		if _, err := Load(filepath.Join("testdata", name)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func newTestClient(t *testing.T, withCert bool) *http.Client {
+	caData, err := ioutil.ReadFile("testdata/ca.crt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := x509.NewCertPool()
+	pool.AppendCertsFromPEM(caData)
+	tlsConfig := &tls.Config{RootCAs: pool, ServerName: "localhost"}
+	if withCert {
+		cert, err := tls.LoadX509KeyPair("testdata/client.crt", "testdata/client.key")
+		if err != nil {
+			t.Fatal(err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+}
+
+func TestServer(t *testing.T) {
+	config, err := Load("testdata/web-config.yml")
+	if err != nil {
+		t.Fatalf("Load failed: %s", err)
+	}
+	if !config.TLSEnabled() {
+		t.Fatal("expected TLS to be enabled")
+	}
+	handler := config.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("metrics"))
+	}))
+	server, err := config.NewServer("127.0.0.1:0", handler)
+	if err != nil {
+		t.Fatalf("NewServer failed: %s", err)
+	}
+
+	ts := httptest.NewUnstartedServer(handler)
+	ts.TLS = server.TLSConfig
+	ts.StartTLS()
+	defer ts.Close()
+
+	// the client certificate is required
+	if _, err := newTestClient(t, false).Get(ts.URL); err == nil {
+		t.Errorf("expected the request without a client certificate to fail")
+	}
+
+	client := newTestClient(t, true)
+	tests := []struct {
+		name     string
+		user     string
+		password string
+		status   int
+	}{
+		{name: "no credentials", status: http.StatusUnauthorized},
+		{name: "wrong password", user: "prometheus", password: "other", status: http.StatusUnauthorized},
+		{name: "unknown user", user: "other", password: "gluster-exporter", status: http.StatusUnauthorized},
+		{name: "valid", user: "prometheus", password: "gluster-exporter", status: http.StatusOK},
+		// served from the cache of the verified credentials
+		{name: "valid again", user: "prometheus", password: "gluster-exporter", status: http.StatusOK},
+	}
+	for _, tt := range tests {
+		req, err := http.NewRequest(http.MethodGet, ts.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tt.user != "" {
+			req.SetBasicAuth(tt.user, tt.password)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("%s: request failed: %s", tt.name, err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != tt.status {
+			t.Errorf("%s: expected status %d, got %d", tt.name, tt.status, resp.StatusCode)
+		}
+		if header := resp.Header.Get("X-Content-Type-Options"); header != "nosniff" {
+			t.Errorf("%s: expected the configured header, got %q", tt.name, header)
+		}
+	}
+}
+
+func TestNoConfig(t *testing.T) {
+	config := &Config{}
+	server, err := config.NewServer(":0", nil)
+	if err != nil {
+		t.Fatalf("NewServer failed: %s", err)
+	}
+	if server.TLSConfig != nil {
+		t.Errorf("expected no TLS configuration")
+	}
+
+	rec := httptest.NewRecorder()
+	config.Handler(http.NotFoundHandler()).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected the request to pass through, got %d", rec.Code)
+	}
+}
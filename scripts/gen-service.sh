@@ -11,6 +11,7 @@ Description=Gluster Prometheus Exporter
 
 [Service]
 ExecStart=${SBINDIR}/gluster-exporter --config=${SYSCONFDIR}/gluster-exporter/gluster-exporter.toml
+ExecReload=/bin/kill -HUP \$MAINPID
 KillMode=process
 
 [Install]